@@ -8,6 +8,7 @@ require (
 	github.com/axrona/go-discordrpc v1.1.0-1
 	github.com/goccy/go-json v0.10.5
 	github.com/google/go-github/v30 v30.1.0
+	github.com/google/uuid v1.6.0
 	github.com/joho/godotenv v1.5.1
 	github.com/mouuff/go-rocket-update v1.5.6
 	github.com/wailsapp/wails/v3 v3.0.0-alpha.77
@@ -32,7 +33,6 @@ require (
 	github.com/godbus/dbus/v5 v5.2.2 // indirect
 	github.com/golang/groupcache v0.0.0-20241129210726-2c02b8208cf8 // indirect
 	github.com/google/go-querystring v1.0.0 // indirect
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/jbenet/go-context v0.0.0-20150711004518-d14ea06fba99 // indirect
 	github.com/jchv/go-winloader v0.0.0-20250406163304-c1995be93bd1 // indirect
 	github.com/kevinburke/ssh_config v1.4.0 // indirect
@@ -112,3 +112,14 @@ func MinecraftData(isPreview bool) string {
 	}
 	return filepath.Join(AppData(), suffix)
 }
+
+func LibraryDir() (string, error) {
+	base := BaseRoot()
+	dir := filepath.Join(base, "library")
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if mkErr := os.MkdirAll(dir, 0o755); mkErr != nil {
+			return "", mkErr
+		}
+	}
+	return dir, nil
+}
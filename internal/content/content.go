@@ -1097,6 +1097,37 @@ func DecodeLevelDat(worldDir string) (map[string]any, int32, error) {
 	return root, version, nil
 }
 
+// LastOpenedVersion returns the lastOpenedWithVersion of a decoded level.dat as a dotted version, or an
+// empty string when the world does not record it.
+func LastOpenedVersion(root map[string]any) string {
+	return levelDatVersion(root, "lastOpenedWithVersion")
+}
+
+// MinimumClientVersion returns the MinimumCompatibleClientVersion of a decoded level.dat as a dotted
+// version, or an empty string when the world does not record it.
+func MinimumClientVersion(root map[string]any) string {
+	return levelDatVersion(root, "MinimumCompatibleClientVersion")
+}
+
+func levelDatVersion(root map[string]any, key string) string {
+	data := root
+	if v, ok := root["Data"].(map[string]any); ok {
+		data = v
+	}
+	var nums []string
+	switch parts := data[key].(type) {
+	case []int32:
+		for _, p := range parts {
+			nums = append(nums, strconv.Itoa(int(p)))
+		}
+	case []any:
+		for _, p := range parts {
+			nums = append(nums, fmt.Sprint(p))
+		}
+	}
+	return strings.Join(nums, ".")
+}
+
 func EncodeLevelDat(worldDir string, version int32, root map[string]any) error {
 	data, err := nbt.MarshalEncoding(root, nbt.LittleEndian)
 	if err != nil {
@@ -1122,6 +1153,12 @@ func ReadLevelDatFields(worldDir string) ([]types.LevelDatField, int32, error) {
 	if err != nil {
 		return nil, 0, err
 	}
+	return LevelDatFieldsOf(root), ver, nil
+}
+
+// LevelDatFieldsOf lists the top-level fields of a decoded level.dat root, the way ReadLevelDatFields
+// does for a world folder.
+func LevelDatFieldsOf(root map[string]any) []types.LevelDatField {
 	var data map[string]any
 	data = root
 	inData := false
@@ -1238,7 +1275,7 @@ func ReadLevelDatFields(worldDir string) ([]types.LevelDatField, int32, error) {
 		}
 		out = append(out, f)
 	}
-	return out, ver, nil
+	return out
 }
 
 func ReadLevelDatOrder(worldDir string) ([]string, int32, error) {
@@ -0,0 +1,27 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/nbt"
+)
+
+func TestLastOpenedVersion(t *testing.T) {
+	b, err := nbt.MarshalEncoding(map[string]any{"lastOpenedWithVersion": []int32{1, 21, 50, 7, 0}}, nbt.LittleEndian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var root map[string]any
+	if err := nbt.UnmarshalEncoding(b, &root, nbt.LittleEndian); err != nil {
+		t.Fatal(err)
+	}
+	if got := LastOpenedVersion(root); got != "1.21.50.7.0" {
+		t.Fatalf("LastOpenedVersion = %q", got)
+	}
+	if got := LastOpenedVersion(map[string]any{"Data": root}); got != "1.21.50.7.0" {
+		t.Fatalf("LastOpenedVersion under Data = %q", got)
+	}
+	if got := LastOpenedVersion(map[string]any{}); got != "" {
+		t.Fatalf("LastOpenedVersion of empty root = %q", got)
+	}
+}
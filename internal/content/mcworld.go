@@ -0,0 +1,86 @@
+package content
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/nbt"
+)
+
+// McworldInfo is what ReadMcworldInfo learns about a .mcworld archive without extracting it.
+type McworldInfo struct {
+	LevelName       string
+	LevelDat        map[string]any
+	LevelDatVersion int32
+}
+
+// ReadMcworldInfo decodes the level.dat and levelname.txt of the world in the archive at archivePath.
+// When the world is nested in a folder, the shallowest level.dat wins.
+func ReadMcworldInfo(archivePath string) (McworldInfo, error) {
+	var info McworldInfo
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return info, err
+	}
+	defer zr.Close()
+
+	var levelDat, levelName *zip.File
+	depth := -1
+	for _, f := range zr.File {
+		name := normalizeZipEntryName(f.Name)
+		if !strings.EqualFold(path.Base(name), "level.dat") {
+			continue
+		}
+		if d := strings.Count(name, "/"); depth < 0 || d < depth {
+			levelDat, depth = f, d
+		}
+	}
+	if levelDat == nil {
+		return info, io.ErrUnexpectedEOF
+	}
+	dir := path.Dir(normalizeZipEntryName(levelDat.Name))
+	for _, f := range zr.File {
+		name := normalizeZipEntryName(f.Name)
+		if path.Dir(name) == dir && strings.EqualFold(path.Base(name), "levelname.txt") {
+			levelName = f
+			break
+		}
+	}
+
+	b, err := readZipFile(levelDat)
+	if err != nil {
+		return info, err
+	}
+	if len(b) < 8 {
+		return info, io.ErrUnexpectedEOF
+	}
+	info.LevelDatVersion = int32(binary.LittleEndian.Uint32(b[:4]))
+	if err := nbt.UnmarshalEncoding(b[8:], &info.LevelDat, nbt.LittleEndian); err != nil {
+		return info, err
+	}
+	if levelName != nil {
+		if nb, err := readZipFile(levelName); err == nil {
+			info.LevelName = strings.TrimSpace(strings.SplitN(string(nb), "\n", 2)[0])
+		}
+	}
+	if info.LevelName == "" {
+		data := info.LevelDat
+		if v, ok := data["Data"].(map[string]any); ok {
+			data = v
+		}
+		info.LevelName, _ = data["LevelName"].(string)
+	}
+	return info, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,77 @@
+package content
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/nbt"
+)
+
+func writeTestMcworld(t *testing.T, p string, entries map[string][]byte) {
+	t.Helper()
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, data := range entries {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testLevelDat(t *testing.T, root map[string]any) []byte {
+	t.Helper()
+	body, err := nbt.MarshalEncoding(root, nbt.LittleEndian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := binary.LittleEndian.AppendUint32(nil, 10)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(body)))
+	return append(out, body...)
+}
+
+func TestReadMcworldInfo(t *testing.T) {
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "nested.mcworld")
+	writeTestMcworld(t, nested, map[string][]byte{
+		"My World/level.dat":           testLevelDat(t, map[string]any{"LevelName": "From Dat", "LastPlayed": int64(1700000000)}),
+		"My World/levelname.txt":       []byte("From Txt\n"),
+		"My World/sub/inner/level.dat": []byte("junk"),
+		"My World/db/CURRENT":          []byte("MANIFEST-000001\n"),
+	})
+	info, err := ReadMcworldInfo(nested)
+	if err != nil {
+		t.Fatalf("ReadMcworldInfo: %v", err)
+	}
+	if info.LevelName != "From Txt" || info.LevelDatVersion != 10 {
+		t.Fatalf("info = %+v", info)
+	}
+	if info.LevelDat["LastPlayed"] != int64(1700000000) {
+		t.Fatalf("LastPlayed = %v", info.LevelDat["LastPlayed"])
+	}
+
+	flat := filepath.Join(dir, "flat.mcworld")
+	writeTestMcworld(t, flat, map[string][]byte{
+		"level.dat": testLevelDat(t, map[string]any{"LevelName": "From Dat"}),
+	})
+	if info, err = ReadMcworldInfo(flat); err != nil || info.LevelName != "From Dat" {
+		t.Fatalf("flat: %+v %v", info, err)
+	}
+
+	empty := filepath.Join(dir, "empty.mcworld")
+	writeTestMcworld(t, empty, map[string][]byte{"readme.txt": []byte("x")})
+	if _, err := ReadMcworldInfo(empty); err == nil {
+		t.Fatal("expected an error for an archive without level.dat")
+	}
+}
@@ -0,0 +1,222 @@
+package content
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	json "github.com/goccy/go-json"
+	"github.com/google/uuid"
+
+	"github.com/liteldev/LeviLauncher/internal/packages"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+const (
+	SkinGeometryClassic = "geometry.humanoid.custom"
+	SkinGeometrySlim    = "geometry.humanoid.customSlim"
+)
+
+type skinsFile struct {
+	Skins            []skinEntry `json:"skins"`
+	SerializeName    string      `json:"serialize_name"`
+	LocalizationName string      `json:"localization_name"`
+}
+
+type skinEntry struct {
+	LocalizationName string `json:"localization_name"`
+	Geometry         string `json:"geometry"`
+	Texture          string `json:"texture"`
+	Type             string `json:"type"`
+}
+
+// ReadSkinPackFromDir parses skins.json of a skin pack and resolves display names from its texts folder.
+func ReadSkinPackFromDir(dir string) (types.SkinPackInfo, bool) {
+	info := types.SkinPackInfo{Skins: []types.SkinInfo{}}
+	target := findManifestDir(dir)
+	if target == "" {
+		return info, false
+	}
+	b, err := os.ReadFile(filepath.Join(target, "skins.json"))
+	if err != nil {
+		return info, false
+	}
+	var sf skinsFile
+	if err := json.Unmarshal(utils.JsonCompatBytes(b), &sf); err != nil {
+		return info, false
+	}
+	if mb, err := os.ReadFile(filepath.Join(target, "manifest.json")); err == nil {
+		var mf packages.RawManifest
+		if json.Unmarshal(utils.JsonCompatBytes(mb), &mf) == nil {
+			info.UUID = strings.TrimSpace(mf.Header.UUID)
+		}
+	}
+	pi := ReadPackInfoFromDir(target)
+	texts := packages.ReadPackTexts(target)
+	lookup := func(key string) string {
+		if texts == nil {
+			return ""
+		}
+		return strings.TrimSpace(texts[key])
+	}
+
+	info.Path = target
+	info.Version = pi.Version
+	info.SerializeName = strings.TrimSpace(sf.SerializeName)
+	info.LocalizationName = strings.TrimSpace(sf.LocalizationName)
+	info.Name = firstNonEmpty(lookup("skinpack."+info.LocalizationName), pi.Name, info.SerializeName, filepath.Base(target))
+	for _, s := range sf.Skins {
+		loc := strings.TrimSpace(s.LocalizationName)
+		skin := types.SkinInfo{
+			LocalizationName: loc,
+			DisplayName:      firstNonEmpty(lookup("skin."+info.LocalizationName+"."+loc), loc),
+			Geometry:         strings.TrimSpace(s.Geometry),
+			Texture:          strings.TrimSpace(s.Texture),
+			Type:             strings.TrimSpace(s.Type),
+		}
+		if skin.Texture != "" {
+			// The texture must stay inside the pack; skins.json is not trusted to point anywhere else.
+			p := filepath.Join(target, filepath.FromSlash(skin.Texture))
+			rel, err := filepath.Rel(target, p)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && utils.FileExists(p) {
+				skin.TexturePath = p
+			}
+		}
+		info.Skins = append(info.Skins, skin)
+	}
+	return info, true
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if s := strings.TrimSpace(v); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// validSkinTextureSize reports whether the game accepts a skin texture of the given size.
+func validSkinTextureSize(w int, h int) bool {
+	switch {
+	case w == 64 && (h == 32 || h == 64):
+		return true
+	case w == 128 && h == 128:
+		return true
+	}
+	return false
+}
+
+// skinSerializeName turns a display name into the identifier used for serialize_name and lang keys.
+func skinSerializeName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "CustomSkinPack"
+	}
+	return b.String()
+}
+
+// BuildSkinPack creates a skin pack folder inside skinDir from a set of PNG skins and returns its path.
+// The manifest gets fresh UUIDs, so building the same request twice yields two distinct packs.
+func BuildSkinPack(skinDir string, req types.SkinPackBuildRequest) (string, string) {
+	name := strings.TrimSpace(req.Name)
+	if strings.TrimSpace(skinDir) == "" {
+		return "", "ERR_NO_PLAYER"
+	}
+	if name == "" {
+		return "", "ERR_INVALID_NAME"
+	}
+	if len(req.Skins) == 0 {
+		return "", "ERR_NO_SKINS"
+	}
+	textures := make([][]byte, len(req.Skins))
+	for i, s := range req.Skins {
+		b, err := os.ReadFile(strings.TrimSpace(s.TexturePath))
+		if err != nil {
+			return "", "ERR_READ_FILE"
+		}
+		cfg, err := png.DecodeConfig(bytes.NewReader(b))
+		if err != nil || !validSkinTextureSize(cfg.Width, cfg.Height) {
+			return "", "ERR_INVALID_SKIN_TEXTURE"
+		}
+		textures[i] = b
+	}
+
+	serialize := skinSerializeName(name)
+	var lang strings.Builder
+	fmt.Fprintf(&lang, "skinpack.%s=%s\n", serialize, name)
+	sf := skinsFile{SerializeName: serialize, LocalizationName: serialize}
+	files := map[string][]byte{}
+	for i, s := range req.Skins {
+		loc := fmt.Sprintf("skin%d", i+1)
+		geometry := SkinGeometryClassic
+		if s.Slim {
+			geometry = SkinGeometrySlim
+		}
+		texture := loc + ".png"
+		sf.Skins = append(sf.Skins, skinEntry{LocalizationName: loc, Geometry: geometry, Texture: texture, Type: "free"})
+		display := strings.TrimSpace(s.Name)
+		if display == "" {
+			display = strings.TrimSuffix(filepath.Base(s.TexturePath), filepath.Ext(s.TexturePath))
+		}
+		fmt.Fprintf(&lang, "skin.%s.%s=%s\n", serialize, loc, display)
+		files[texture] = textures[i]
+	}
+
+	manifest := map[string]any{
+		"format_version": 1,
+		"header": map[string]any{
+			"name":    name,
+			"uuid":    uuid.NewString(),
+			"version": []int{1, 0, 0},
+		},
+		"modules": []map[string]any{{
+			"type":    "skin_pack",
+			"uuid":    uuid.NewString(),
+			"version": []int{1, 0, 0},
+		}},
+	}
+	mb, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", "ERR_WRITE_FILE"
+	}
+	sb, err := json.MarshalIndent(sf, "", "  ")
+	if err != nil {
+		return "", "ERR_WRITE_FILE"
+	}
+	files["manifest.json"] = mb
+	files["skins.json"] = sb
+	files["texts/en_US.lang"] = []byte(lang.String())
+	files["texts/languages.json"] = []byte(`["en_US"]`)
+
+	if err := os.MkdirAll(skinDir, 0o755); err != nil {
+		return "", "ERR_CREATE_TARGET_DIR"
+	}
+	folder := utils.SanitizeFilename(name)
+	target := filepath.Join(skinDir, folder)
+	for i := 2; utils.DirExists(target) || utils.FileExists(target); i++ {
+		target = filepath.Join(skinDir, fmt.Sprintf("%s (%d)", folder, i))
+	}
+	for rel, data := range files {
+		p := filepath.Join(target, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			_ = os.RemoveAll(target)
+			return "", "ERR_CREATE_TARGET_DIR"
+		}
+		if err := os.WriteFile(p, data, 0o644); err != nil {
+			_ = os.RemoveAll(target)
+			return "", "ERR_WRITE_FILE"
+		}
+	}
+	return target, ""
+}
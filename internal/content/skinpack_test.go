@@ -0,0 +1,111 @@
+package content
+
+import (
+	"encoding/json"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+func writeTestSkin(t *testing.T, dir string, name string, w int, h int) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatalf("create %s: %v", name, err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, image.NewNRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatalf("encode %s: %v", name, err)
+	}
+	return p
+}
+
+func TestBuildSkinPackRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	skinDir := filepath.Join(t.TempDir(), "skin_packs")
+	req := types.SkinPackBuildRequest{
+		Name: "My Skins",
+		Skins: []types.SkinPackBuildSkin{
+			{Name: "Steve Alt", TexturePath: writeTestSkin(t, src, "a.png", 64, 64)},
+			{TexturePath: writeTestSkin(t, src, "alex.png", 64, 64), Slim: true},
+		},
+	}
+
+	dir, code := BuildSkinPack(skinDir, req)
+	if code != "" {
+		t.Fatalf("build skin pack: %s", code)
+	}
+	info, ok := ReadSkinPackFromDir(dir)
+	if !ok {
+		t.Fatalf("read back skin pack from %s", dir)
+	}
+	if info.Name != "My Skins" || info.SerializeName != "MySkins" || info.UUID == "" || info.Version != "1.0.0" {
+		t.Fatalf("unexpected pack info: %+v", info)
+	}
+	if len(info.Skins) != 2 {
+		t.Fatalf("expected 2 skins, got %+v", info.Skins)
+	}
+	if info.Skins[0].DisplayName != "Steve Alt" || info.Skins[0].Geometry != SkinGeometryClassic || info.Skins[0].TexturePath == "" {
+		t.Fatalf("unexpected first skin: %+v", info.Skins[0])
+	}
+	if info.Skins[1].DisplayName != "alex" || info.Skins[1].Geometry != SkinGeometrySlim {
+		t.Fatalf("unexpected second skin: %+v", info.Skins[1])
+	}
+
+	again, code := BuildSkinPack(skinDir, req)
+	if code != "" || again == dir {
+		t.Fatalf("expected a second distinct pack, got %q (%s)", again, code)
+	}
+}
+
+func TestBuildSkinPackRejectsBadTexture(t *testing.T) {
+	src := t.TempDir()
+	req := types.SkinPackBuildRequest{
+		Name:  "Bad",
+		Skins: []types.SkinPackBuildSkin{{TexturePath: writeTestSkin(t, src, "wide.png", 100, 20)}},
+	}
+	if _, code := BuildSkinPack(t.TempDir(), req); code != "ERR_INVALID_SKIN_TEXTURE" {
+		t.Fatalf("expected ERR_INVALID_SKIN_TEXTURE, got %q", code)
+	}
+}
+
+func TestReadSkinPackIgnoresTextureOutsidePack(t *testing.T) {
+	src := t.TempDir()
+	skinDir := filepath.Join(t.TempDir(), "skin_packs")
+	dir, code := BuildSkinPack(skinDir, types.SkinPackBuildRequest{
+		Name:  "Escape",
+		Skins: []types.SkinPackBuildSkin{{TexturePath: writeTestSkin(t, src, "a.png", 64, 64)}},
+	})
+	if code != "" {
+		t.Fatalf("build skin pack: %s", code)
+	}
+	writeTestSkin(t, filepath.Dir(skinDir), "outside.png", 64, 64)
+	b, err := os.ReadFile(filepath.Join(dir, "skins.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sf map[string]any
+	if err := json.Unmarshal(b, &sf); err != nil {
+		t.Fatal(err)
+	}
+	sf["skins"].([]any)[0].(map[string]any)["texture"] = "../../outside.png"
+	if b, err = json.Marshal(sf); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "skins.json"), b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, ok := ReadSkinPackFromDir(dir)
+	if !ok || len(info.Skins) != 1 {
+		t.Fatalf("read skin pack: %+v", info)
+	}
+	if info.Skins[0].TexturePath != "" {
+		t.Fatalf("texture outside the pack was resolved to %q", info.Skins[0].TexturePath)
+	}
+}
@@ -0,0 +1,68 @@
+package content
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+// World compatibility statuses reported by CheckWorldCompat.
+const (
+	CompatOK           = "ok"
+	CompatUnknown      = "unknown"
+	CompatDowngrade    = "downgrade"
+	CompatIncompatible = "incompatible"
+)
+
+// CompareGameVersions orders dotted game versions numerically by their first n parts, or by every part
+// when n is 0 or less. Comparing releases passes 3: level.dat records five parts and versions record a
+// four-part build, but the world format and script APIs only change between releases, so builds of the
+// same release compare equal.
+func CompareGameVersions(a, b string, n int) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	if n <= 0 {
+		n = max(len(pa), len(pb))
+	}
+	for i := 0; i < n; i++ {
+		var na, nb int
+		if i < len(pa) {
+			na, _ = strconv.Atoi(strings.TrimSpace(pa[i]))
+		}
+		if i < len(pb) {
+			nb, _ = strconv.Atoi(strings.TrimSpace(pb[i]))
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// CheckWorldCompat compares the versions recorded in a decoded level.dat with the game version a world is
+// about to be opened in. A game older than MinimumCompatibleClientVersion is incompatible; one older than
+// lastOpenedWithVersion is a downgrade that may lose or corrupt newer content. The status is unknown when
+// either side has no version.
+func CheckWorldCompat(root map[string]any, gameVersion string) types.WorldCompatResult {
+	res := types.WorldCompatResult{
+		WorldVersion:     LastOpenedVersion(root),
+		MinClientVersion: MinimumClientVersion(root),
+		TargetVersion:    strings.TrimSpace(gameVersion),
+		Status:           CompatUnknown,
+	}
+	if res.TargetVersion == "" || (res.WorldVersion == "" && res.MinClientVersion == "") {
+		return res
+	}
+	switch {
+	case res.MinClientVersion != "" && CompareGameVersions(res.TargetVersion, res.MinClientVersion, 3) < 0:
+		res.Status = CompatIncompatible
+	case res.WorldVersion != "" && CompareGameVersions(res.TargetVersion, res.WorldVersion, 3) < 0:
+		res.Status = CompatDowngrade
+	default:
+		res.Status = CompatOK
+	}
+	return res
+}
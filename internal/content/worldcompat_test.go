@@ -0,0 +1,52 @@
+package content
+
+import "testing"
+
+func TestCheckWorldCompat(t *testing.T) {
+	root := map[string]any{
+		"lastOpenedWithVersion":          []int32{1, 21, 50, 7, 0},
+		"MinimumCompatibleClientVersion": []int32{1, 21, 0, 0, 0},
+	}
+	cases := []struct {
+		target string
+		want   string
+	}{
+		{"1.21.50.10", CompatOK},
+		{"1.21.60.24", CompatOK},
+		{"1.21.44.1", CompatDowngrade},
+		{"1.20.81.1", CompatIncompatible},
+		{"", CompatUnknown},
+	}
+	for _, c := range cases {
+		res := CheckWorldCompat(root, c.target)
+		if res.Status != c.want {
+			t.Errorf("CheckWorldCompat(%q) = %q, want %q", c.target, res.Status, c.want)
+		}
+	}
+	res := CheckWorldCompat(root, "1.21.50.7")
+	if res.WorldVersion != "1.21.50.7.0" || res.MinClientVersion != "1.21.0.0.0" {
+		t.Fatalf("res = %+v", res)
+	}
+	if got := CheckWorldCompat(map[string]any{}, "1.21.50.7").Status; got != CompatUnknown {
+		t.Fatalf("no versions: status = %q", got)
+	}
+}
+
+func TestCompareGameVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		n    int
+		want int
+	}{
+		{"1.21.80.3", "1.21.80.25", 3, 0},
+		{"1.21.80.3", "1.21.80.25", 0, -1},
+		{"1.21.100", "1.21.90.1.0", 3, 1},
+		{"1.21", "1.21.0", 0, 0},
+		{"1.20.80", "1.21.0", 3, -1},
+	}
+	for _, c := range cases {
+		if got := CompareGameVersions(c.a, c.b, c.n); got != c.want {
+			t.Errorf("CompareGameVersions(%q, %q, %d) = %d, want %d", c.a, c.b, c.n, got, c.want)
+		}
+	}
+}
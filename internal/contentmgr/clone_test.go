@@ -0,0 +1,128 @@
+package contentmgr
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/content"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+	"github.com/liteldev/LeviLauncher/internal/worlddb"
+)
+
+// writeEmptyWorldDB creates a LevelDB database with no tables: a manifest naming log 3 and an empty log.
+func writeEmptyWorldDB(t *testing.T, worldDir string) {
+	t.Helper()
+	dir := filepath.Join(worldDir, "db")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	var edit []byte
+	edit = binary.AppendUvarint(edit, 1)
+	edit = binary.AppendUvarint(edit, uint64(len("leveldb.BytewiseComparator")))
+	edit = append(edit, "leveldb.BytewiseComparator"...)
+	for _, kv := range [][2]uint64{{2, 3}, {3, 4}, {4, 0}} {
+		edit = binary.AppendUvarint(edit, kv[0])
+		edit = binary.AppendUvarint(edit, kv[1])
+	}
+	crcTable := crc32.MakeTable(crc32.Castagnoli)
+	sum := crc32.Update(crc32.Checksum([]byte{1}, crcTable), crcTable, edit)
+	rec := binary.LittleEndian.AppendUint32(nil, (sum>>15|sum<<17)+0xa282ead8)
+	rec = binary.LittleEndian.AppendUint16(rec, uint16(len(edit)))
+	rec = append(append(rec, 1), edit...)
+	for name, data := range map[string][]byte{"MANIFEST-000002": rec, "CURRENT": []byte("MANIFEST-000002\n"), "000003.log": nil} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func writeCloneSource(t *testing.T, base string) string {
+	t.Helper()
+	dir := writeTestWorld(t, base, "survival", "Survival")
+	root := map[string]any{"LevelName": "Survival", "RandomSeed": int64(42), "Time": int64(9000)}
+	if err := content.EncodeLevelDat(dir, 10, root); err != nil {
+		t.Fatal(err)
+	}
+	writeEmptyWorldDB(t, dir)
+	for _, key := range []string{"~local_player", "player_server_1"} {
+		if err := worlddb.PutNBT(dir, []byte(key), map[string]any{"Pos": []float32{0, 64, 0}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func worldPlayers(t *testing.T, dir string) []string {
+	t.Helper()
+	w, err := worlddb.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	players, err := w.Players()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return players
+}
+
+func TestCloneWorld(t *testing.T) {
+	m, bases := transferTestManager(t)
+	src := writeCloneSource(t, bases["src"])
+	req := types.WorldCloneRequest{
+		SourceVersionName: "src", SourcePlayer: "p", SourceWorldPath: src,
+		TargetVersionName: "dst", TargetPlayer: "p",
+		LevelName: "Creative Copy", NewSeed: true, ResetPlayers: true, ResetTime: true,
+	}
+	res := m.CloneWorld(req)
+	if res.ErrorCode != "" {
+		t.Fatalf("clone = %+v", res)
+	}
+	if res.FolderName != "survival" || res.Path != filepath.Join(bases["dst"], "Users", "p", "games", "com.mojang", "minecraftWorlds", "survival") {
+		t.Fatalf("folder = %q, path = %q", res.FolderName, res.Path)
+	}
+	root, _, err := content.DecodeLevelDat(res.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root["LevelName"] != "Creative Copy" || root["RandomSeed"] != res.Seed || res.Seed == 42 || root["Time"] != int64(0) {
+		t.Fatalf("level.dat = %+v, seed %d", root, res.Seed)
+	}
+	if b, _ := os.ReadFile(filepath.Join(res.Path, "levelname.txt")); string(b) != "Creative Copy" {
+		t.Fatalf("levelname.txt = %q", b)
+	}
+	if players := worldPlayers(t, res.Path); len(players) != 0 {
+		t.Fatalf("players left = %v", players)
+	}
+	if players := worldPlayers(t, src); len(players) != 2 {
+		t.Fatalf("source players = %v", players)
+	}
+
+	// Cloning again without options keeps the seed and players and picks a free folder name.
+	res = m.CloneWorld(types.WorldCloneRequest{SourceVersionName: "src", SourcePlayer: "p", SourceWorldPath: src, TargetVersionName: "dst", TargetPlayer: "p"})
+	if res.ErrorCode != "" || res.FolderName == "survival" || res.Seed != 42 || res.LevelName != "Survival Copy" {
+		t.Fatalf("second clone = %+v", res)
+	}
+	if players := worldPlayers(t, res.Path); len(players) != 2 {
+		t.Fatalf("players = %v", players)
+	}
+}
+
+func TestTransferWorldToVersion(t *testing.T) {
+	m, bases := transferTestManager(t)
+	src := writeTestWorld(t, bases["src"], "aaa", "Source A")
+	if code := m.TransferWorldToVersion("src", "p", src, "dst", "q"); code != "" {
+		t.Fatalf("transfer = %q", code)
+	}
+	target := filepath.Join(bases["dst"], "Users", "q", "games", "com.mojang", "minecraftWorlds", "aaa")
+	if !utils.FileExists(filepath.Join(target, "levelname.txt")) {
+		t.Fatal("world not copied into the target player's minecraftWorlds")
+	}
+	if code := m.TransferWorldToVersion("src", "p", filepath.Join(bases["src"], "elsewhere"), "dst", "q"); code != "ERR_INVALID_PATH" {
+		t.Fatalf("outside source = %q", code)
+	}
+}
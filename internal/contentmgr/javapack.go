@@ -0,0 +1,37 @@
+package contentmgr
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/content"
+	"github.com/liteldev/LeviLauncher/internal/javapack"
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+// ImportJavaResourcePackPath converts a Java resource pack archive into a Bedrock resource pack and
+// imports it into a version like an .mcpack. The converted pack is named after the archive and gets a
+// new UUID, so importing the same archive twice installs two packs.
+func (m *Manager) ImportJavaResourcePackPath(name string, path string) types.JavaPackConversion {
+	p := strings.TrimSpace(path)
+	b, err := os.ReadFile(p)
+	if p == "" || err != nil {
+		return types.JavaPackConversion{Untranslated: []types.JavaPackAsset{}, ErrorCode: "ERR_OPEN_ZIP"}
+	}
+	packName := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+	data, rep, err := javapack.Convert(b, packName)
+	switch {
+	case errors.Is(err, javapack.ErrNotJavaPack):
+		rep.ErrorCode = "ERR_NOT_JAVA_PACK"
+	case errors.Is(err, javapack.ErrInvalidMeta):
+		rep.ErrorCode = "ERR_INVALID_PACK_MCMETA"
+	case err != nil:
+		rep.ErrorCode = "ERR_OPEN_ZIP"
+	default:
+		roots := m.getContentRoots(name)
+		rep.ErrorCode = content.ImportMcpackToDirs2(data, packName+".mcpack", roots.ResourcePacks, roots.BehaviorPacks, "", false)
+	}
+	return rep
+}
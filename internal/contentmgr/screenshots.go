@@ -0,0 +1,290 @@
+package contentmgr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/apppath"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+const (
+	defaultThumbnailSize = 320
+	maxThumbnailSize     = 1024
+)
+
+type ScreenshotQuery struct {
+	World  string `json:"world"`
+	Oldest bool   `json:"oldest"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+}
+
+type ScreenshotGroup struct {
+	World string `json:"world"`
+	Count int    `json:"count"`
+}
+
+type ScreenshotPage struct {
+	Items  []ScreenshotInfo  `json:"items"`
+	Total  int               `json:"total"`
+	Groups []ScreenshotGroup `json:"groups"`
+}
+
+type ScreenshotBulkResult struct {
+	Done      int      `json:"done"`
+	Paths     []string `json:"paths"`
+	Failed    []string `json:"failed"`
+	ErrorCode string   `json:"errorCode"`
+}
+
+// readScreenshotSidecar reads the JSON the game writes next to a screenshot. Every build records the
+// capture time; the world is only known when the sidecar names it.
+func readScreenshotSidecar(jsonPath string) (int64, string) {
+	b, err := os.ReadFile(jsonPath)
+	if err != nil {
+		return 0, ""
+	}
+	var meta screenshotMeta
+	_ = json.Unmarshal(b, &meta)
+	var raw map[string]any
+	if json.Unmarshal(b, &raw) != nil {
+		return meta.CaptureTime, ""
+	}
+	for _, key := range []string{"worldName", "levelName", "worldId", "levelId"} {
+		for k, v := range raw {
+			if s, ok := v.(string); ok && strings.EqualFold(k, key) && strings.TrimSpace(s) != "" {
+				return meta.CaptureTime, strings.TrimSpace(s)
+			}
+		}
+	}
+	return meta.CaptureTime, ""
+}
+
+func (m *Manager) screenshotsRoot(versionName string, player string) string {
+	usersRoot := strings.TrimSpace(m.getContentRoots(versionName).UsersRoot)
+	p := strings.TrimSpace(player)
+	if usersRoot == "" || p == "" {
+		return ""
+	}
+	return filepath.Join(usersRoot, p, "games", "com.mojang", "Screenshots")
+}
+
+func screenshotSortTime(s ScreenshotInfo) int64 {
+	if s.CaptureTime > 0 {
+		return s.CaptureTime
+	}
+	return s.ModTime
+}
+
+// ListScreenshotsPage lists a page of screenshots sorted by capture time, newest first unless
+// query.Oldest is set, falling back to the file time for screenshots without a sidecar. Groups counts
+// every screenshot by world before the world filter applies; screenshots of an unknown world have an
+// empty World.
+func (m *Manager) ListScreenshotsPage(versionName string, player string, query ScreenshotQuery) ScreenshotPage {
+	page := ScreenshotPage{Items: []ScreenshotInfo{}, Groups: []ScreenshotGroup{}}
+	all := m.ListScreenshots(versionName, player)
+	counts := map[string]int{}
+	var matched []ScreenshotInfo
+	for _, s := range all {
+		counts[s.World]++
+		if query.World == "" || s.World == query.World {
+			matched = append(matched, s)
+		}
+	}
+	for w, n := range counts {
+		page.Groups = append(page.Groups, ScreenshotGroup{World: w, Count: n})
+	}
+	sort.Slice(page.Groups, func(i, j int) bool { return page.Groups[i].World < page.Groups[j].World })
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		ti, tj := screenshotSortTime(matched[i]), screenshotSortTime(matched[j])
+		if ti == tj {
+			return matched[i].Path < matched[j].Path
+		}
+		if query.Oldest {
+			return ti < tj
+		}
+		return ti > tj
+	})
+	page.Total = len(matched)
+	start := min(max(query.Offset, 0), len(matched))
+	end := len(matched)
+	if query.Limit > 0 {
+		end = min(start+query.Limit, end)
+	}
+	page.Items = append(page.Items, matched[start:end]...)
+	return page
+}
+
+// thumbnail scales img down so its longer side is at most size, averaging the source pixels under
+// each thumbnail pixel.
+func thumbnail(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return img
+	}
+	tw, th := size, h*size/w
+	if h > w {
+		tw, th = w*size/h, size
+	}
+	tw, th = max(tw, 1), max(th, 1)
+	out := image.NewNRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+max((y+1)*h/th, y*h/th+1)
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+max((x+1)*w/tw, x*w/tw+1)
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBAModel.Convert(img.At(sx, sy)).(color.NRGBA)
+					r, g, bl, a = r+uint64(c.R), g+uint64(c.G), bl+uint64(c.B), a+uint64(c.A)
+					n++
+				}
+			}
+			out.SetNRGBA(x, y, color.NRGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(bl / n), A: uint8(a / n)})
+		}
+	}
+	return out
+}
+
+// GetScreenshotThumbnail returns a data URL of a scaled-down copy of an image, at most size pixels on its
+// longer side. Thumbnails are cached keyed by path, modification time and size, so an edited or replaced
+// screenshot gets a new one.
+func (m *Manager) GetScreenshotThumbnail(path string, size int) string {
+	p := strings.TrimSpace(path)
+	fi, err := os.Stat(p)
+	if p == "" || err != nil || fi.IsDir() {
+		return ""
+	}
+	if size <= 0 {
+		size = defaultThumbnailSize
+	}
+	size = min(size, maxThumbnailSize)
+	isPNG := strings.EqualFold(filepath.Ext(p), ".png")
+	mime, ext := "image/jpeg", ".jpg"
+	if isPNG {
+		mime, ext = "image/png", ".png"
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d", strings.ToLower(filepath.Clean(p)), fi.ModTime().UnixNano(), size)))
+	cachePath := filepath.Join(apppath.BaseRoot(), "cache", "thumbnails", hex.EncodeToString(sum[:12])+ext)
+	if b, err := os.ReadFile(cachePath); err == nil {
+		return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(b)
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return ""
+	}
+	img, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	thumb := thumbnail(img, size)
+	if isPNG {
+		err = png.Encode(&buf, thumb)
+	} else {
+		err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 80})
+	}
+	if err != nil {
+		return ""
+	}
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err == nil {
+		_ = os.WriteFile(cachePath, buf.Bytes(), 0644)
+	}
+	return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// nextAvailableFileName returns name, or name with a " (n)" suffix before the extension when a file of
+// that name already exists in dir.
+func nextAvailableFileName(dir string, name string) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 1; utils.FileExists(filepath.Join(dir, candidate)); i++ {
+		candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+	return candidate
+}
+
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// ExportScreenshots copies screenshots of a player into destDir. Existing files are kept and the copy
+// gets a numbered name instead.
+func (m *Manager) ExportScreenshots(versionName string, player string, paths []string, destDir string) ScreenshotBulkResult {
+	res := ScreenshotBulkResult{Paths: []string{}, Failed: []string{}}
+	dest := strings.TrimSpace(destDir)
+	if dest == "" {
+		res.ErrorCode = "ERR_TARGET_DIR_NOT_SPECIFIED"
+		return res
+	}
+	root := m.screenshotsRoot(versionName, player)
+	if root == "" {
+		res.ErrorCode = "ERR_INVALID_PATH"
+		return res
+	}
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		res.ErrorCode = "ERR_CREATE_TARGET_DIR"
+		return res
+	}
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if !isChildOfPath(p, root) || !utils.FileExists(p) {
+			res.Failed = append(res.Failed, p)
+			continue
+		}
+		target := filepath.Join(dest, nextAvailableFileName(dest, filepath.Base(p)))
+		if err := copyFile(p, target); err != nil {
+			res.Failed = append(res.Failed, p)
+			continue
+		}
+		res.Done++
+		res.Paths = append(res.Paths, target)
+	}
+	return res
+}
+
+// DeleteScreenshots deletes screenshots of a player together with their sidecar files.
+func (m *Manager) DeleteScreenshots(versionName string, player string, paths []string) ScreenshotBulkResult {
+	res := ScreenshotBulkResult{Paths: []string{}, Failed: []string{}}
+	for _, p := range paths {
+		if code := m.DeleteScreenshot(versionName, player, p); code != "" || utils.FileExists(p) {
+			res.Failed = append(res.Failed, p)
+			continue
+		}
+		res.Done++
+		res.Paths = append(res.Paths, p)
+	}
+	return res
+}
@@ -0,0 +1,73 @@
+package contentmgr
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+func TestListScreenshotsPage(t *testing.T) {
+	base := t.TempDir()
+	dir := filepath.Join(base, "Users", "p1", "games", "com.mojang", "Screenshots", "a")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	shots := []struct {
+		name    string
+		sidecar string
+	}{
+		{"one", `{"captureTime": 100, "worldName": "Alpha"}`},
+		{"two", `{"captureTime": 300, "worldName": "Beta"}`},
+		{"three", `{"captureTime": 200, "worldName": "Alpha"}`},
+		{"four", ""},
+	}
+	for _, s := range shots {
+		os.WriteFile(filepath.Join(dir, s.name+".jpeg"), []byte("x"), 0644)
+		if s.sidecar != "" {
+			os.WriteFile(filepath.Join(dir, s.name+".json"), []byte(s.sidecar), 0644)
+		}
+	}
+	m := New(Deps{GetContentRoots: func(string) types.ContentRoots {
+		return types.ContentRoots{UsersRoot: filepath.Join(base, "Users")}
+	}})
+
+	page := m.ListScreenshotsPage("v", "p1", ScreenshotQuery{World: "Alpha"})
+	if page.Total != 2 || page.Items[0].Name != "three" || page.Items[1].Name != "one" {
+		t.Fatalf("alpha page = %+v", page)
+	}
+	if len(page.Groups) != 3 || page.Groups[0].World != "" || page.Groups[1].Count != 2 {
+		t.Fatalf("groups = %+v", page.Groups)
+	}
+	page = m.ListScreenshotsPage("v", "p1", ScreenshotQuery{Oldest: true, Offset: 1, Limit: 2})
+	if page.Total != 4 || len(page.Items) != 2 || page.Items[0].Name != "three" || page.Items[1].Name != "two" {
+		t.Fatalf("oldest page = %+v", page.Items)
+	}
+
+	res := m.DeleteScreenshots("v", "p1", []string{filepath.Join(dir, "one.jpeg"), filepath.Join(base, "outside.jpeg")})
+	if res.Done != 1 || len(res.Failed) != 1 {
+		t.Fatalf("delete = %+v", res)
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 400, 100))
+	for x := 0; x < 400; x++ {
+		for y := 0; y < 100; y++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 200, A: 255})
+		}
+	}
+	thumb := thumbnail(img, 100)
+	if b := thumb.Bounds(); b.Dx() != 100 || b.Dy() != 25 {
+		t.Fatalf("thumbnail bounds = %v", b)
+	}
+	if c := color.NRGBAModel.Convert(thumb.At(10, 10)).(color.NRGBA); c.R != 200 || c.A != 255 {
+		t.Fatalf("thumbnail color = %v", c)
+	}
+	if small := thumbnail(img, 1000); small != image.Image(img) {
+		t.Fatal("small image was resized")
+	}
+}
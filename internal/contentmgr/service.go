@@ -3,7 +3,8 @@ package contentmgr
 import (
 	"archive/zip"
 	"bytes"
-	"encoding/json"
+	"crypto/rand"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"os"
@@ -16,8 +17,10 @@ import (
 	"github.com/liteldev/LeviLauncher/internal/content"
 	"github.com/liteldev/LeviLauncher/internal/materialbin"
 	"github.com/liteldev/LeviLauncher/internal/packages"
+	"github.com/liteldev/LeviLauncher/internal/packlint"
 	"github.com/liteldev/LeviLauncher/internal/types"
 	"github.com/liteldev/LeviLauncher/internal/utils"
+	"github.com/liteldev/LeviLauncher/internal/worlddb"
 )
 
 type MaterialCompatResult struct {
@@ -49,6 +52,8 @@ type Deps struct {
 	GetContentRoots    func(name string) types.ContentRoots
 	GetVersionGameInfo func(name string) string
 	ListDir            func(path string) []types.FileEntry
+	CheckWorldCompat   func(worldDir string, versionName string) types.WorldCompatResult
+	BackupWorld        func(worldDir string, versionName string) string
 }
 
 type Manager struct {
@@ -56,6 +61,8 @@ type Manager struct {
 	getContentRoots    func(name string) types.ContentRoots
 	getVersionGameInfo func(name string) string
 	listDir            func(path string) []types.FileEntry
+	checkWorldCompat   func(worldDir string, versionName string) types.WorldCompatResult
+	backupWorld        func(worldDir string, versionName string) string
 }
 
 func New(deps Deps) *Manager {
@@ -68,11 +75,19 @@ func New(deps Deps) *Manager {
 	if deps.ListDir == nil {
 		deps.ListDir = func(string) []types.FileEntry { return nil }
 	}
+	if deps.CheckWorldCompat == nil {
+		deps.CheckWorldCompat = func(string, string) types.WorldCompatResult { return types.WorldCompatResult{} }
+	}
+	if deps.BackupWorld == nil {
+		deps.BackupWorld = func(string, string) string { return "" }
+	}
 	return &Manager{
 		packLoader:         deps.PackLoader,
 		getContentRoots:    deps.GetContentRoots,
 		getVersionGameInfo: deps.GetVersionGameInfo,
 		listDir:            deps.ListDir,
+		checkWorldCompat:   deps.CheckWorldCompat,
+		backupWorld:        deps.BackupWorld,
 	}
 }
 
@@ -84,6 +99,16 @@ func (m *Manager) ListPacksForVersion(versionName string, player string) []packa
 	if roots.ResourcePacks == "" && roots.BehaviorPacks == "" {
 		return []packages.Pack{}
 	}
+	skinPacksDirs := skinPackDirs(roots, player)
+
+	packs, err := m.packLoader.LoadPacksForVersion(versionName, roots.ResourcePacks, roots.BehaviorPacks, skinPacksDirs...)
+	if err != nil {
+		return []packages.Pack{}
+	}
+	return packs
+}
+
+func skinPackDirs(roots types.ContentRoots, player string) []string {
 	var skinPacksDirs []string
 
 	if roots.ResourcePacks != "" {
@@ -103,12 +128,45 @@ func (m *Manager) ListPacksForVersion(versionName string, player string) []packa
 			skinPacksDirs = append(skinPacksDirs, userSkinsSimple)
 		}
 	}
+	return skinPacksDirs
+}
 
-	packs, err := m.packLoader.LoadPacksForVersion(versionName, roots.ResourcePacks, roots.BehaviorPacks, skinPacksDirs...)
-	if err != nil {
-		return []packages.Pack{}
+func (m *Manager) ListSkinPacks(versionName string, player string) []types.SkinPackInfo {
+	out := []types.SkinPackInfo{}
+	for _, dir := range skinPackDirs(m.getContentRoots(versionName), player) {
+		ents, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range ents {
+			if !e.IsDir() {
+				continue
+			}
+			if info, ok := content.ReadSkinPackFromDir(filepath.Join(dir, e.Name())); ok {
+				out = append(out, info)
+			}
+		}
 	}
-	return packs
+	return out
+}
+
+func (m *Manager) ReadSkinPack(path string) types.SkinPackInfo {
+	info, _ := content.ReadSkinPackFromDir(path)
+	return info
+}
+
+func (m *Manager) CreateSkinPack(versionName string, player string, req types.SkinPackBuildRequest) string {
+	p := strings.TrimSpace(player)
+	if p != "" && (p == "." || p == ".." || strings.ContainsAny(p, `/\`)) {
+		return "ERR_INVALID_PATH"
+	}
+	roots := m.getContentRoots(versionName)
+	skinDir := m.versionSkinDir(versionName, roots)
+	if users := strings.TrimSpace(roots.UsersRoot); users != "" && p != "" {
+		skinDir = filepath.Join(users, p, "games", "com.mojang", "skin_packs")
+	}
+	_, errCode := content.BuildSkinPack(skinDir, req)
+	return errCode
 }
 
 func compareVersions(v1, v2 string) int {
@@ -233,28 +291,7 @@ func (m *Manager) TransferPackToVersion(sourceVersionName string, sourcePackPath
 	if strings.TrimSpace(dstRoots.ResourcePacks) == "" && strings.TrimSpace(dstRoots.BehaviorPacks) == "" {
 		return "ERR_ACCESS_VERSIONS_DIR"
 	}
-
-	allowedSourceRoots := []string{
-		strings.TrimSpace(srcRoots.ResourcePacks),
-		strings.TrimSpace(srcRoots.BehaviorPacks),
-	}
-	if strings.TrimSpace(srcRoots.ResourcePacks) != "" {
-		allowedSourceRoots = append(
-			allowedSourceRoots,
-			filepath.Join(filepath.Dir(srcRoots.ResourcePacks), "skin_packs"),
-		)
-	}
-	allowed := false
-	for _, root := range allowedSourceRoots {
-		if strings.TrimSpace(root) == "" {
-			continue
-		}
-		if isChildOfPath(srcPackPath, root) {
-			allowed = true
-			break
-		}
-	}
-	if !allowed {
+	if !isPackInRoots(srcPackPath, srcRoots) {
 		return "ERR_INVALID_PACKAGE"
 	}
 
@@ -273,6 +310,26 @@ func (m *Manager) TransferPackToVersion(sourceVersionName string, sourcePackPath
 	)
 }
 
+// isPackInRoots reports whether path lies in the resource, behavior or skin pack folder of roots.
+func isPackInRoots(path string, roots types.ContentRoots) bool {
+	allowedRoots := []string{
+		strings.TrimSpace(roots.ResourcePacks),
+		strings.TrimSpace(roots.BehaviorPacks),
+	}
+	if strings.TrimSpace(roots.ResourcePacks) != "" {
+		allowedRoots = append(allowedRoots, filepath.Join(filepath.Dir(roots.ResourcePacks), "skin_packs"))
+	}
+	for _, root := range allowedRoots {
+		if strings.TrimSpace(root) == "" {
+			continue
+		}
+		if isChildOfPath(path, root) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Manager) ImportMcpack(name string, data []byte, overwrite bool) string {
 	roots := m.getContentRoots(name)
 	skinDir := m.versionSkinDir(name, roots)
@@ -411,6 +468,10 @@ func (m *Manager) GetPackInfo(dir string) types.PackInfo {
 	return content.ReadPackInfoFromDir(dir)
 }
 
+func (m *Manager) ValidatePack(path string) types.PackLintReport {
+	return packlint.ValidatePath(path)
+}
+
 func readMaterialBinVersion(path string) (uint64, error) {
 	p := strings.TrimSpace(path)
 	if p == "" {
@@ -764,19 +825,16 @@ func (m *Manager) DeleteWorld(name string, path string) string {
 	return ""
 }
 
-func (m *Manager) TransferWorldToVersion(sourceVersionName string, sourcePlayer string, sourceWorldPath string, targetVersionName string, targetPlayer string) string {
-	srcVer := strings.TrimSpace(sourceVersionName)
-	dstVer := strings.TrimSpace(targetVersionName)
-	srcPlayer := strings.TrimSpace(sourcePlayer)
-	dstPlayer := strings.TrimSpace(targetPlayer)
-	srcWorld := filepath.Clean(strings.TrimSpace(sourceWorldPath))
+// worldTransferRoots validates a source world and returns the worlds folder of the target player,
+// creating it when missing.
+func (m *Manager) worldTransferRoots(srcVer string, srcPlayer string, srcWorld string, dstVer string, dstPlayer string) (string, string) {
 	if srcVer == "" || dstVer == "" || srcPlayer == "" || dstPlayer == "" || srcWorld == "" {
-		return "ERR_INVALID_PATH"
+		return "", "ERR_INVALID_PATH"
 	}
 
 	fi, err := os.Stat(srcWorld)
 	if err != nil || !fi.IsDir() {
-		return "ERR_INVALID_PATH"
+		return "", "ERR_INVALID_PATH"
 	}
 
 	srcRoots := m.getContentRoots(srcVer)
@@ -784,17 +842,26 @@ func (m *Manager) TransferWorldToVersion(sourceVersionName string, sourcePlayer
 	srcUsersRoot := strings.TrimSpace(srcRoots.UsersRoot)
 	dstUsersRoot := strings.TrimSpace(dstRoots.UsersRoot)
 	if srcUsersRoot == "" || dstUsersRoot == "" {
-		return "ERR_ACCESS_VERSIONS_DIR"
+		return "", "ERR_ACCESS_VERSIONS_DIR"
 	}
 
 	sourceWorldsRoot := filepath.Join(srcUsersRoot, srcPlayer, "games", "com.mojang", "minecraftWorlds")
 	if !isChildOfPath(srcWorld, sourceWorldsRoot) {
-		return "ERR_INVALID_PATH"
+		return "", "ERR_INVALID_PATH"
 	}
 
 	targetWorldsRoot := filepath.Join(dstUsersRoot, dstPlayer, "games", "com.mojang", "minecraftWorlds")
 	if err := os.MkdirAll(targetWorldsRoot, 0755); err != nil {
-		return "ERR_CREATE_TARGET_DIR"
+		return "", "ERR_CREATE_TARGET_DIR"
+	}
+	return targetWorldsRoot, ""
+}
+
+func (m *Manager) TransferWorldToVersion(sourceVersionName string, sourcePlayer string, sourceWorldPath string, targetVersionName string, targetPlayer string) string {
+	srcWorld := filepath.Clean(strings.TrimSpace(sourceWorldPath))
+	targetWorldsRoot, code := m.worldTransferRoots(strings.TrimSpace(sourceVersionName), strings.TrimSpace(sourcePlayer), srcWorld, strings.TrimSpace(targetVersionName), strings.TrimSpace(targetPlayer))
+	if code != "" {
+		return code
 	}
 
 	targetFolderName := nextAvailableFolderName(targetWorldsRoot, filepath.Base(srcWorld))
@@ -806,11 +873,88 @@ func (m *Manager) TransferWorldToVersion(sourceVersionName string, sourcePlayer
 	return ""
 }
 
+// CloneWorld copies a world like TransferWorldToVersion and gives the copy its own identity: a folder
+// and level name of its own, and optionally a fresh seed, no player data and a reset clock. A new seed
+// only affects chunks that have not been generated yet.
+func (m *Manager) CloneWorld(req types.WorldCloneRequest) types.WorldCloneResult {
+	var res types.WorldCloneResult
+	srcWorld := filepath.Clean(strings.TrimSpace(req.SourceWorldPath))
+	targetWorldsRoot, code := m.worldTransferRoots(strings.TrimSpace(req.SourceVersionName), strings.TrimSpace(req.SourcePlayer), srcWorld, strings.TrimSpace(req.TargetVersionName), strings.TrimSpace(req.TargetPlayer))
+	if code != "" {
+		res.ErrorCode = code
+		return res
+	}
+	root, ver, err := content.DecodeLevelDat(srcWorld)
+	if err != nil {
+		res.ErrorCode = "ERR_READ_LEVEL_DAT"
+		return res
+	}
+
+	levelName := strings.TrimSpace(req.LevelName)
+	if levelName == "" {
+		old, _ := root["LevelName"].(string)
+		levelName = strings.TrimSpace(old + " Copy")
+	}
+	desired := filepath.Base(srcWorld)
+	if name := strings.TrimSpace(req.FolderName); name != "" {
+		desired = utils.SanitizeFilename(name)
+	}
+	res.FolderName = nextAvailableFolderName(targetWorldsRoot, desired)
+	res.Path = filepath.Join(targetWorldsRoot, res.FolderName)
+	res.LevelName = levelName
+
+	if err := utils.CopyDir(srcWorld, res.Path); err != nil {
+		_ = os.RemoveAll(res.Path)
+		res.ErrorCode = "ERR_WRITE_FILE"
+		return res
+	}
+	fail := func(code string) types.WorldCloneResult {
+		_ = os.RemoveAll(res.Path)
+		return types.WorldCloneResult{ErrorCode: code}
+	}
+	// The copy must not keep the backup of the source's level.dat made by earlier edits.
+	_ = os.Remove(filepath.Join(res.Path, "level.dat_leviold"))
+
+	root["LevelName"] = levelName
+	if req.NewSeed {
+		var b [8]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return fail("ERR_RANDOM_SEED")
+		}
+		res.Seed = int64(binary.LittleEndian.Uint64(b[:]))
+		root["RandomSeed"] = res.Seed
+	} else if seed, ok := root["RandomSeed"].(int64); ok {
+		res.Seed = seed
+	}
+	if req.ResetTime {
+		for _, k := range []string{"Time", "currentTick"} {
+			if _, ok := root[k]; ok {
+				root[k] = int64(0)
+			}
+		}
+	}
+	if err := content.EncodeLevelDat(res.Path, ver, root); err != nil {
+		return fail("ERR_WRITE_FILE")
+	}
+	if err := os.WriteFile(filepath.Join(res.Path, "levelname.txt"), []byte(levelName), 0644); err != nil {
+		return fail("ERR_WRITE_FILE")
+	}
+	if req.ResetPlayers && utils.DirExists(filepath.Join(res.Path, "db")) {
+		if _, err := worlddb.DeletePlayers(res.Path); err != nil {
+			return fail("ERR_WRITE_WORLD_DB")
+		}
+	}
+	return res
+}
+
 type ScreenshotInfo struct {
 	Name        string `json:"name"`
 	Path        string `json:"path"`
 	Dir         string `json:"dir"`
 	CaptureTime int64  `json:"captureTime"`
+	ModTime     int64  `json:"modTime"`
+	Size        int64  `json:"size"`
+	World       string `json:"world"`
 }
 
 type screenshotMeta struct {
@@ -818,14 +962,8 @@ type screenshotMeta struct {
 }
 
 func (m *Manager) ListScreenshots(versionName string, player string) []ScreenshotInfo {
-	roots := m.getContentRoots(versionName)
-	usersRoot := strings.TrimSpace(roots.UsersRoot)
-	p := strings.TrimSpace(player)
-	if usersRoot == "" || p == "" {
-		return []ScreenshotInfo{}
-	}
-	screenshotsRoot := filepath.Join(usersRoot, p, "games", "com.mojang", "Screenshots")
-	if !utils.DirExists(screenshotsRoot) {
+	screenshotsRoot := m.screenshotsRoot(versionName, player)
+	if screenshotsRoot == "" || !utils.DirExists(screenshotsRoot) {
 		return []ScreenshotInfo{}
 	}
 	var result []ScreenshotInfo
@@ -854,19 +992,19 @@ func (m *Manager) ListScreenshots(versionName string, player string) []Screensho
 			baseName := strings.TrimSuffix(name, filepath.Ext(name))
 			imgPath := filepath.Join(subPath, name)
 			jsonPath := filepath.Join(subPath, baseName+".json")
-			var captureTime int64
-			if jsonData, err := os.ReadFile(jsonPath); err == nil {
-				var meta screenshotMeta
-				if json.Unmarshal(jsonData, &meta) == nil {
-					captureTime = meta.CaptureTime
-				}
-			}
-			result = append(result, ScreenshotInfo{
+			captureTime, world := readScreenshotSidecar(jsonPath)
+			info := ScreenshotInfo{
 				Name:        baseName,
 				Path:        imgPath,
 				Dir:         subPath,
 				CaptureTime: captureTime,
-			})
+				World:       world,
+			}
+			if fi, err := e.Info(); err == nil {
+				info.ModTime = fi.ModTime().Unix()
+				info.Size = fi.Size()
+			}
+			result = append(result, info)
 		}
 	}
 	if result == nil {
@@ -0,0 +1,16 @@
+package contentmgr
+
+import (
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+func TestCreateSkinPackRejectsPlayerPaths(t *testing.T) {
+	m, _ := transferTestManager(t)
+	for _, player := range []string{"..", ".", "../p", `a\b`, "a/b"} {
+		if code := m.CreateSkinPack("dst", player, types.SkinPackBuildRequest{Name: "x"}); code != "ERR_INVALID_PATH" {
+			t.Errorf("player %q: got %q, want ERR_INVALID_PATH", player, code)
+		}
+	}
+}
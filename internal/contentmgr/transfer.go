@@ -0,0 +1,309 @@
+package contentmgr
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/liteldev/LeviLauncher/internal/content"
+	"github.com/liteldev/LeviLauncher/internal/packages"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+const (
+	TransferKindPack        = "pack"
+	TransferKindWorld       = "world"
+	TransferKindWorldCompat = "world_compat"
+
+	TransferChoiceSkip          = "skip"
+	TransferChoiceOverwrite     = "overwrite"
+	TransferChoiceKeepBoth      = "keep_both"
+	TransferChoiceProceed       = "proceed"
+	TransferChoiceBackupProceed = "backup_proceed"
+)
+
+type transferPack struct {
+	uuid    string
+	path    string
+	summary string
+}
+
+func readTransferPack(dir string) (transferPack, bool) {
+	b, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return transferPack{}, false
+	}
+	var mf packages.RawManifest
+	if json.Unmarshal(utils.JsonCompatBytes(b), &mf) != nil || strings.TrimSpace(mf.Header.UUID) == "" {
+		return transferPack{}, false
+	}
+	info := content.ReadPackInfoFromDir(dir)
+	summary := strings.TrimSpace(info.Name + " " + info.Version)
+	return transferPack{uuid: strings.ToLower(strings.TrimSpace(mf.Header.UUID)), path: dir, summary: summary}, true
+}
+
+// targetPacksByUUID indexes the packs already installed for a version by manifest UUID.
+func (m *Manager) targetPacksByUUID(versionName string, roots types.ContentRoots) map[string]transferPack {
+	out := map[string]transferPack{}
+	dirs := []string{roots.ResourcePacks, roots.BehaviorPacks}
+	if skinDir := m.versionSkinDir(versionName, roots); skinDir != "" {
+		dirs = append(dirs, skinDir)
+	}
+	for _, root := range dirs {
+		if strings.TrimSpace(root) == "" {
+			continue
+		}
+		ents, err := os.ReadDir(root)
+		if err != nil {
+			continue
+		}
+		for _, e := range ents {
+			if !e.IsDir() {
+				continue
+			}
+			if p, ok := readTransferPack(filepath.Join(root, e.Name())); ok {
+				out[p.uuid] = p
+			}
+		}
+	}
+	return out
+}
+
+func worldSummary(dir string) string {
+	if b, err := os.ReadFile(filepath.Join(dir, "levelname.txt")); err == nil {
+		if name := strings.TrimSpace(strings.SplitN(string(b), "\n", 2)[0]); name != "" {
+			return name
+		}
+	}
+	return filepath.Base(dir)
+}
+
+// collectTransferConflicts lists the items of req that would collide with content of the target: packs
+// whose UUID is already installed, in any version, worlds whose folder name is taken, and worlds the
+// target version cannot open safely.
+func (m *Manager) collectTransferConflicts(req types.ContentTransferRequest) ([]types.ContentTransferConflict, string) {
+	srcVer, dstVer := strings.TrimSpace(req.SourceVersionName), strings.TrimSpace(req.TargetVersionName)
+	if srcVer == "" || dstVer == "" {
+		return nil, "ERR_INVALID_PATH"
+	}
+	srcRoots, dstRoots := m.getContentRoots(srcVer), m.getContentRoots(dstVer)
+	if strings.TrimSpace(dstRoots.UsersRoot) == "" {
+		return nil, "ERR_ACCESS_VERSIONS_DIR"
+	}
+	if strings.EqualFold(filepath.Clean(srcRoots.UsersRoot), filepath.Clean(dstRoots.UsersRoot)) &&
+		strings.EqualFold(strings.TrimSpace(req.SourcePlayer), strings.TrimSpace(req.TargetPlayer)) {
+		return nil, "ERR_SAME_LOCATION"
+	}
+
+	conflicts := []types.ContentTransferConflict{}
+	var installed map[string]transferPack
+	worldsRoot := filepath.Join(dstRoots.UsersRoot, strings.TrimSpace(req.TargetPlayer), "games", "com.mojang", "minecraftWorlds")
+	for _, it := range req.Items {
+		src := filepath.Clean(strings.TrimSpace(it.Path))
+		switch it.Kind {
+		case TransferKindPack:
+			if !isPackInRoots(src, srcRoots) {
+				return nil, "ERR_INVALID_PACKAGE"
+			}
+			if installed == nil {
+				installed = m.targetPacksByUUID(dstVer, dstRoots)
+			}
+			p, ok := readTransferPack(src)
+			if !ok {
+				continue
+			}
+			if cur, exists := installed[p.uuid]; exists {
+				conflicts = append(conflicts, types.ContentTransferConflict{
+					ID:            TransferKindPack + ":" + p.uuid,
+					Kind:          TransferKindPack,
+					SourcePath:    src,
+					TargetPath:    cur.path,
+					IdentityKind:  "pack_uuid",
+					IdentityKey:   p.uuid,
+					SourceSummary: p.summary,
+					TargetSummary: cur.summary,
+					Choices:       []string{TransferChoiceSkip, TransferChoiceOverwrite},
+				})
+			}
+		case TransferKindWorld:
+			if !utils.DirExists(src) {
+				return nil, "ERR_INVALID_PATH"
+			}
+			target := filepath.Join(worldsRoot, filepath.Base(src))
+			if utils.DirExists(target) {
+				conflicts = append(conflicts, types.ContentTransferConflict{
+					ID:            TransferKindWorld + ":" + strings.ToLower(filepath.Base(src)),
+					Kind:          TransferKindWorld,
+					SourcePath:    src,
+					TargetPath:    target,
+					IdentityKind:  "world_folder",
+					IdentityKey:   filepath.Base(src),
+					SourceSummary: worldSummary(src),
+					TargetSummary: worldSummary(target),
+					Choices:       []string{TransferChoiceSkip, TransferChoiceOverwrite, TransferChoiceKeepBoth},
+				})
+			}
+			if check := m.checkWorldCompat(src, dstVer); check.Status == content.CompatIncompatible || check.Status == content.CompatDowngrade {
+				conflicts = append(conflicts, types.ContentTransferConflict{
+					ID:            TransferKindWorldCompat + ":" + strings.ToLower(filepath.Base(src)),
+					Kind:          TransferKindWorldCompat,
+					SourcePath:    src,
+					TargetPath:    target,
+					IdentityKind:  check.Status,
+					IdentityKey:   filepath.Base(src),
+					SourceSummary: check.WorldVersion,
+					TargetSummary: check.TargetVersion,
+					Choices:       []string{TransferChoiceSkip, TransferChoiceProceed, TransferChoiceBackupProceed},
+				})
+			}
+		default:
+			return nil, "ERR_INVALID_TRANSFER_ITEM"
+		}
+	}
+	return conflicts, ""
+}
+
+// PreviewContentTransfer lists the conflicts a batch transfer would run into. Each needs a resolution
+// before TransferContent accepts the batch.
+func (m *Manager) PreviewContentTransfer(req types.ContentTransferRequest) types.ContentTransferPreview {
+	conflicts, code := m.collectTransferConflicts(req)
+	if conflicts == nil {
+		conflicts = []types.ContentTransferConflict{}
+	}
+	return types.ContentTransferPreview{Conflicts: conflicts, ErrorCode: code}
+}
+
+// replaceWorld copies src over target through a staging folder, so a failed copy leaves target intact.
+func replaceWorld(src string, target string) error {
+	staging := filepath.Join(filepath.Dir(target), "."+filepath.Base(target)+".transfer")
+	_ = os.RemoveAll(staging)
+	if err := utils.CopyDir(src, staging); err != nil {
+		_ = os.RemoveAll(staging)
+		return err
+	}
+	if err := os.RemoveAll(target); err != nil {
+		_ = os.RemoveAll(staging)
+		return err
+	}
+	return os.Rename(staging, target)
+}
+
+// TransferContent copies a batch of packs and worlds from one version to another. A version without
+// isolation stands for the shared game data. Every conflict reported by PreviewContentTransfer must be
+// resolved; a world the target version cannot open safely is only copied once its world_compat
+// conflict is resolved with proceed or backup_proceed. Canceling ctx stops the batch before the next
+// item; items already copied stay.
+func (m *Manager) TransferContent(ctx context.Context, req types.ContentTransferRequest, progress func(types.ContentTransferProgress)) types.ContentTransferResult {
+	res := types.ContentTransferResult{Status: "failed", Items: []types.ContentTransferItemResult{}}
+	if progress == nil {
+		progress = func(types.ContentTransferProgress) {}
+	}
+	conflicts, code := m.collectTransferConflicts(req)
+	if code != "" {
+		res.ErrorCode = code
+		return res
+	}
+	choices := map[string]string{}
+	for _, r := range req.Resolutions {
+		choices[strings.TrimSpace(r.ConflictID)] = strings.TrimSpace(r.Choice)
+	}
+	byPath := map[string]types.ContentTransferConflict{}
+	compatByPath := map[string]string{}
+	for _, c := range conflicts {
+		choice, ok := choices[c.ID]
+		if !ok {
+			res.ErrorCode = "ERR_TRANSFER_CONFLICT_UNRESOLVED"
+			return res
+		}
+		valid := false
+		for _, allowed := range c.Choices {
+			valid = valid || allowed == choice
+		}
+		if !valid {
+			res.ErrorCode = "ERR_TRANSFER_INVALID_CONFLICT_RESOLUTION"
+			return res
+		}
+		if c.Kind == TransferKindWorldCompat {
+			compatByPath[strings.ToLower(c.SourcePath)] = choice
+			continue
+		}
+		byPath[strings.ToLower(c.SourcePath)] = c
+	}
+
+	dstVer := strings.TrimSpace(req.TargetVersionName)
+	total := len(req.Items)
+	done, failed := 0, 0
+	for i, it := range req.Items {
+		src := filepath.Clean(strings.TrimSpace(it.Path))
+		item := types.ContentTransferItemResult{Kind: it.Kind, SourcePath: src, Status: "success"}
+		if ctx.Err() != nil {
+			item.Status = "canceled"
+			res.Items = append(res.Items, item)
+			continue
+		}
+		progress(types.ContentTransferProgress{Phase: "transferring", Current: i + 1, Total: total, Path: src, Ts: time.Now().UnixMilli()})
+		choice := ""
+		if c, ok := byPath[strings.ToLower(src)]; ok {
+			choice = choices[c.ID]
+			item.TargetPath = c.TargetPath
+		}
+		compat := compatByPath[strings.ToLower(src)]
+		if compat == TransferChoiceBackupProceed && choice != TransferChoiceSkip {
+			if m.backupWorld(src, strings.TrimSpace(req.SourceVersionName)) == "" {
+				item.ErrorCode = "ERR_BACKUP_FAILED"
+			}
+		}
+		switch {
+		case item.ErrorCode != "":
+		case choice == TransferChoiceSkip || compat == TransferChoiceSkip:
+			item.Status = "skipped"
+		case it.Kind == TransferKindPack:
+			item.ErrorCode = m.TransferPackToVersion(req.SourceVersionName, src, dstVer, choice == TransferChoiceOverwrite)
+		default:
+			worldsRoot, code := m.worldTransferRoots(strings.TrimSpace(req.SourceVersionName), strings.TrimSpace(req.SourcePlayer), src, dstVer, strings.TrimSpace(req.TargetPlayer))
+			if code != "" {
+				item.ErrorCode = code
+				break
+			}
+			if choice == TransferChoiceOverwrite {
+				if err := replaceWorld(src, item.TargetPath); err != nil {
+					item.ErrorCode = "ERR_WRITE_FILE"
+				}
+				break
+			}
+			item.TargetPath = filepath.Join(worldsRoot, nextAvailableFolderName(worldsRoot, filepath.Base(src)))
+			if err := utils.CopyDir(src, item.TargetPath); err != nil {
+				item.ErrorCode = "ERR_WRITE_FILE"
+			}
+		}
+		if item.ErrorCode != "" {
+			item.Status = "failed"
+			failed++
+		} else if item.Status == "success" {
+			done++
+		}
+		res.Items = append(res.Items, item)
+	}
+	progress(types.ContentTransferProgress{Phase: "finalizing", Current: total, Total: total, Ts: time.Now().UnixMilli()})
+
+	switch {
+	case ctx.Err() != nil:
+		res.Status = "canceled"
+	case failed == 0:
+		res.Status = "success"
+	case done > 0:
+		res.Status = "partial"
+	default:
+		for _, item := range res.Items {
+			if item.ErrorCode != "" {
+				res.ErrorCode = item.ErrorCode
+				break
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,137 @@
+package contentmgr
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/content"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+func transferTestManager(t *testing.T) (*Manager, map[string]string) {
+	t.Helper()
+	bases := map[string]string{"src": t.TempDir(), "dst": t.TempDir()}
+	return New(Deps{GetContentRoots: func(name string) types.ContentRoots {
+		base := bases[name]
+		shared := filepath.Join(base, "Users", "Shared", "games", "com.mojang")
+		return types.ContentRoots{
+			Base:          base,
+			UsersRoot:     filepath.Join(base, "Users"),
+			ResourcePacks: filepath.Join(shared, "resource_packs"),
+			BehaviorPacks: filepath.Join(shared, "behavior_packs"),
+		}
+	}}), bases
+}
+
+func writeTestWorld(t *testing.T, base string, folder string, name string) string {
+	t.Helper()
+	dir := filepath.Join(base, "Users", "p", "games", "com.mojang", "minecraftWorlds", folder)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "levelname.txt"), []byte(name), 0644)
+	return dir
+}
+
+func TestTransferContentWorldConflicts(t *testing.T) {
+	m, bases := transferTestManager(t)
+	a := writeTestWorld(t, bases["src"], "aaa", "Source A")
+	b := writeTestWorld(t, bases["src"], "bbb", "Source B")
+	c := writeTestWorld(t, bases["src"], "ccc", "Source C")
+	writeTestWorld(t, bases["dst"], "aaa", "Target A")
+	writeTestWorld(t, bases["dst"], "bbb", "Target B")
+
+	req := types.ContentTransferRequest{
+		SourceVersionName: "src", SourcePlayer: "p", TargetVersionName: "dst", TargetPlayer: "p",
+		Items: []types.ContentTransferItem{{Kind: TransferKindWorld, Path: a}, {Kind: TransferKindWorld, Path: b}, {Kind: TransferKindWorld, Path: c}},
+	}
+	preview := m.PreviewContentTransfer(req)
+	if preview.ErrorCode != "" || len(preview.Conflicts) != 2 || preview.Conflicts[0].TargetSummary != "Target A" {
+		t.Fatalf("preview = %+v", preview)
+	}
+	if res := m.TransferContent(context.Background(), req, nil); res.ErrorCode != "ERR_TRANSFER_CONFLICT_UNRESOLVED" {
+		t.Fatalf("unresolved: %+v", res)
+	}
+
+	req.Resolutions = []types.ContentTransferResolution{
+		{ConflictID: preview.Conflicts[0].ID, Choice: TransferChoiceOverwrite},
+		{ConflictID: preview.Conflicts[1].ID, Choice: TransferChoiceKeepBoth},
+	}
+	var steps int
+	res := m.TransferContent(context.Background(), req, func(types.ContentTransferProgress) { steps++ })
+	if res.Status != "success" || len(res.Items) != 3 || steps != 4 {
+		t.Fatalf("transfer = %+v, steps %d", res, steps)
+	}
+	worlds := filepath.Join(bases["dst"], "Users", "p", "games", "com.mojang", "minecraftWorlds")
+	if got, _ := os.ReadFile(filepath.Join(worlds, "aaa", "levelname.txt")); string(got) != "Source A" {
+		t.Fatalf("overwritten world has %q", got)
+	}
+	if got, _ := os.ReadFile(filepath.Join(worlds, "bbb", "levelname.txt")); string(got) != "Target B" {
+		t.Fatalf("kept world has %q", got)
+	}
+	if res.Items[1].TargetPath == filepath.Join(worlds, "bbb") || !utils.DirExists(res.Items[1].TargetPath) || !utils.DirExists(filepath.Join(worlds, "ccc")) {
+		t.Fatalf("items = %+v", res.Items)
+	}
+}
+
+func TestTransferContentCanceled(t *testing.T) {
+	m, bases := transferTestManager(t)
+	a := writeTestWorld(t, bases["src"], "aaa", "A")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res := m.TransferContent(ctx, types.ContentTransferRequest{
+		SourceVersionName: "src", SourcePlayer: "p", TargetVersionName: "dst", TargetPlayer: "p",
+		Items: []types.ContentTransferItem{{Kind: TransferKindWorld, Path: a}},
+	}, nil)
+	if res.Status != "canceled" || res.Items[0].Status != "canceled" {
+		t.Fatalf("canceled transfer = %+v", res)
+	}
+}
+
+func TestTransferContentWorldCompatConflicts(t *testing.T) {
+	m, bases := transferTestManager(t)
+	var backedUp []string
+	m.checkWorldCompat = func(dir string, version string) types.WorldCompatResult {
+		if filepath.Base(dir) == "ok" {
+			return types.WorldCompatResult{Status: content.CompatOK}
+		}
+		return types.WorldCompatResult{Status: content.CompatDowngrade, WorldVersion: "1.21.90", TargetVersion: "1.21.80"}
+	}
+	m.backupWorld = func(dir string, version string) string {
+		backedUp = append(backedUp, filepath.Base(dir)+"@"+version)
+		return dir + ".bak"
+	}
+	a := writeTestWorld(t, bases["src"], "aaa", "A")
+	b := writeTestWorld(t, bases["src"], "bbb", "B")
+	ok := writeTestWorld(t, bases["src"], "ok", "OK")
+	req := types.ContentTransferRequest{
+		SourceVersionName: "src", SourcePlayer: "p", TargetVersionName: "dst", TargetPlayer: "p",
+		Items: []types.ContentTransferItem{{Kind: TransferKindWorld, Path: a}, {Kind: TransferKindWorld, Path: b}, {Kind: TransferKindWorld, Path: ok}},
+	}
+	preview := m.PreviewContentTransfer(req)
+	if len(preview.Conflicts) != 2 || preview.Conflicts[0].Kind != TransferKindWorldCompat || preview.Conflicts[0].IdentityKind != content.CompatDowngrade {
+		t.Fatalf("preview = %+v", preview)
+	}
+	if res := m.TransferContent(context.Background(), req, nil); res.ErrorCode != "ERR_TRANSFER_CONFLICT_UNRESOLVED" {
+		t.Fatalf("unresolved: %+v", res)
+	}
+
+	req.Resolutions = []types.ContentTransferResolution{
+		{ConflictID: preview.Conflicts[0].ID, Choice: TransferChoiceSkip},
+		{ConflictID: preview.Conflicts[1].ID, Choice: TransferChoiceBackupProceed},
+	}
+	res := m.TransferContent(context.Background(), req, nil)
+	if res.Status != "success" || res.Items[0].Status != "skipped" || res.Items[1].Status != "success" || res.Items[2].Status != "success" {
+		t.Fatalf("transfer = %+v", res)
+	}
+	worlds := filepath.Join(bases["dst"], "Users", "p", "games", "com.mojang", "minecraftWorlds")
+	if utils.DirExists(filepath.Join(worlds, "aaa")) || !utils.DirExists(filepath.Join(worlds, "bbb")) {
+		t.Fatalf("skipped world copied or accepted world missing")
+	}
+	if len(backedUp) != 1 || backedUp[0] != "bbb@src" {
+		t.Fatalf("backups = %v", backedUp)
+	}
+}
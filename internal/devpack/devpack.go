@@ -0,0 +1,210 @@
+// Package devpack mirrors a pack working folder into the game's development pack folders.
+//
+// Changes are detected by polling file sizes and modification times. Polling keeps the watcher free of
+// platform specific notification APIs and copes with editors that replace files through renames, at the
+// cost of a small delay between saving a file and the sync.
+package devpack
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+const DefaultInterval = time.Second
+
+// Folders that are part of a working copy but never belong in the game's copy of a pack.
+var ignoredDirs = map[string]struct{}{
+	".git":         {},
+	".svn":         {},
+	".idea":        {},
+	".vscode":      {},
+	"node_modules": {},
+}
+
+type FileState struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// Snapshot maps the slash separated relative path of every file under dir to its size and mtime.
+type Snapshot map[string]FileState
+
+func ignored(rel string) bool {
+	for _, part := range strings.Split(rel, "/") {
+		if _, ok := ignoredDirs[strings.ToLower(part)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan takes a snapshot of dir.
+func Scan(dir string) (Snapshot, error) {
+	snap := Snapshot{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if ignored(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		snap[rel] = FileState{Size: fi.Size(), ModTime: fi.ModTime()}
+		return nil
+	})
+	return snap, err
+}
+
+// Diff returns the sorted relative paths that were added, modified or removed between two snapshots.
+func Diff(prev Snapshot, next Snapshot) []string {
+	var out []string
+	for rel, st := range next {
+		old, ok := prev[rel]
+		if !ok || old.Size != st.Size || !old.ModTime.Equal(st.ModTime) {
+			out = append(out, rel)
+		}
+	}
+	for rel := range prev {
+		if _, ok := next[rel]; !ok {
+			out = append(out, rel)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Sync makes dst an exact copy of src. Only files whose size or modification time differ are copied,
+// and files that no longer exist in src are removed from dst.
+func Sync(src string, dst string) (types.DevPackSyncStats, error) {
+	var stats types.DevPackSyncStats
+	from, err := Scan(src)
+	if err != nil {
+		return stats, err
+	}
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		return stats, err
+	}
+	to, err := Scan(dst)
+	if err != nil {
+		return stats, err
+	}
+	for rel, st := range from {
+		if old, ok := to[rel]; ok && old.Size == st.Size && old.ModTime.Equal(st.ModTime) {
+			stats.Unchanged++
+			continue
+		}
+		if err := copyFile(filepath.Join(src, filepath.FromSlash(rel)), filepath.Join(dst, filepath.FromSlash(rel)), st.ModTime); err != nil {
+			return stats, err
+		}
+		stats.Copied++
+		stats.Bytes += st.Size
+	}
+	for rel := range to {
+		if _, ok := from[rel]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dst, filepath.FromSlash(rel))); err != nil && !os.IsNotExist(err) {
+			return stats, err
+		}
+		stats.Removed++
+	}
+	if stats.Removed > 0 {
+		removeEmptyDirs(dst)
+	}
+	return stats, nil
+}
+
+// copyFile copies through a temporary file so the game never reads a half written file, and carries
+// over the source mtime so the next sync sees the file as unchanged.
+func copyFile(src string, dst string, modTime time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp := dst + ".devsync"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Chtimes(tmp, modTime, modTime); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+func removeEmptyDirs(root string) {
+	var dirs []string
+	_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() && p != root {
+			dirs = append(dirs, p)
+		}
+		return nil
+	})
+	// Deepest first, so parents become empty before they are visited.
+	sort.Slice(dirs, func(i, j int) bool { return len(dirs[i]) > len(dirs[j]) })
+	for _, d := range dirs {
+		_ = os.Remove(d)
+	}
+}
+
+// Watch polls dir every interval and calls onChange with the changed paths until ctx is cancelled. The
+// callback runs on the watcher goroutine, so a slow sync delays the next poll instead of overlapping.
+func Watch(ctx context.Context, dir string, interval time.Duration, onChange func(changed []string)) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	prev, _ := Scan(dir)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		next, err := Scan(dir)
+		if err != nil {
+			continue
+		}
+		if changed := Diff(prev, next); len(changed) > 0 {
+			prev = next
+			onChange(changed)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package devpack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, root string, rel string, body string) {
+	t.Helper()
+	p := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", rel, err)
+	}
+	if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+		t.Fatalf("write %s: %v", rel, err)
+	}
+}
+
+func TestSyncIsIncremental(t *testing.T) {
+	src := t.TempDir()
+	dst := filepath.Join(t.TempDir(), "pack")
+	writeFile(t, src, "manifest.json", "{}")
+	writeFile(t, src, "scripts/main.js", "console.log(1)")
+	writeFile(t, src, ".git/HEAD", "ref")
+
+	stats, err := Sync(src, dst)
+	if err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if stats.Copied != 2 || stats.Removed != 0 {
+		t.Fatalf("unexpected first sync stats: %+v", stats)
+	}
+	if _, err := os.Stat(filepath.Join(dst, ".git")); !os.IsNotExist(err) {
+		t.Fatalf("ignored folders must not be mirrored")
+	}
+
+	stats, err = Sync(src, dst)
+	if err != nil || stats.Copied != 0 || stats.Unchanged != 2 {
+		t.Fatalf("expected nothing to copy on second sync, got %+v (%v)", stats, err)
+	}
+
+	writeFile(t, src, "scripts/main.js", "console.log(22)")
+	if err := os.RemoveAll(filepath.Join(src, "manifest.json")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	stats, err = Sync(src, dst)
+	if err != nil || stats.Copied != 1 || stats.Removed != 1 {
+		t.Fatalf("unexpected incremental stats: %+v (%v)", stats, err)
+	}
+	b, err := os.ReadFile(filepath.Join(dst, "scripts", "main.js"))
+	if err != nil || string(b) != "console.log(22)" {
+		t.Fatalf("mirrored file not updated: %q (%v)", b, err)
+	}
+}
+
+func TestWatchReportsChanges(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, src, "manifest.json", "{}")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan []string, 4)
+	go Watch(ctx, src, 10*time.Millisecond, func(changed []string) { changes <- changed })
+
+	time.Sleep(30 * time.Millisecond)
+	writeFile(t, src, "texts/en_US.lang", "pack.name=x")
+	select {
+	case got := <-changes:
+		if len(got) != 1 || got[0] != "texts/en_US.lang" {
+			t.Fatalf("unexpected changes: %v", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("watcher did not report the new file")
+	}
+}
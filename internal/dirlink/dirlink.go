@@ -0,0 +1,125 @@
+// Package dirlink manages directory links used to share content folders between game instances.
+//
+// Links are created as NTFS junctions, which unlike symbolic links do not require administrator rights
+// or developer mode, and are followed transparently by the game.
+package dirlink
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Microsoft/go-winio"
+	"golang.org/x/sys/windows"
+)
+
+var ErrNotLink = errors.New("dirlink: path is not a directory link")
+
+// Create makes link a junction pointing at target. link must not exist yet and target must be an
+// existing directory.
+func Create(link string, target string) error {
+	absTarget, err := filepath.Abs(strings.TrimSpace(target))
+	if err != nil {
+		return err
+	}
+	fi, err := os.Stat(absTarget)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return os.ErrInvalid
+	}
+	if err := os.Mkdir(link, 0o755); err != nil {
+		return err
+	}
+	if err := setMountPoint(link, absTarget); err != nil {
+		_ = os.Remove(link)
+		return err
+	}
+	return nil
+}
+
+func setMountPoint(link string, target string) error {
+	p, err := windows.UTF16PtrFromString(link)
+	if err != nil {
+		return err
+	}
+	h, err := windows.CreateFile(
+		p,
+		windows.GENERIC_WRITE,
+		0,
+		nil,
+		windows.OPEN_EXISTING,
+		windows.FILE_FLAG_OPEN_REPARSE_POINT|windows.FILE_FLAG_BACKUP_SEMANTICS,
+		0,
+	)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(h)
+	buf := winio.EncodeReparsePoint(&winio.ReparsePoint{Target: target, IsMountPoint: true})
+	var returned uint32
+	return windows.DeviceIoControl(h, windows.FSCTL_SET_REPARSE_POINT, &buf[0], uint32(len(buf)), nil, 0, &returned, nil)
+}
+
+// IsLink reports whether p is a junction or a directory symbolic link.
+func IsLink(p string) bool {
+	fi, err := os.Lstat(p)
+	if err != nil {
+		return false
+	}
+	if fi.Mode()&(os.ModeSymlink|os.ModeIrregular) == 0 {
+		return false
+	}
+	_, err = os.Readlink(p)
+	return err == nil
+}
+
+// Target returns the absolute directory a link points at.
+func Target(p string) (string, error) {
+	if !IsLink(p) {
+		return "", ErrNotLink
+	}
+	t, err := os.Readlink(p)
+	if err != nil {
+		return "", err
+	}
+	t = strings.TrimPrefix(t, `\\?\`)
+	t = strings.TrimPrefix(t, `\??\`)
+	if !filepath.IsAbs(t) {
+		t = filepath.Join(filepath.Dir(p), t)
+	}
+	return filepath.Clean(t), nil
+}
+
+// IsBroken reports whether p is a link whose target no longer exists.
+func IsBroken(p string) bool {
+	t, err := Target(p)
+	if err != nil {
+		return false
+	}
+	fi, err := os.Stat(t)
+	return err != nil || !fi.IsDir()
+}
+
+// Remove deletes the link itself without touching the directory it points at.
+func Remove(p string) error {
+	if !IsLink(p) {
+		return ErrNotLink
+	}
+	return os.Remove(p)
+}
+
+// SameTarget reports whether link points at dir.
+func SameTarget(link string, dir string) bool {
+	t, err := Target(link)
+	if err != nil {
+		return false
+	}
+	abs, err := filepath.Abs(strings.TrimSpace(dir))
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(filepath.Clean(t), filepath.Clean(abs))
+}
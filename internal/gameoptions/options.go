@@ -0,0 +1,129 @@
+// Package gameoptions reads and writes the options.txt the game keeps for each player in
+// games/com.mojang/minecraftpe.
+//
+// The file is a list of key:value lines. A File keeps every line as it was read, so writing it back
+// after an edit only changes the edited values; keys the launcher does not know and lines it cannot
+// parse are preserved in place.
+package gameoptions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type line struct {
+	key   string
+	value string
+	// raw holds lines without a key, written back verbatim.
+	raw string
+}
+
+// File is a parsed options.txt.
+type File struct {
+	lines []line
+	eol   string
+	// trailing reports whether the last line ended with a line break.
+	trailing bool
+	bom      bool
+}
+
+// Parse reads options.txt content. It never fails: lines without a colon are kept as they are.
+func Parse(b []byte) *File {
+	s, bom := strings.CutPrefix(string(b), "\ufeff")
+	f := &File{eol: "\n", trailing: true, bom: bom}
+	if strings.Contains(s, "\r\n") {
+		f.eol = "\r\n"
+	}
+	if s == "" {
+		return f
+	}
+	f.trailing = strings.HasSuffix(s, "\n")
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "\n"), "\r")
+	for _, l := range strings.Split(s, "\n") {
+		l = strings.TrimSuffix(l, "\r")
+		key, value, ok := strings.Cut(l, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			f.lines = append(f.lines, line{raw: l})
+			continue
+		}
+		f.lines = append(f.lines, line{key: key, value: value})
+	}
+	return f
+}
+
+// Read parses the options file at path.
+func Read(path string) (*File, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b), nil
+}
+
+// Keys returns the keys in file order.
+func (f *File) Keys() []string {
+	keys := make([]string, 0, len(f.lines))
+	for _, l := range f.lines {
+		if l.key != "" {
+			keys = append(keys, l.key)
+		}
+	}
+	return keys
+}
+
+// Get returns the value of key.
+func (f *File) Get(key string) (string, bool) {
+	for _, l := range f.lines {
+		if l.key == key {
+			return l.value, true
+		}
+	}
+	return "", false
+}
+
+// Set changes the value of key in place, or appends it when the file does not have it yet.
+func (f *File) Set(key string, value string) {
+	for i := range f.lines {
+		if f.lines[i].key == key {
+			f.lines[i].value = value
+			return
+		}
+	}
+	f.lines = append(f.lines, line{key: key, value: value})
+}
+
+// Bytes formats the file with the line endings it was read with.
+func (f *File) Bytes() []byte {
+	var sb strings.Builder
+	if f.bom {
+		sb.WriteString("\ufeff")
+	}
+	for i, l := range f.lines {
+		if l.key != "" {
+			sb.WriteString(l.key + ":" + l.value)
+		} else {
+			sb.WriteString(l.raw)
+		}
+		if i < len(f.lines)-1 || f.trailing {
+			sb.WriteString(f.eol)
+		}
+	}
+	return []byte(sb.String())
+}
+
+// Write saves the file to path through a temporary file, so the game never sees a partial file.
+func (f *File) Write(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, f.Bytes(), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
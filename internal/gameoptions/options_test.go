@@ -0,0 +1,136 @@
+package gameoptions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+func TestRoundTrip(t *testing.T) {
+	in := "\ufeffgfx_viewdistance:320\r\nunknown_key:a:b\r\n# not an option\r\n\r\ngame_language:en_US\r\n"
+	f := Parse([]byte(in))
+	if got := string(f.Bytes()); got != in {
+		t.Fatalf("round trip = %q", got)
+	}
+	if v, _ := f.Get("unknown_key"); v != "a:b" {
+		t.Fatalf("unknown_key = %q", v)
+	}
+
+	f.Set("gfx_viewdistance", "512")
+	f.Set("audio_main", "0.5")
+	want := "\ufeffgfx_viewdistance:512\r\nunknown_key:a:b\r\n# not an option\r\n\r\ngame_language:en_US\r\naudio_main:0.5\r\n"
+	if got := string(f.Bytes()); got != want {
+		t.Fatalf("after set = %q", got)
+	}
+
+	if got := string(Parse([]byte("a:1\nb:2")).Bytes()); got != "a:1\nb:2" {
+		t.Fatalf("no trailing newline = %q", got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "minecraftpe", "options.txt")
+	f := Parse(nil)
+	f.Set("gfx_gamma", "1")
+	if err := f.Write(p); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := got.Get("gfx_gamma"); !ok || v != "1" {
+		t.Fatalf("gfx_gamma = %q", v)
+	}
+	if _, err := os.Stat(p + ".tmp"); !os.IsNotExist(err) {
+		t.Fatal("temporary file left behind")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		key, value string
+		ok         bool
+	}{
+		{"gfx_viewdistance", "320", true},
+		{"gfx_viewdistance", "32", false},
+		{"gfx_viewdistance", "far", false},
+		{"gfx_field_of_view", "70.5", true},
+		{"gfx_vsync", "1", true},
+		{"gfx_vsync", "true", false},
+		{"graphics_mode", "1", true},
+		{"graphics_mode", "7", false},
+		{"game_language", "zh_CN", true},
+		{"game_language", "chinese", false},
+		{"keyboard_type_0_key.jump", "32", true},
+		{"keyboard_type_0_key.attack", "-99", true},
+		{"keyboard_type_0_key.jump", "space", false},
+		{"some_future_option", "anything", true},
+		{"some_future_option", "two\nlines", false},
+		{"bad:key", "1", false},
+	}
+	for _, c := range cases {
+		if err := Validate(c.key, c.value); (err == nil) != c.ok {
+			t.Errorf("Validate(%q, %q) = %v", c.key, c.value, err)
+		}
+	}
+}
+
+func TestTyped(t *testing.T) {
+	opts := Typed(Parse([]byte("keyboard_type_0_key.jump:32\nmystery:1\n")))
+	if len(opts) != 2 || opts[0].Group != GroupKeybindings || opts[0].Type != TypeKey || !opts[0].Known {
+		t.Fatalf("keybinding = %+v", opts)
+	}
+	if opts[1].Known || opts[1].Type != TypeString {
+		t.Fatalf("unknown = %+v", opts[1])
+	}
+	if !MatchKey("keyboard_type_0_*", "keyboard_type_0_key.jump") || MatchKey("gfx_gamma", "gfx_gamma2") {
+		t.Fatal("MatchKey")
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	f := Parse([]byte("gfx_viewdistance:320\naudio_main:1\nkeyboard_type_0_key.jump:32\nkeyboard_type_1_key.jump:40\n"))
+	got := Capture(f, PatternsForGroups([]string{GroupKeybindings}))
+	if len(got) != 2 || got[0].Key != "keyboard_type_0_key.jump" || got[1].Value != "40" {
+		t.Fatalf("capture = %+v", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "option_profiles.json")
+	list, err := LoadProfiles(path)
+	if err != nil || len(list.Profiles) != 0 {
+		t.Fatalf("load missing = %+v, %v", list, err)
+	}
+	list.DefaultID = "b"
+	list.Profiles = append(list.Profiles,
+		types.OptionProfile{ID: "b", Name: "Video", Options: Capture(f, PatternsForGroups([]string{GroupVideo}))},
+		types.OptionProfile{ID: "a", Name: "Keys", Options: got},
+	)
+	if err := SaveProfiles(path, list); err != nil {
+		t.Fatal(err)
+	}
+	list, err = LoadProfiles(path)
+	if err != nil || list.DefaultID != "b" || len(list.Profiles) != 2 || list.Profiles[0].Name != "Keys" {
+		t.Fatalf("reload = %+v, %v", list, err)
+	}
+	video, err := FindProfile(list, "b")
+	if err != nil || len(video.Options) != 1 {
+		t.Fatalf("video = %+v, %v", video, err)
+	}
+	if _, err := FindProfile(list, "c"); err != ErrProfileNotFound {
+		t.Fatalf("missing profile = %v", err)
+	}
+
+	target := Parse([]byte("gfx_viewdistance:128\nmystery:x\n"))
+	if err := ApplyProfile(target, video); err != nil {
+		t.Fatal(err)
+	}
+	if string(target.Bytes()) != "gfx_viewdistance:320\nmystery:x\n" {
+		t.Fatalf("applied = %q", target.Bytes())
+	}
+	if err := ApplyProfile(target, types.OptionProfile{Options: []types.GameOptionValue{{Key: "gfx_vsync", Value: "2"}}}); err == nil {
+		t.Fatal("invalid value applied")
+	}
+}
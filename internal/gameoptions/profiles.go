@@ -0,0 +1,104 @@
+package gameoptions
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+var ErrProfileNotFound = errors.New("gameoptions: profile not found")
+
+// LoadProfiles reads the option profiles stored at path. A missing file gives an empty list.
+func LoadProfiles(path string) (types.OptionProfileList, error) {
+	list := types.OptionProfileList{Profiles: []types.OptionProfile{}}
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return list, nil
+	}
+	if err != nil {
+		return list, err
+	}
+	if err := json.Unmarshal(b, &list); err != nil {
+		return types.OptionProfileList{Profiles: []types.OptionProfile{}}, err
+	}
+	if list.Profiles == nil {
+		list.Profiles = []types.OptionProfile{}
+	}
+	return list, nil
+}
+
+// SaveProfiles writes the option profiles to path.
+func SaveProfiles(path string, list types.OptionProfileList) error {
+	sort.SliceStable(list.Profiles, func(i, j int) bool {
+		return strings.ToLower(list.Profiles[i].Name) < strings.ToLower(list.Profiles[j].Name)
+	})
+	b, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// FindProfile returns the profile with the given ID.
+func FindProfile(list types.OptionProfileList, id string) (types.OptionProfile, error) {
+	for _, p := range list.Profiles {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return types.OptionProfile{}, ErrProfileNotFound
+}
+
+// PatternsForGroups returns the key patterns covering the schema options of the given groups.
+func PatternsForGroups(groups []string) []string {
+	var out []string
+	for _, g := range groups {
+		for _, d := range schema {
+			if d.Group == strings.TrimSpace(g) {
+				out = append(out, d.Key)
+			}
+		}
+	}
+	return out
+}
+
+// Capture returns the options of f matching any of patterns, in file order.
+func Capture(f *File, patterns []string) []types.GameOptionValue {
+	out := []types.GameOptionValue{}
+	for _, key := range f.Keys() {
+		for _, p := range patterns {
+			if MatchKey(p, key) {
+				v, _ := f.Get(key)
+				out = append(out, types.GameOptionValue{Key: key, Value: v})
+				break
+			}
+		}
+	}
+	return out
+}
+
+// ApplyProfile sets the options of p in f after checking all of them.
+func ApplyProfile(f *File, p types.OptionProfile) error {
+	for _, o := range p.Options {
+		if err := Validate(o.Key, o.Value); err != nil {
+			return err
+		}
+	}
+	for _, o := range p.Options {
+		f.Set(o.Key, o.Value)
+	}
+	return nil
+}
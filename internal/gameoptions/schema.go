@@ -0,0 +1,170 @@
+package gameoptions
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+const (
+	TypeInt    = "int"
+	TypeFloat  = "float"
+	TypeBool   = "bool"
+	TypeEnum   = "enum"
+	TypeString = "string"
+	TypeKey    = "key"
+)
+
+const (
+	GroupVideo       = "video"
+	GroupAudio       = "audio"
+	GroupControls    = "controls"
+	GroupGeneral     = "general"
+	GroupKeybindings = "keybindings"
+)
+
+var ErrInvalidValue = errors.New("gameoptions: invalid value")
+
+func intOption(key string, group string, min float64, max float64) types.GameOptionDef {
+	return types.GameOptionDef{Key: key, Group: group, Type: TypeInt, Min: min, Max: max}
+}
+
+func floatOption(key string, group string, min float64, max float64) types.GameOptionDef {
+	return types.GameOptionDef{Key: key, Group: group, Type: TypeFloat, Min: min, Max: max}
+}
+
+func boolOption(key string, group string) types.GameOptionDef {
+	return types.GameOptionDef{Key: key, Group: group, Type: TypeBool}
+}
+
+func enumOption(key string, group string, choices ...types.GameOptionChoice) types.GameOptionDef {
+	return types.GameOptionDef{Key: key, Group: group, Type: TypeEnum, Choices: choices}
+}
+
+// schema lists the options the launcher edits with typed controls. A key ending in "*" covers every key
+// with that prefix; keybindings are stored per input device as keyboard_type_<n>_key.<action> with the
+// key code as the value, negative for mouse buttons.
+var schema = []types.GameOptionDef{
+	intOption("gfx_viewdistance", GroupVideo, 64, 1536),
+	intOption("gfx_guiscale_offset", GroupVideo, -3, 3),
+	enumOption("graphics_mode", GroupVideo,
+		types.GameOptionChoice{Value: "0", Label: "Simple"},
+		types.GameOptionChoice{Value: "1", Label: "Fancy"},
+		types.GameOptionChoice{Value: "2", Label: "Advanced"},
+	),
+	boolOption("gfx_fancygraphics", GroupVideo),
+	floatOption("gfx_field_of_view", GroupVideo, 30, 110),
+	floatOption("gfx_gamma", GroupVideo, 0, 1),
+	boolOption("gfx_vsync", GroupVideo),
+	intOption("gfx_max_framerate", GroupVideo, 0, 1000),
+	enumOption("game_thirdperson", GroupVideo,
+		types.GameOptionChoice{Value: "0", Label: "First Person"},
+		types.GameOptionChoice{Value: "1", Label: "Third Person Back"},
+		types.GameOptionChoice{Value: "2", Label: "Third Person Front"},
+	),
+	floatOption("audio_main", GroupAudio, 0, 1),
+	floatOption("audio_music", GroupAudio, 0, 1),
+	floatOption("audio_sound", GroupAudio, 0, 1),
+	floatOption("ctrl_sensitivity2", GroupControls, 0, 1),
+	boolOption("ctrl_invertmouse", GroupControls),
+	boolOption("ctrl_autojump", GroupControls),
+	{Key: "game_language", Group: GroupGeneral, Type: TypeString},
+	{Key: "keyboard_type_*", Group: GroupKeybindings, Type: TypeKey},
+}
+
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}_[A-Z]{2}$`)
+
+// Schema returns the typed option definitions.
+func Schema() []types.GameOptionDef {
+	out := make([]types.GameOptionDef, len(schema))
+	copy(out, schema)
+	return out
+}
+
+// Lookup returns the definition covering key.
+func Lookup(key string) (types.GameOptionDef, bool) {
+	for _, d := range schema {
+		if prefix, ok := strings.CutSuffix(d.Key, "*"); ok {
+			if strings.HasPrefix(key, prefix) && (d.Type != TypeKey || strings.Contains(key, "_key.")) {
+				return d, true
+			}
+			continue
+		}
+		if d.Key == key {
+			return d, true
+		}
+	}
+	return types.GameOptionDef{}, false
+}
+
+// MatchKey reports whether key is selected by pattern, an exact key or a prefix ending in "*".
+func MatchKey(pattern string, key string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(key, prefix)
+	}
+	return pattern == key
+}
+
+// Validate checks value against the definition of key. Keys without a definition accept any value that
+// fits on one line.
+func Validate(key string, value string) error {
+	if strings.ContainsAny(key, ":\r\n") || strings.TrimSpace(key) == "" || strings.ContainsAny(value, "\r\n") {
+		return ErrInvalidValue
+	}
+	d, ok := Lookup(key)
+	if !ok {
+		return nil
+	}
+	inRange := func(n float64) bool { return d.Min == d.Max || (n >= d.Min && n <= d.Max) }
+	switch d.Type {
+	case TypeInt:
+		if n, err := strconv.Atoi(value); err == nil && inRange(float64(n)) {
+			return nil
+		}
+	case TypeFloat:
+		if n, err := strconv.ParseFloat(value, 64); err == nil && inRange(n) {
+			return nil
+		}
+	case TypeBool:
+		if value == "0" || value == "1" {
+			return nil
+		}
+	case TypeEnum:
+		for _, c := range d.Choices {
+			if c.Value == value {
+				return nil
+			}
+		}
+	case TypeString:
+		if d.Key != "game_language" || languagePattern.MatchString(value) {
+			return nil
+		}
+	case TypeKey:
+		valid := value != ""
+		for _, code := range strings.Split(value, ",") {
+			_, err := strconv.Atoi(strings.TrimSpace(code))
+			valid = valid && err == nil
+		}
+		if valid {
+			return nil
+		}
+	}
+	return ErrInvalidValue
+}
+
+// Typed returns the options of f in file order with the type and group of their definition.
+func Typed(f *File) []types.GameOption {
+	out := []types.GameOption{}
+	for _, key := range f.Keys() {
+		value, _ := f.Get(key)
+		o := types.GameOption{Key: key, Value: value, Type: TypeString}
+		if d, ok := Lookup(key); ok {
+			o.Type, o.Group, o.Known = d.Type, d.Group, true
+		}
+		out = append(out, o)
+	}
+	return out
+}
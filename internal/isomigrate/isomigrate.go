@@ -0,0 +1,315 @@
+// Package isomigrate moves game data between the shared GDK data folder and the private data folder of
+// an isolated version.
+//
+// Both folders have the same layout below their Users folder, so an item is identified by its path
+// relative to Users: a world of a player, the options of a player, or a pack in the shared com.mojang
+// folder. Apply copies the selected items and only touches the source after every copy and the commit
+// step succeeded; any failure before that restores the target to its previous state.
+package isomigrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+const (
+	KindWorld   = "world"
+	KindPack    = "pack"
+	KindOptions = "options"
+
+	ChoiceSkip      = "skip"
+	ChoiceOverwrite = "overwrite"
+	ChoiceKeepBoth  = "keep_both"
+
+	StatusSuccess    = "success"
+	StatusRolledBack = "rolled_back"
+	StatusFailed     = "failed"
+)
+
+var packRoots = []string{"resource_packs", "behavior_packs", "skin_packs", "development_resource_packs", "development_behavior_packs"}
+
+func sharedDir(users string) string {
+	return filepath.Join(users, "Shared", "games", "com.mojang")
+}
+
+func packUUID(dir string) string {
+	b, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return ""
+	}
+	var mf struct {
+		Header struct {
+			UUID string `json:"uuid"`
+		} `json:"header"`
+	}
+	_ = json.Unmarshal(utils.JsonCompatBytes(b), &mf)
+	return strings.ToLower(strings.TrimSpace(mf.Header.UUID))
+}
+
+func worldLabel(dir string) string {
+	if b, err := os.ReadFile(filepath.Join(dir, "levelname.txt")); err == nil {
+		if name := strings.TrimSpace(strings.SplitN(string(b), "\n", 2)[0]); name != "" {
+			return name
+		}
+	}
+	return filepath.Base(dir)
+}
+
+func size(p string) int64 {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return 0
+	}
+	if fi.IsDir() {
+		return utils.DirSize(p)
+	}
+	return fi.Size()
+}
+
+// Scan lists the items below srcUsers that can be migrated to dstUsers and marks those that collide
+// with an existing entry of the target: the same path, or for packs a pack with the same UUID.
+func Scan(srcUsers string, dstUsers string) []types.IsolationMigrationItem {
+	items := []types.IsolationMigrationItem{}
+	add := func(kind string, rel string, label string, choices []string) {
+		src := filepath.Join(srcUsers, rel)
+		it := types.IsolationMigrationItem{ID: filepath.ToSlash(rel), Kind: kind, Path: src, Label: label, Size: size(src), Choices: choices}
+		target := filepath.Join(dstUsers, rel)
+		if _, err := os.Stat(target); err == nil {
+			it.Conflict, it.TargetPath = true, target
+		}
+		items = append(items, it)
+	}
+
+	players, _ := os.ReadDir(srcUsers)
+	for _, pl := range players {
+		if !pl.IsDir() || strings.EqualFold(pl.Name(), "Shared") {
+			continue
+		}
+		mojang := filepath.Join(pl.Name(), "games", "com.mojang")
+		worlds, _ := os.ReadDir(filepath.Join(srcUsers, mojang, "minecraftWorlds"))
+		for _, w := range worlds {
+			if !w.IsDir() || strings.HasPrefix(w.Name(), ".") {
+				continue
+			}
+			rel := filepath.Join(mojang, "minecraftWorlds", w.Name())
+			add(KindWorld, rel, worldLabel(filepath.Join(srcUsers, rel)), []string{ChoiceSkip, ChoiceOverwrite, ChoiceKeepBoth})
+		}
+		opts := filepath.Join(mojang, "minecraftpe", "options.txt")
+		if utils.FileExists(filepath.Join(srcUsers, opts)) {
+			add(KindOptions, opts, pl.Name(), []string{ChoiceSkip, ChoiceOverwrite})
+		}
+	}
+
+	for _, root := range packRoots {
+		srcRoot := filepath.Join(sharedDir(srcUsers), root)
+		ents, err := os.ReadDir(srcRoot)
+		if err != nil {
+			continue
+		}
+		installed := map[string]string{}
+		dstRoot := filepath.Join(sharedDir(dstUsers), root)
+		if dents, err := os.ReadDir(dstRoot); err == nil {
+			for _, e := range dents {
+				if id := packUUID(filepath.Join(dstRoot, e.Name())); id != "" && e.IsDir() {
+					installed[id] = filepath.Join(dstRoot, e.Name())
+				}
+			}
+		}
+		for _, e := range ents {
+			if !e.IsDir() {
+				continue
+			}
+			rel, err := filepath.Rel(srcUsers, filepath.Join(srcRoot, e.Name()))
+			if err != nil {
+				continue
+			}
+			add(KindPack, rel, e.Name(), []string{ChoiceSkip, ChoiceOverwrite})
+			it := &items[len(items)-1]
+			if cur, ok := installed[packUUID(it.Path)]; ok && !it.Conflict {
+				it.Conflict, it.TargetPath = true, cur
+			}
+		}
+	}
+	for i := range items {
+		if items[i].Conflict {
+			items[i].TargetSummary = filepath.Base(items[i].TargetPath)
+			if items[i].Kind == KindWorld {
+				items[i].TargetSummary = worldLabel(items[i].TargetPath)
+			}
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
+
+// journal records what Apply changed in the target so it can be undone.
+type journal struct {
+	created []string
+	// moved maps a staging path back to the target entry it replaced.
+	moved [][2]string
+}
+
+func (j *journal) rollback() {
+	for i := len(j.created) - 1; i >= 0; i-- {
+		_ = os.RemoveAll(j.created[i])
+	}
+	for i := len(j.moved) - 1; i >= 0; i-- {
+		_ = os.Rename(j.moved[i][0], j.moved[i][1])
+	}
+}
+
+func (j *journal) discard() {
+	for _, m := range j.moved {
+		_ = os.RemoveAll(m[0])
+	}
+}
+
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func uniqueDir(dir string) string {
+	candidate := dir
+	for i := 1; ; i++ {
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s (%d)", dir, i)
+	}
+}
+
+// Apply migrates the items of req.ItemIDs, or every item when req.All is set, from srcUsers to dstUsers.
+// An empty selection migrates nothing and only runs commit.
+// Each selected conflict needs a resolution. commit runs once all items are copied; if it or any copy
+// fails, the target is restored and the source is left untouched. With req.Move the sources are deleted
+// afterwards; sources that cannot be deleted are reported in SourcesLeft.
+func Apply(srcUsers string, dstUsers string, req types.IsolationMigrationRequest, commit func() error, progress func(types.IsolationMigrationProgress)) types.IsolationMigrationResult {
+	res := types.IsolationMigrationResult{Status: StatusFailed, SourcesLeft: []string{}}
+	if progress == nil {
+		progress = func(types.IsolationMigrationProgress) {}
+	}
+	items := Scan(srcUsers, dstUsers)
+	if !req.All {
+		wanted := map[string]bool{}
+		for _, id := range req.ItemIDs {
+			wanted[strings.TrimSpace(id)] = true
+		}
+		var selected []types.IsolationMigrationItem
+		for _, it := range items {
+			if wanted[it.ID] {
+				selected = append(selected, it)
+				delete(wanted, it.ID)
+			}
+		}
+		if len(wanted) > 0 {
+			res.ErrorCode = "ERR_NOT_FOUND"
+			return res
+		}
+		items = selected
+	}
+	choices := map[string]string{}
+	for _, r := range req.Resolutions {
+		choices[strings.TrimSpace(r.ItemID)] = strings.TrimSpace(r.Choice)
+	}
+	for _, it := range items {
+		if !it.Conflict {
+			continue
+		}
+		choice, ok := choices[it.ID]
+		if !ok {
+			res.ErrorCode = "ERR_MIGRATION_CONFLICT_UNRESOLVED"
+			return res
+		}
+		valid := false
+		for _, c := range it.Choices {
+			valid = valid || c == choice
+		}
+		if !valid {
+			res.ErrorCode = "ERR_MIGRATION_INVALID_CONFLICT_RESOLUTION"
+			return res
+		}
+	}
+
+	var j journal
+	fail := func(code string) types.IsolationMigrationResult {
+		progress(types.IsolationMigrationProgress{Phase: "rolling_back", Current: len(items), Total: len(items), Ts: time.Now().UnixMilli()})
+		j.rollback()
+		res.Status, res.ErrorCode, res.Migrated = StatusRolledBack, code, 0
+		return res
+	}
+	var migrated []types.IsolationMigrationItem
+	for i, it := range items {
+		progress(types.IsolationMigrationProgress{Phase: "copying", Current: i + 1, Total: len(items), Path: it.ID, Ts: time.Now().UnixMilli()})
+		target := filepath.Join(dstUsers, filepath.FromSlash(it.ID))
+		if it.Conflict {
+			switch choices[it.ID] {
+			case ChoiceSkip:
+				res.Skipped++
+				continue
+			case ChoiceKeepBoth:
+				target = uniqueDir(target)
+			case ChoiceOverwrite:
+				staging := it.TargetPath + ".migrate-old"
+				_ = os.RemoveAll(staging)
+				if err := os.Rename(it.TargetPath, staging); err != nil {
+					return fail("ERR_WRITE_FILE")
+				}
+				j.moved = append(j.moved, [2]string{staging, it.TargetPath})
+			}
+		}
+		var err error
+		if it.Kind == KindOptions {
+			err = copyFile(it.Path, target)
+		} else {
+			err = utils.CopyDir(it.Path, target)
+		}
+		j.created = append(j.created, target)
+		if err != nil {
+			return fail("ERR_WRITE_FILE")
+		}
+		migrated = append(migrated, it)
+	}
+	if commit != nil {
+		if err := commit(); err != nil {
+			return fail("ERR_WRITE_VERSION_META")
+		}
+	}
+	j.discard()
+	res.Status, res.Migrated = StatusSuccess, len(migrated)
+
+	if req.Move {
+		for i, it := range migrated {
+			progress(types.IsolationMigrationProgress{Phase: "removing_sources", Current: i + 1, Total: len(migrated), Path: it.ID, Ts: time.Now().UnixMilli()})
+			if err := os.RemoveAll(it.Path); err != nil {
+				res.SourcesLeft = append(res.SourcesLeft, it.Path)
+			}
+		}
+	}
+	progress(types.IsolationMigrationProgress{Phase: "finalizing", Current: len(items), Total: len(items), Ts: time.Now().UnixMilli()})
+	return res
+}
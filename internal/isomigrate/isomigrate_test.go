@@ -0,0 +1,124 @@
+package isomigrate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+func writeFile(t *testing.T, p string, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func setup(t *testing.T) (string, string) {
+	t.Helper()
+	src, dst := filepath.Join(t.TempDir(), "Users"), filepath.Join(t.TempDir(), "Users")
+	mojang := filepath.Join("p1", "games", "com.mojang")
+	writeFile(t, filepath.Join(src, mojang, "minecraftWorlds", "w1", "levelname.txt"), "World One")
+	writeFile(t, filepath.Join(src, mojang, "minecraftWorlds", "w2", "levelname.txt"), "World Two")
+	writeFile(t, filepath.Join(src, mojang, "minecraftpe", "options.txt"), "gfx_fov:70")
+	writeFile(t, filepath.Join(src, "Shared", "games", "com.mojang", "behavior_packs", "bp", "manifest.json"), `{"header": {"uuid": "AAAA"}}`)
+
+	writeFile(t, filepath.Join(dst, mojang, "minecraftWorlds", "w1", "levelname.txt"), "Old One")
+	writeFile(t, filepath.Join(dst, "Shared", "games", "com.mojang", "behavior_packs", "other", "manifest.json"), `{"header": {"uuid": "aaaa"}}`)
+	return src, dst
+}
+
+func TestScan(t *testing.T) {
+	src, dst := setup(t)
+	items := Scan(src, dst)
+	if len(items) != 4 {
+		t.Fatalf("items = %+v", items)
+	}
+	byID := map[string]types.IsolationMigrationItem{}
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+	w1 := byID["p1/games/com.mojang/minecraftWorlds/w1"]
+	if !w1.Conflict || w1.Label != "World One" || w1.TargetSummary != "Old One" {
+		t.Fatalf("w1 = %+v", w1)
+	}
+	bp := byID["Shared/games/com.mojang/behavior_packs/bp"]
+	if !bp.Conflict || filepath.Base(bp.TargetPath) != "other" {
+		t.Fatalf("pack = %+v", bp)
+	}
+	if byID["p1/games/com.mojang/minecraftWorlds/w2"].Conflict {
+		t.Fatal("w2 should not conflict")
+	}
+}
+
+func TestApply(t *testing.T) {
+	src, dst := setup(t)
+	req := types.IsolationMigrationRequest{Move: true, All: true}
+	if res := Apply(src, dst, req, nil, nil); res.ErrorCode != "ERR_MIGRATION_CONFLICT_UNRESOLVED" {
+		t.Fatalf("unresolved: %+v", res)
+	}
+	req.Resolutions = []types.IsolationMigrationResolution{
+		{ItemID: "p1/games/com.mojang/minecraftWorlds/w1", Choice: ChoiceKeepBoth},
+		{ItemID: "Shared/games/com.mojang/behavior_packs/bp", Choice: ChoiceOverwrite},
+	}
+	res := Apply(src, dst, req, nil, nil)
+	if res.Status != StatusSuccess || res.Migrated != 4 || len(res.SourcesLeft) != 0 {
+		t.Fatalf("apply = %+v", res)
+	}
+	worlds := filepath.Join(dst, "p1", "games", "com.mojang", "minecraftWorlds")
+	for _, w := range []string{"w1", "w1 (1)", "w2"} {
+		if !utils.DirExists(filepath.Join(worlds, w)) {
+			t.Fatalf("missing world %s", w)
+		}
+	}
+	packs := filepath.Join(dst, "Shared", "games", "com.mojang", "behavior_packs")
+	if utils.DirExists(filepath.Join(packs, "other")) || !utils.DirExists(filepath.Join(packs, "bp")) {
+		t.Fatal("pack was not replaced")
+	}
+	if utils.DirExists(filepath.Join(src, "p1", "games", "com.mojang", "minecraftWorlds", "w2")) {
+		t.Fatal("moved source still exists")
+	}
+}
+
+func TestApplyRollsBack(t *testing.T) {
+	src, dst := setup(t)
+	req := types.IsolationMigrationRequest{Move: true, All: true, Resolutions: []types.IsolationMigrationResolution{
+		{ItemID: "p1/games/com.mojang/minecraftWorlds/w1", Choice: ChoiceOverwrite},
+		{ItemID: "Shared/games/com.mojang/behavior_packs/bp", Choice: ChoiceOverwrite},
+	}}
+	res := Apply(src, dst, req, func() error { return errors.New("meta") }, nil)
+	if res.Status != StatusRolledBack {
+		t.Fatalf("apply = %+v", res)
+	}
+	worlds := filepath.Join(dst, "p1", "games", "com.mojang", "minecraftWorlds")
+	if got, _ := os.ReadFile(filepath.Join(worlds, "w1", "levelname.txt")); string(got) != "Old One" {
+		t.Fatalf("w1 not restored: %q", got)
+	}
+	if utils.DirExists(filepath.Join(worlds, "w2")) || utils.FileExists(filepath.Join(dst, "p1", "games", "com.mojang", "minecraftpe", "options.txt")) {
+		t.Fatal("copies left behind")
+	}
+	if !utils.DirExists(filepath.Join(dst, "Shared", "games", "com.mojang", "behavior_packs", "other")) {
+		t.Fatal("replaced pack not restored")
+	}
+	if !utils.DirExists(filepath.Join(src, "p1", "games", "com.mojang", "minecraftWorlds", "w2")) {
+		t.Fatal("source touched")
+	}
+}
+
+func TestApplyEmptySelection(t *testing.T) {
+	src, dst := setup(t)
+	committed := false
+	res := Apply(src, dst, types.IsolationMigrationRequest{Move: true}, func() error { committed = true; return nil }, nil)
+	if res.Status != StatusSuccess || res.Migrated != 0 || !committed {
+		t.Fatalf("apply = %+v, committed %v", res, committed)
+	}
+	if !utils.DirExists(filepath.Join(src, "p1", "games", "com.mojang", "minecraftWorlds", "w2")) || utils.DirExists(filepath.Join(dst, "p1", "games", "com.mojang", "minecraftWorlds", "w2")) {
+		t.Fatal("an empty selection must not migrate anything")
+	}
+}
@@ -0,0 +1,271 @@
+// Package javapack converts Java Edition resource packs into Bedrock resource packs.
+//
+// Only textures carry over: Java models, block states, sounds, fonts and shaders have no Bedrock
+// equivalent a converter can produce, so they are listed in the report as untranslated. Textures are
+// moved to the Bedrock folder layout and renamed where the editions disagree on a name; anything the
+// converter cannot place is reported rather than guessed.
+package javapack
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	json "github.com/goccy/go-json"
+	"github.com/google/uuid"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+// Reasons an asset was left out of the converted pack.
+const (
+	ReasonNotTexture        = "NOT_TEXTURE"
+	ReasonOtherNamespace    = "OTHER_NAMESPACE"
+	ReasonUnsupportedFolder = "UNSUPPORTED_FOLDER"
+	ReasonNotPNG            = "NOT_PNG"
+	ReasonAnimation         = "ANIMATION"
+	ReasonInvalidIcon       = "INVALID_ICON"
+)
+
+const (
+	iconSize = 256
+	// maxEntrySize bounds how much of one archive entry is read.
+	maxEntrySize = 64 << 20
+)
+
+var (
+	ErrNotJavaPack = errors.New("javapack: pack.mcmeta not found")
+	ErrInvalidMeta = errors.New("javapack: invalid pack.mcmeta")
+)
+
+// description flattens a pack.mcmeta description, which is either a string or a text component.
+func description(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case []any:
+		var sb strings.Builder
+		for _, c := range x {
+			sb.WriteString(description(c))
+		}
+		return sb.String()
+	case map[string]any:
+		s, _ := x["text"].(string)
+		if extra, ok := x["extra"].([]any); ok {
+			s += description(extra)
+		}
+		return s
+	}
+	return ""
+}
+
+// stripFormatting removes the § formatting codes Java allows in descriptions.
+func stripFormatting(s string) string {
+	var sb strings.Builder
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		if rs[i] == '§' {
+			i++
+			continue
+		}
+		sb.WriteRune(rs[i])
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func readEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(io.LimitReader(rc, maxEntrySize))
+}
+
+// convertIcon turns pack.png into a square PNG of at most iconSize pixels, centering non-square images.
+func convertIcon(b []byte) ([]byte, error) {
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	src := img.Bounds()
+	side := min(max(src.Dx(), src.Dy()), iconSize)
+	w, h := side, src.Dy()*side/max(src.Dx(), 1)
+	if src.Dy() > src.Dx() {
+		w, h = src.Dx()*side/max(src.Dy(), 1), side
+	}
+	w, h = max(w, 1), max(h, 1)
+	out := image.NewNRGBA(image.Rect(0, 0, side, side))
+	ox, oy := (side-w)/2, (side-h)/2
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := img.At(src.Min.X+x*src.Dx()/w, src.Min.Y+y*src.Dy()/h)
+			out.SetNRGBA(ox+x, oy+y, color.NRGBAModel.Convert(c).(color.NRGBA))
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, out); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Convert builds a Bedrock resource pack archive from a Java resource pack archive. name is used for the
+// pack when set, otherwise the description is. The pack.mcmeta may sit in a top-level folder.
+func Convert(data []byte, name string) ([]byte, types.JavaPackConversion, error) {
+	rep := types.JavaPackConversion{Untranslated: []types.JavaPackAsset{}}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, rep, err
+	}
+	root := ""
+	var metaFile *zip.File
+	for _, f := range zr.File {
+		p := path.Clean(strings.ReplaceAll(f.Name, "\\", "/"))
+		if path.Base(p) == "pack.mcmeta" && (metaFile == nil || strings.Count(p, "/") < strings.Count(metaFile.Name, "/")) {
+			metaFile, root = f, strings.TrimSuffix(p, "pack.mcmeta")
+		}
+	}
+	if metaFile == nil {
+		return nil, rep, ErrNotJavaPack
+	}
+	mb, err := readEntry(metaFile)
+	if err != nil {
+		return nil, rep, err
+	}
+	var meta struct {
+		Pack struct {
+			PackFormat  int `json:"pack_format"`
+			Description any `json:"description"`
+		} `json:"pack"`
+	}
+	if err := json.Unmarshal(bytes.TrimPrefix(mb, []byte("\xef\xbb\xbf")), &meta); err != nil {
+		return nil, rep, ErrInvalidMeta
+	}
+	rep.PackFormat = meta.Pack.PackFormat
+	rep.Description = stripFormatting(description(meta.Pack.Description))
+	rep.Name = strings.TrimSpace(name)
+	if rep.Name == "" {
+		rep.Name = rep.Description
+	}
+	if rep.Name == "" {
+		rep.Name = "Converted Java Pack"
+	}
+
+	files := map[string][]byte{}
+	skip := func(p string, reason string) {
+		rep.Untranslated = append(rep.Untranslated, types.JavaPackAsset{Path: p, Reason: reason})
+	}
+	for _, f := range zr.File {
+		p := path.Clean(strings.ReplaceAll(f.Name, "\\", "/"))
+		if f.FileInfo().IsDir() || !strings.HasPrefix(p, root) {
+			continue
+		}
+		rel := strings.TrimPrefix(p, root)
+		if rel == "pack.mcmeta" || strings.HasPrefix(rel, "../") {
+			continue
+		}
+		if rel == "pack.png" {
+			b, err := readEntry(f)
+			if err == nil {
+				b, err = convertIcon(b)
+			}
+			if err != nil {
+				skip(rel, ReasonInvalidIcon)
+				continue
+			}
+			files["pack_icon.png"] = b
+			rep.IconConverted = true
+			continue
+		}
+		ns, texPath, ok := strings.Cut(strings.TrimPrefix(rel, "assets/"), "/textures/")
+		if !strings.HasPrefix(rel, "assets/") || !ok {
+			skip(rel, ReasonNotTexture)
+			continue
+		}
+		if ns != "minecraft" {
+			skip(rel, ReasonOtherNamespace)
+			continue
+		}
+		if strings.HasSuffix(texPath, ".mcmeta") {
+			skip(rel, ReasonAnimation)
+			continue
+		}
+		folder, file := path.Dir(texPath), path.Base(texPath)
+		target, mapped, matched := "", false, ""
+		for java, bedrock := range folderMap {
+			if (folder == java || strings.HasPrefix(folder, java+"/")) && len(java) > len(matched) {
+				target, mapped, matched = bedrock+strings.TrimPrefix(folder, java), true, java
+			}
+		}
+		if !mapped {
+			skip(rel, ReasonUnsupportedFolder)
+			continue
+		}
+		if !strings.EqualFold(path.Ext(file), ".png") {
+			skip(rel, ReasonNotPNG)
+			continue
+		}
+		b, err := readEntry(f)
+		if err != nil {
+			skip(rel, ReasonNotPNG)
+			continue
+		}
+		base := strings.TrimSuffix(file, path.Ext(file))
+		newBase := bedrockName(target, base)
+		if newBase != base {
+			rep.Renamed++
+		}
+		files["textures/"+target+"/"+newBase+".png"] = b
+		rep.Converted++
+	}
+	sort.Slice(rep.Untranslated, func(i, j int) bool { return rep.Untranslated[i].Path < rep.Untranslated[j].Path })
+
+	rep.UUID = uuid.NewString()
+	manifest := map[string]any{
+		"format_version": 2,
+		"header": map[string]any{
+			"name":               rep.Name,
+			"description":        rep.Description,
+			"uuid":               rep.UUID,
+			"version":            []int{1, 0, 0},
+			"min_engine_version": []int{1, 16, 0},
+		},
+		"modules": []map[string]any{{
+			"type":    "resources",
+			"uuid":    uuid.NewString(),
+			"version": []int{1, 0, 0},
+		}},
+	}
+	if files["manifest.json"], err = json.MarshalIndent(manifest, "", "  "); err != nil {
+		return nil, rep, err
+	}
+
+	names := make([]string, 0, len(files))
+	for n := range files {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, n := range names {
+		w, err := zw.Create(n)
+		if err != nil {
+			return nil, rep, err
+		}
+		if _, err := w.Write(files[n]); err != nil {
+			return nil, rep, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, rep, err
+	}
+	return buf.Bytes(), rep, nil
+}
@@ -0,0 +1,144 @@
+package javapack
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"testing"
+
+	json "github.com/goccy/go-json"
+)
+
+func pngBytes(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zipOf(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func readZip(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		out[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+	return out
+}
+
+func TestConvert(t *testing.T) {
+	tex := pngBytes(t, 16, 16)
+	in := zipOf(t, map[string][]byte{
+		"Faithful/pack.mcmeta": []byte(`{"pack": {"pack_format": 15, "description": [{"text": "§6Faithful "}, "x32"]}}`),
+		"Faithful/pack.png":    pngBytes(t, 64, 32),
+		"Faithful/assets/minecraft/textures/block/oak_planks.png":          tex,
+		"Faithful/assets/minecraft/textures/block/light_gray_wool.png":     tex,
+		"Faithful/assets/minecraft/textures/block/amethyst_block.png":      tex,
+		"Faithful/assets/minecraft/textures/item/bow.png":                  tex,
+		"Faithful/assets/minecraft/textures/models/armor/iron_layer_1.png": tex,
+		"Faithful/assets/minecraft/textures/block/water_still.png.mcmeta":  []byte(`{}`),
+		"Faithful/assets/minecraft/textures/gui/widgets.png":               tex,
+		"Faithful/assets/minecraft/models/block/stone.json":                []byte(`{}`),
+		"Faithful/assets/modid/textures/block/thing.png":                   tex,
+		"Faithful/assets/minecraft/textures/block/notes.txt":               []byte("x"),
+	})
+	out, rep, err := Convert(in, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Name != "Faithful x32" || rep.PackFormat != 15 || rep.Converted != 5 || rep.Renamed != 3 || !rep.IconConverted {
+		t.Fatalf("report = %+v", rep)
+	}
+	reasons := map[string]string{}
+	for _, a := range rep.Untranslated {
+		reasons[a.Path] = a.Reason
+	}
+	want := map[string]string{
+		"assets/minecraft/textures/block/water_still.png.mcmeta": ReasonAnimation,
+		"assets/minecraft/textures/gui/widgets.png":              ReasonUnsupportedFolder,
+		"assets/minecraft/models/block/stone.json":               ReasonNotTexture,
+		"assets/modid/textures/block/thing.png":                  ReasonOtherNamespace,
+		"assets/minecraft/textures/block/notes.txt":              ReasonNotPNG,
+	}
+	if len(reasons) != len(want) {
+		t.Fatalf("untranslated = %+v", rep.Untranslated)
+	}
+	for p, r := range want {
+		if reasons[p] != r {
+			t.Errorf("%s: reason %q, want %q", p, reasons[p], r)
+		}
+	}
+
+	files := readZip(t, out)
+	for _, p := range []string{
+		"manifest.json",
+		"pack_icon.png",
+		"textures/blocks/planks_oak.png",
+		"textures/blocks/wool_colored_silver.png",
+		"textures/blocks/amethyst_block.png",
+		"textures/items/bow_standby.png",
+		"textures/models/armor/iron_layer_1.png",
+	} {
+		if _, ok := files[p]; !ok {
+			t.Errorf("missing %s", p)
+		}
+	}
+	icon, err := png.Decode(bytes.NewReader(files["pack_icon.png"]))
+	if err != nil || icon.Bounds().Dx() != 64 || icon.Bounds().Dy() != 64 {
+		t.Fatalf("icon = %v, %v", icon.Bounds(), err)
+	}
+	var mf struct {
+		Header struct {
+			Name string `json:"name"`
+			UUID string `json:"uuid"`
+		} `json:"header"`
+		Modules []struct {
+			Type string `json:"type"`
+		} `json:"modules"`
+	}
+	if err := json.Unmarshal(files["manifest.json"], &mf); err != nil {
+		t.Fatal(err)
+	}
+	if mf.Header.Name != "Faithful x32" || mf.Header.UUID != rep.UUID || len(mf.Modules) != 1 || mf.Modules[0].Type != "resources" {
+		t.Fatalf("manifest = %+v", mf)
+	}
+}
+
+func TestConvertRejectsNonJavaPack(t *testing.T) {
+	in := zipOf(t, map[string][]byte{"manifest.json": []byte(`{}`)})
+	if _, _, err := Convert(in, "x"); err != ErrNotJavaPack {
+		t.Fatalf("err = %v", err)
+	}
+	in = zipOf(t, map[string][]byte{"pack.mcmeta": []byte(`not json`)})
+	if _, _, err := Convert(in, "x"); err != ErrInvalidMeta {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package javapack
+
+import "strings"
+
+// folderMap maps Java texture folders to their Bedrock counterparts. Java renamed blocks and items to
+// the singular form in 1.13; both spellings are accepted. Folders missing here, such as gui, font and
+// particle, use a different layout or atlas in Bedrock and are reported instead of converted.
+var folderMap = map[string]string{
+	"block":        "blocks",
+	"blocks":       "blocks",
+	"item":         "items",
+	"items":        "items",
+	"entity":       "entity",
+	"environment":  "environment",
+	"painting":     "painting",
+	"models/armor": "models/armor",
+	"colormap":     "colormap",
+	"map":          "map",
+	"misc":         "misc",
+	"trims":        "trims",
+}
+
+// textureNames maps Java texture names that Bedrock spells differently, by Bedrock folder.
+var textureNames = map[string]map[string]string{
+	"blocks": {
+		"grass_block_top":       "grass_top",
+		"grass_block_side":      "grass_side_carried",
+		"grass_block_snow":      "grass_side_snowed",
+		"stone_bricks":          "stonebrick",
+		"mossy_stone_bricks":    "stonebrick_mossy",
+		"cracked_stone_bricks":  "stonebrick_cracked",
+		"chiseled_stone_bricks": "stonebrick_carved",
+		"bricks":                "brick",
+		"granite":               "stone_granite",
+		"polished_granite":      "stone_granite_smooth",
+		"diorite":               "stone_diorite",
+		"polished_diorite":      "stone_diorite_smooth",
+		"andesite":              "stone_andesite",
+		"polished_andesite":     "stone_andesite_smooth",
+		"mossy_cobblestone":     "cobblestone_mossy",
+		"sandstone":             "sandstone_normal",
+		"cut_sandstone":         "sandstone_smooth",
+		"chiseled_sandstone":    "sandstone_carved",
+		"terracotta":            "hardened_clay",
+		"water_still":           "water_still_grey",
+		"water_flow":            "water_flow_grey",
+		"furnace_front":         "furnace_front_off",
+		"torch":                 "torch_on",
+		"sugar_cane":            "reeds",
+		"short_grass":           "tallgrass",
+		"grass":                 "tallgrass",
+		"nether_bricks":         "nether_brick",
+		"spawner":               "mob_spawner",
+	},
+	"items": {
+		"bow":             "bow_standby",
+		"compass_00":      "compass_item",
+		"clock_00":        "clock_item",
+		"oak_door":        "door_wood",
+		"iron_door":       "door_iron",
+		"cooked_beef":     "beef_cooked",
+		"beef":            "beef_raw",
+		"cooked_chicken":  "chicken_cooked",
+		"chicken":         "chicken_raw",
+		"cooked_porkchop": "porkchop_cooked",
+		"porkchop":        "porkchop_raw",
+		"cod":             "fish_raw",
+		"cooked_cod":      "fish_cooked",
+		"salmon":          "fish_salmon_raw",
+		"cooked_salmon":   "fish_salmon_cooked",
+		"golden_apple":    "apple_golden",
+		"wheat_seeds":     "seeds_wheat",
+		"water_bucket":    "bucket_water",
+		"lava_bucket":     "bucket_lava",
+		"milk_bucket":     "bucket_milk",
+		"bucket":          "bucket_empty",
+		"writable_book":   "book_writable",
+		"written_book":    "book_written",
+		"enchanted_book":  "book_enchanted",
+		"book":            "book_normal",
+		"fishing_rod":     "fishing_rod_uncast",
+		"glass_bottle":    "potion_bottle_empty",
+		"map":             "map_empty",
+		"filled_map":      "map_filled",
+	},
+}
+
+// woodNames maps Java wood names to the spelling Bedrock uses in its older texture names.
+var woodNames = map[string]string{
+	"oak":      "oak",
+	"spruce":   "spruce",
+	"birch":    "birch",
+	"jungle":   "jungle",
+	"acacia":   "acacia",
+	"dark_oak": "big_oak",
+}
+
+// colorNames maps Java dye colors to the spelling Bedrock uses in its texture names.
+var colorNames = map[string]string{
+	"white": "white", "orange": "orange", "magenta": "magenta", "light_blue": "light_blue",
+	"yellow": "yellow", "lime": "lime", "pink": "pink", "gray": "gray", "light_gray": "silver",
+	"cyan": "cyan", "purple": "purple", "blue": "blue", "brown": "brown", "green": "green",
+	"red": "red", "black": "black",
+}
+
+// bedrockName returns the Bedrock name of a texture in a Bedrock folder. Names Bedrock shares with Java,
+// which includes every texture added since the editions were aligned, are returned unchanged.
+func bedrockName(folder string, name string) string {
+	if n, ok := textureNames[folder][name]; ok {
+		return n
+	}
+	if folder != "blocks" {
+		return name
+	}
+	for java, bedrock := range woodNames {
+		switch name {
+		case java + "_planks":
+			return "planks_" + bedrock
+		case java + "_log":
+			return "log_" + bedrock
+		case java + "_log_top":
+			return "log_" + bedrock + "_top"
+		case java + "_leaves":
+			return "leaves_" + bedrock
+		case java + "_sapling":
+			return "sapling_" + bedrock
+		}
+	}
+	for java, bedrock := range colorNames {
+		rest, ok := strings.CutPrefix(name, java+"_")
+		if !ok {
+			continue
+		}
+		switch rest {
+		case "wool":
+			return "wool_colored_" + bedrock
+		case "concrete":
+			return "concrete_" + bedrock
+		case "concrete_powder":
+			return "concrete_powder_" + bedrock
+		case "stained_glass":
+			return "glass_" + bedrock
+		case "terracotta":
+			return "hardened_clay_stained_" + bedrock
+		case "glazed_terracotta":
+			return "glazed_terracotta_" + bedrock
+		}
+	}
+	return name
+}
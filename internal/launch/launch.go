@@ -4,6 +4,7 @@ import (
 	"context"
 	"path/filepath"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 	"unsafe"
@@ -28,6 +29,30 @@ var (
 	procIsWindowVisible = user32.NewProc("IsWindowVisible")
 )
 
+// SessionHooks are told about the game sessions watched by MonitorGameProcess. OnStart receives a
+// context that is canceled when the session ends; both hooks run on their own goroutine.
+type SessionHooks struct {
+	OnStart func(ctx context.Context, versionDir string, started time.Time)
+	OnExit  func(versionDir string, started time.Time)
+}
+
+var (
+	sessionHooksMu sync.RWMutex
+	sessionHooks   SessionHooks
+)
+
+func SetSessionHooks(h SessionHooks) {
+	sessionHooksMu.Lock()
+	defer sessionHooksMu.Unlock()
+	sessionHooks = h
+}
+
+func currentSessionHooks() SessionHooks {
+	sessionHooksMu.RLock()
+	defer sessionHooksMu.RUnlock()
+	return sessionHooks
+}
+
 func FindWindowByTitleExact(title string) bool {
 	t, err := syscall.UTF16PtrFromString(title)
 	if err != nil {
@@ -216,6 +241,14 @@ func MonitorGameProcess(ctx context.Context, versionDir string, launchPID int) {
 
 	application.Get().Event.Emit(EventMcLaunchDone, struct{}{})
 
+	started := time.Now()
+	hooks := currentSessionHooks()
+	sessionCtx, endSession := context.WithCancel(ctx)
+	defer endSession()
+	if hooks.OnStart != nil {
+		go hooks.OnStart(sessionCtx, versionDir, started)
+	}
+
 	if visible {
 		w := application.Get().Window.Current()
 		if w != nil {
@@ -232,6 +265,10 @@ func MonitorGameProcess(ctx context.Context, versionDir string, launchPID int) {
 			return
 		case <-ticker.C:
 			if !isGameRunning(versionDir) {
+				endSession()
+				if hooks.OnExit != nil {
+					go hooks.OnExit(versionDir, started)
+				}
 				discord.SetLauncherIdle()
 				// 似乎会出现Bug，后续修复
 				//w := application.Get().Window.Current()
@@ -0,0 +1,125 @@
+package leveldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+// ErrCorrupt is returned when a file of the database does not follow the LevelDB format.
+var ErrCorrupt = errors.New("leveldb: corrupt database")
+
+// Block compression types. Bedrock's fork of LevelDB adds the two zlib variants; 4 is what current
+// versions of the game write.
+const (
+	compressionNone    = 0
+	compressionSnappy  = 1
+	compressionZlib    = 2
+	compressionZlibRaw = 4
+)
+
+const (
+	blockTrailerLen = 5
+	footerLen       = 48
+	tableMagic      = 0xdb4775248b80fb57
+)
+
+type keyKind uint8
+
+const (
+	kindDelete keyKind = 0
+	kindValue  keyKind = 1
+)
+
+// maxSequence is used to build lookup keys that sort before every stored version of a user key.
+const maxSequence = (uint64(1) << 56) - 1
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+func maskCRC(c uint32) uint32 {
+	return (c>>15 | c<<17) + 0xa282ead8
+}
+
+func unmaskCRC(c uint32) uint32 {
+	rot := c - 0xa282ead8
+	return rot>>17 | rot<<15
+}
+
+// makeInternalKey appends the sequence number and kind to a user key, the form every key takes in tables
+// and in the memtable.
+func makeInternalKey(ukey []byte, seq uint64, kind keyKind) []byte {
+	out := make([]byte, len(ukey)+8)
+	copy(out, ukey)
+	binary.LittleEndian.PutUint64(out[len(ukey):], seq<<8|uint64(kind))
+	return out
+}
+
+func parseInternalKey(ikey []byte) ([]byte, uint64, keyKind, bool) {
+	if len(ikey) < 8 {
+		return nil, 0, 0, false
+	}
+	n := len(ikey) - 8
+	tag := binary.LittleEndian.Uint64(ikey[n:])
+	return ikey[:n], tag >> 8, keyKind(tag & 0xff), true
+}
+
+// compareInternal orders internal keys by user key ascending and then by sequence number descending, so
+// the newest version of a key comes first.
+func compareInternal(a []byte, b []byte) int {
+	ua, ta := splitInternalKey(a)
+	ub, tb := splitInternalKey(b)
+	if c := bytes.Compare(ua, ub); c != 0 {
+		return c
+	}
+	switch {
+	case ta > tb:
+		return -1
+	case ta < tb:
+		return 1
+	}
+	return 0
+}
+
+func splitInternalKey(ikey []byte) ([]byte, uint64) {
+	if len(ikey) < 8 {
+		return ikey, 0
+	}
+	n := len(ikey) - 8
+	return ikey[:n], binary.LittleEndian.Uint64(ikey[n:])
+}
+
+type blockHandle struct {
+	offset uint64
+	size   uint64
+}
+
+func decodeBlockHandle(b []byte) (blockHandle, int) {
+	offset, n := binary.Uvarint(b)
+	if n <= 0 {
+		return blockHandle{}, 0
+	}
+	size, m := binary.Uvarint(b[n:])
+	if m <= 0 {
+		return blockHandle{}, 0
+	}
+	return blockHandle{offset: offset, size: size}, n + m
+}
+
+func appendBlockHandle(dst []byte, h blockHandle) []byte {
+	dst = binary.AppendUvarint(dst, h.offset)
+	return binary.AppendUvarint(dst, h.size)
+}
+
+func getLengthPrefixed(b []byte) ([]byte, []byte, bool) {
+	n, m := binary.Uvarint(b)
+	if m <= 0 || uint64(len(b)-m) < n {
+		return nil, nil, false
+	}
+	return b[m : m+int(n)], b[m+int(n):], true
+}
+
+func appendLengthPrefixed(dst []byte, b []byte) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(b)))
+	return append(dst, b...)
+}
@@ -0,0 +1,365 @@
+// Package leveldb reads the LevelDB databases Minecraft Bedrock stores worlds in.
+//
+// It implements the on-disk format directly: the MANIFEST named by CURRENT lists the live table files,
+// the write-ahead logs hold the most recent writes, and table blocks may be stored uncompressed, with
+// Snappy, or with the zlib and raw deflate compressors of Mojang's fork. The database is opened without
+// taking the LOCK file, so it must not be used while the game has the world open.
+package leveldb
+
+import (
+	"bytes"
+	"container/heap"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var ErrNotFound = errors.New("leveldb: not found")
+
+type memEntry struct {
+	ikey  []byte
+	value []byte
+}
+
+// DB is a read-only view of a database directory as it was when Open was called.
+type DB struct {
+	dir    string
+	state  *manifestState
+	levels [numLevels][]fileMeta
+	mem    []memEntry
+	// logNum is the newest log replayed by Open, the one further writes are appended to.
+	logNum uint64
+
+	mu     sync.Mutex
+	tables map[uint64]*table
+}
+
+// Open loads the manifest and replays the write-ahead logs of the database in dir.
+func Open(dir string) (*DB, error) {
+	st, err := loadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	db := &DB{dir: dir, state: st, levels: st.levels(), tables: map[uint64]*table{}}
+	if err := db.loadLogs(); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func (db *DB) loadLogs() error {
+	ents, err := os.ReadDir(db.dir)
+	if err != nil {
+		return err
+	}
+	var nums []uint64
+	for _, e := range ents {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".log") {
+			continue
+		}
+		num, err := strconv.ParseUint(strings.TrimSuffix(name, ".log"), 10, 64)
+		if err != nil {
+			continue
+		}
+		if num >= db.state.logNumber || (num != 0 && num == db.state.prevLogNumber) {
+			nums = append(nums, num)
+		}
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	db.logNum = db.state.logNumber
+	if len(nums) > 0 && nums[len(nums)-1] > db.logNum {
+		db.logNum = nums[len(nums)-1]
+	}
+	for _, num := range nums {
+		data, err := os.ReadFile(filepath.Join(db.dir, fmt.Sprintf("%06d.log", num)))
+		if err != nil {
+			return err
+		}
+		err = readLogRecords(data, func(rec []byte) error {
+			entries, err := decodeBatch(rec)
+			if err != nil {
+				return err
+			}
+			for _, e := range entries {
+				db.mem = append(db.mem, memEntry{ikey: makeInternalKey(e.key, e.seq, e.kind), value: e.value})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	sort.SliceStable(db.mem, func(i, j int) bool { return compareInternal(db.mem[i].ikey, db.mem[j].ikey) < 0 })
+	return nil
+}
+
+// Close releases the table files opened so far.
+func (db *DB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var first error
+	for num, t := range db.tables {
+		if err := t.close(); err != nil && first == nil {
+			first = err
+		}
+		delete(db.tables, num)
+	}
+	return first
+}
+
+// Dir returns the database directory.
+func (db *DB) Dir() string { return db.dir }
+
+func (db *DB) table(num uint64) (*table, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if t, ok := db.tables[num]; ok {
+		return t, nil
+	}
+	var t *table
+	var err error
+	for _, ext := range []string{".ldb", ".sst"} {
+		t, err = openTable(filepath.Join(db.dir, fmt.Sprintf("%06d%s", num, ext)))
+		if !errors.Is(err, os.ErrNotExist) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	db.tables[num] = t
+	return t, nil
+}
+
+// Get returns the value stored for key, or ErrNotFound.
+func (db *DB) Get(key []byte) ([]byte, error) {
+	lookup := makeInternalKey(key, maxSequence, kindValue)
+	i := sort.Search(len(db.mem), func(i int) bool { return compareInternal(db.mem[i].ikey, lookup) >= 0 })
+	if i < len(db.mem) {
+		uk, _, kind, _ := parseInternalKey(db.mem[i].ikey)
+		if bytes.Equal(uk, key) {
+			if kind == kindDelete {
+				return nil, ErrNotFound
+			}
+			return db.mem[i].value, nil
+		}
+	}
+	for level, files := range db.levels {
+		for _, f := range files {
+			fs, _ := splitInternalKey(f.smallest)
+			fl, _ := splitInternalKey(f.largest)
+			if bytes.Compare(key, fs) < 0 || bytes.Compare(key, fl) > 0 {
+				continue
+			}
+			t, err := db.table(f.num)
+			if err != nil {
+				return nil, err
+			}
+			v, kind, ok, err := t.get(key)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				if kind == kindDelete {
+					return nil, ErrNotFound
+				}
+				return v, nil
+			}
+			// Files of deeper levels do not overlap, so at most one of them can hold the key.
+			if level > 0 {
+				break
+			}
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// TableSize returns the total size of the live table files, which is what the world takes on disk once
+// the logs are compacted.
+func (db *DB) TableSize() int64 {
+	var n int64
+	for _, f := range db.state.files {
+		n += int64(f.size)
+	}
+	return n
+}
+
+// childIter is implemented by the memtable, table and level iterators merged by Iterator.
+type childIter interface {
+	next() bool
+	key() []byte
+	value() []byte
+	error() error
+}
+
+type memIter struct {
+	entries []memEntry
+	pos     int
+}
+
+func (it *memIter) next() bool {
+	if it.pos >= len(it.entries) {
+		return false
+	}
+	it.pos++
+	return true
+}
+
+func (it *memIter) key() []byte   { return it.entries[it.pos-1].ikey }
+func (it *memIter) value() []byte { return it.entries[it.pos-1].value }
+func (it *memIter) error() error  { return nil }
+
+// levelIter walks the non-overlapping files of one level in key order, opening each file on demand.
+type levelIter struct {
+	db    *DB
+	files []fileMeta
+	cur   *tableIter
+	err   error
+}
+
+func (it *levelIter) next() bool {
+	for it.err == nil {
+		if it.cur != nil {
+			if it.cur.next() {
+				return true
+			}
+			if it.err = it.cur.error(); it.err != nil {
+				return false
+			}
+		}
+		if len(it.files) == 0 {
+			return false
+		}
+		t, err := it.db.table(it.files[0].num)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.files = it.files[1:]
+		it.cur = t.iter()
+	}
+	return false
+}
+
+func (it *levelIter) key() []byte   { return it.cur.key() }
+func (it *levelIter) value() []byte { return it.cur.value() }
+func (it *levelIter) error() error  { return it.err }
+
+type iterHeap []childIter
+
+func (h iterHeap) Len() int           { return len(h) }
+func (h iterHeap) Less(i, j int) bool { return compareInternal(h[i].key(), h[j].key()) < 0 }
+func (h iterHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *iterHeap) Push(x any)        { *h = append(*h, x.(childIter)) }
+func (h *iterHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// Iterator visits the live keys of the database in ascending order, yielding only the newest value of
+// each key and hiding deleted keys.
+type Iterator struct {
+	h       iterHeap
+	pending []childIter
+	lastKey []byte
+	hasLast bool
+	k, v    []byte
+	err     error
+}
+
+// NewIterator returns an iterator positioned before the first key.
+func (db *DB) NewIterator() *Iterator {
+	it := &Iterator{}
+	if len(db.mem) > 0 {
+		it.pending = append(it.pending, &memIter{entries: db.mem})
+	}
+	for _, f := range db.levels[0] {
+		f := f
+		it.pending = append(it.pending, &levelIter{db: db, files: []fileMeta{f}})
+	}
+	for l := 1; l < numLevels; l++ {
+		if len(db.levels[l]) > 0 {
+			it.pending = append(it.pending, &levelIter{db: db, files: db.levels[l]})
+		}
+	}
+	return it
+}
+
+// Next advances to the next key and reports whether there is one.
+func (it *Iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.pending != nil {
+		for _, c := range it.pending {
+			if c.next() {
+				it.h = append(it.h, c)
+			} else if err := c.error(); err != nil {
+				it.err = err
+				return false
+			}
+		}
+		it.pending = nil
+		heap.Init(&it.h)
+	}
+	for it.h.Len() > 0 {
+		c := it.h[0]
+		ikey, val := c.key(), c.value()
+		ukey, _, kind, ok := parseInternalKey(ikey)
+		if !ok {
+			it.err = ErrCorrupt
+			return false
+		}
+		skip := it.hasLast && bytes.Equal(ukey, it.lastKey)
+		if !skip {
+			it.lastKey = append(it.lastKey[:0], ukey...)
+			it.hasLast = true
+		}
+		emit := !skip && kind == kindValue
+		if emit {
+			it.k = append([]byte(nil), ukey...)
+			it.v = val
+		}
+		if c.next() {
+			heap.Fix(&it.h, 0)
+		} else {
+			if err := c.error(); err != nil {
+				it.err = err
+				return false
+			}
+			heap.Pop(&it.h)
+		}
+		if emit {
+			return true
+		}
+	}
+	return false
+}
+
+// Key returns the current key. The slice stays valid after Next.
+func (it *Iterator) Key() []byte { return it.k }
+
+// Value returns the current value. The slice must not be modified.
+func (it *Iterator) Value() []byte { return it.v }
+
+// Err returns the first error met while iterating.
+func (it *Iterator) Err() error { return it.err }
+
+// ForEach calls fn for every live key in ascending order until fn returns an error.
+func (db *DB) ForEach(fn func(key []byte, value []byte) error) error {
+	it := db.NewIterator()
+	for it.Next() {
+		if err := fn(it.Key(), it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
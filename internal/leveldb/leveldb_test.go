@@ -0,0 +1,302 @@
+package leveldb
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testKV struct {
+	key   string
+	seq   uint64
+	kind  keyKind
+	value string
+}
+
+// writeTestTable writes a table file the way Bedrock does: raw deflate blocks holding a few entries each.
+func writeTestTable(t *testing.T, path string, entries []testKV) (smallest []byte, largest []byte) {
+	t.Helper()
+	var file bytes.Buffer
+	var index []byte
+	var indexRestarts []uint32
+	writeBlock := func(raw []byte, compression byte) blockHandle {
+		data := raw
+		if compression == compressionZlibRaw {
+			var z bytes.Buffer
+			w, _ := flate.NewWriter(&z, flate.BestSpeed)
+			w.Write(raw)
+			w.Close()
+			data = z.Bytes()
+		}
+		h := blockHandle{offset: uint64(file.Len()), size: uint64(len(data))}
+		file.Write(data)
+		file.WriteByte(compression)
+		sum := crc32.Update(crc32.Checksum(data, crcTable), crcTable, []byte{compression})
+		binary.Write(&file, binary.LittleEndian, maskCRC(sum))
+		return h
+	}
+	finishBlock := func(buf []byte, restarts []uint32) []byte {
+		for _, r := range restarts {
+			buf = binary.LittleEndian.AppendUint32(buf, r)
+		}
+		return binary.LittleEndian.AppendUint32(buf, uint32(len(restarts)))
+	}
+	addEntry := func(buf []byte, restarts *[]uint32, key []byte, value []byte) []byte {
+		*restarts = append(*restarts, uint32(len(buf)))
+		buf = binary.AppendUvarint(buf, 0)
+		buf = binary.AppendUvarint(buf, uint64(len(key)))
+		buf = binary.AppendUvarint(buf, uint64(len(value)))
+		buf = append(buf, key...)
+		return append(buf, value...)
+	}
+
+	for start := 0; start < len(entries); start += 2 {
+		end := start + 2
+		if end > len(entries) {
+			end = len(entries)
+		}
+		var buf []byte
+		var restarts []uint32
+		var last []byte
+		for _, e := range entries[start:end] {
+			last = makeInternalKey([]byte(e.key), e.seq, e.kind)
+			if smallest == nil {
+				smallest = last
+			}
+			buf = addEntry(buf, &restarts, last, []byte(e.value))
+		}
+		largest = last
+		h := writeBlock(finishBlock(buf, restarts), compressionZlibRaw)
+		index = addEntry(index, &indexRestarts, last, appendBlockHandle(nil, h))
+	}
+	meta := writeBlock(finishBlock(nil, nil), compressionNone)
+	idx := writeBlock(finishBlock(index, indexRestarts), compressionNone)
+	footer := appendBlockHandle(nil, meta)
+	footer = appendBlockHandle(footer, idx)
+	footer = append(footer, make([]byte, footerLen-8-len(footer))...)
+	footer = binary.LittleEndian.AppendUint64(footer, tableMagic)
+	file.Write(footer)
+	if err := os.WriteFile(path, file.Bytes(), 0o644); err != nil {
+		t.Fatalf("write table: %v", err)
+	}
+	return smallest, largest
+}
+
+func writeTestDB(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	s5, l5 := writeTestTable(t, filepath.Join(dir, "000005.ldb"), []testKV{
+		{"apple", 1, kindValue, "old-apple"},
+		{"banana", 2, kindValue, "banana"},
+		{"cherry", 3, kindValue, "cherry"},
+	})
+	s6, l6 := writeTestTable(t, filepath.Join(dir, "000006.ldb"), []testKV{
+		{"apple", 4, kindValue, "new-apple"},
+		{"cherry", 5, kindDelete, ""},
+	})
+
+	var edit []byte
+	edit = binary.AppendUvarint(edit, tagComparator)
+	edit = appendLengthPrefixed(edit, []byte("leveldb.BytewiseComparator"))
+	edit = binary.AppendUvarint(edit, tagLogNumber)
+	edit = binary.AppendUvarint(edit, 7)
+	edit = binary.AppendUvarint(edit, tagNextFileNumber)
+	edit = binary.AppendUvarint(edit, 9)
+	edit = binary.AppendUvarint(edit, tagLastSequence)
+	edit = binary.AppendUvarint(edit, 5)
+	for _, f := range []struct {
+		level  int
+		num    uint64
+		s, l   []byte
+		layout string
+	}{{1, 5, s5, l5, "000005.ldb"}, {0, 6, s6, l6, "000006.ldb"}} {
+		fi, err := os.Stat(filepath.Join(dir, f.layout))
+		if err != nil {
+			t.Fatalf("stat table: %v", err)
+		}
+		edit = binary.AppendUvarint(edit, tagNewFile)
+		edit = binary.AppendUvarint(edit, uint64(f.level))
+		edit = binary.AppendUvarint(edit, f.num)
+		edit = binary.AppendUvarint(edit, uint64(fi.Size()))
+		edit = appendLengthPrefixed(edit, f.s)
+		edit = appendLengthPrefixed(edit, f.l)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "MANIFEST-000002"), appendLogRecord(nil, edit), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "CURRENT"), []byte("MANIFEST-000002\n"), 0o644); err != nil {
+		t.Fatalf("write CURRENT: %v", err)
+	}
+
+	// The log holds a write newer than every table plus a batch that spans several log blocks.
+	batch := binary.LittleEndian.AppendUint64(nil, 6)
+	batch = binary.LittleEndian.AppendUint32(batch, 3)
+	batch = append(batch, byte(kindValue))
+	batch = appendLengthPrefixed(batch, []byte("date"))
+	batch = appendLengthPrefixed(batch, []byte("date"))
+	batch = append(batch, byte(kindDelete))
+	batch = appendLengthPrefixed(batch, []byte("banana"))
+	batch = append(batch, byte(kindValue))
+	batch = appendLengthPrefixed(batch, []byte("big"))
+	batch = appendLengthPrefixed(batch, bytes.Repeat([]byte{'x'}, 70000))
+	if err := os.WriteFile(filepath.Join(dir, "000007.log"), appendLogRecord(nil, batch), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	return dir
+}
+
+func TestOpenMergesTablesAndLog(t *testing.T) {
+	db, err := Open(writeTestDB(t))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+
+	var got []string
+	err = db.ForEach(func(k, v []byte) error {
+		if string(k) == "big" {
+			got = append(got, fmt.Sprintf("big=%d", len(v)))
+			return nil
+		}
+		got = append(got, string(k)+"="+string(v))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("iterate: %v", err)
+	}
+	want := []string{"apple=new-apple", "big=70000", "date=date"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if v, err := db.Get([]byte("apple")); err != nil || string(v) != "new-apple" {
+		t.Fatalf("get apple: %q %v", v, err)
+	}
+	for _, k := range []string{"banana", "cherry", "missing"} {
+		if _, err := db.Get([]byte(k)); err != ErrNotFound {
+			t.Fatalf("get %s: expected ErrNotFound, got %v", k, err)
+		}
+	}
+}
+
+func TestReadLogRecordsStopsAtTornTail(t *testing.T) {
+	data := appendLogRecord(nil, []byte("first"))
+	data = appendLogRecord(data, []byte("second"))
+	data = data[:len(data)-2]
+	var recs []string
+	if err := readLogRecords(data, func(rec []byte) error {
+		recs = append(recs, string(rec))
+		return nil
+	}); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(recs) != 1 || recs[0] != "first" {
+		t.Fatalf("unexpected records: %v", recs)
+	}
+}
+
+func TestDecodeSnappy(t *testing.T) {
+	// "abcabcabcabc": a 3-byte literal followed by a 9-byte copy at offset 3.
+	src := []byte{12, 0x08, 'a', 'b', 'c', 0x15, 0x03}
+	out, err := decodeSnappy(src)
+	if err != nil || string(out) != "abcabcabcabc" {
+		t.Fatalf("decode: %q %v", out, err)
+	}
+}
+
+func TestRewriteDropsKeysAndReplacesFiles(t *testing.T) {
+	dir := writeTestDB(t)
+	st, err := Rewrite(dir, func(k, v []byte) bool { return string(k) != "date" })
+	if err != nil {
+		t.Fatalf("rewrite: %v", err)
+	}
+	if st.Kept != 2 || st.Removed != 1 {
+		t.Fatalf("unexpected stats: %+v", st)
+	}
+	for _, name := range []string{"000005.ldb", "000006.ldb", "000007.log", "MANIFEST-000002"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Fatalf("%s should have been removed: %v", name, err)
+		}
+	}
+
+	db, err := Open(dir)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer db.Close()
+	if v, err := db.Get([]byte("apple")); err != nil || string(v) != "new-apple" {
+		t.Fatalf("get apple: %q %v", v, err)
+	}
+	if v, err := db.Get([]byte("big")); err != nil || len(v) != 70000 {
+		t.Fatalf("get big: %d %v", len(v), err)
+	}
+	if _, err := db.Get([]byte("date")); err != ErrNotFound {
+		t.Fatalf("date should be gone, got %v", err)
+	}
+}
+
+func TestRewriteRoundTripsManyKeys(t *testing.T) {
+	dir := writeTestDB(t)
+	// Grow the database past several blocks and restart intervals before compacting it.
+	var batch []byte
+	batch = binary.LittleEndian.AppendUint64(batch, 100)
+	batch = binary.LittleEndian.AppendUint32(batch, 300)
+	for i := 0; i < 300; i++ {
+		batch = append(batch, byte(kindValue))
+		batch = appendLengthPrefixed(batch, []byte(fmt.Sprintf("key-%04d", i)))
+		batch = appendLengthPrefixed(batch, bytes.Repeat([]byte{byte(i)}, 1000))
+	}
+	if err := os.WriteFile(filepath.Join(dir, "000008.log"), appendLogRecord(nil, batch), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	if _, err := Rewrite(dir, func(k, v []byte) bool { return true }); err != nil {
+		t.Fatalf("rewrite: %v", err)
+	}
+	db, err := Open(dir)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer db.Close()
+	n := 0
+	if err := db.ForEach(func(k, v []byte) error {
+		n++
+		return nil
+	}); err != nil {
+		t.Fatalf("iterate: %v", err)
+	}
+	if n != 303 {
+		t.Fatalf("expected 303 keys, got %d", n)
+	}
+	if v, err := db.Get([]byte("key-0150")); err != nil || len(v) != 1000 || v[0] != 150 {
+		t.Fatalf("get key-0150: %v", err)
+	}
+}
+
+func TestWriteAppendsBatchToLog(t *testing.T) {
+	dir := writeTestDB(t)
+	var b Batch
+	b.Put([]byte("apple"), []byte("edited"))
+	b.Delete([]byte("date"))
+	b.Put([]byte("egg"), []byte("egg"))
+	if err := Write(dir, &b); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	db, err := Open(dir)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer db.Close()
+	for k, want := range map[string]string{"apple": "edited", "egg": "egg"} {
+		if v, err := db.Get([]byte(k)); err != nil || string(v) != want {
+			t.Fatalf("get %s: %q %v", k, v, err)
+		}
+	}
+	if _, err := db.Get([]byte("date")); err != ErrNotFound {
+		t.Fatalf("date should be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package leveldb
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+)
+
+// Log files (the write-ahead log and the MANIFEST) are split into 32 KiB blocks. A record that does not
+// fit into the rest of a block is fragmented into first/middle/last pieces.
+const (
+	logBlockSize = 32 * 1024
+	logHeaderLen = 7
+
+	logRecordFull   = 1
+	logRecordFirst  = 2
+	logRecordMiddle = 3
+	logRecordLast   = 4
+)
+
+// readLogRecords calls fn for every complete record of a log file. A torn or corrupt tail, which is what
+// a crash while writing leaves behind, ends the file instead of failing it, like LevelDB recovery does.
+func readLogRecords(data []byte, fn func(rec []byte) error) error {
+	var pending []byte
+	inFragment := false
+	for blockStart := 0; blockStart < len(data); blockStart += logBlockSize {
+		end := blockStart + logBlockSize
+		if end > len(data) {
+			end = len(data)
+		}
+		b := data[blockStart:end]
+		for len(b) >= logHeaderLen {
+			sum := binary.LittleEndian.Uint32(b[0:4])
+			length := int(binary.LittleEndian.Uint16(b[4:6]))
+			typ := b[6]
+			if typ == 0 && length == 0 {
+				// Zero padding written by preallocating writers.
+				break
+			}
+			if logHeaderLen+length > len(b) {
+				return nil
+			}
+			payload := b[logHeaderLen : logHeaderLen+length]
+			if unmaskCRC(sum) != crc32.Update(crc32.Checksum([]byte{typ}, crcTable), crcTable, payload) {
+				return nil
+			}
+			b = b[logHeaderLen+length:]
+			switch typ {
+			case logRecordFull:
+				if err := fn(payload); err != nil {
+					return err
+				}
+				inFragment = false
+			case logRecordFirst:
+				pending = append(pending[:0], payload...)
+				inFragment = true
+			case logRecordMiddle:
+				if inFragment {
+					pending = append(pending, payload...)
+				}
+			case logRecordLast:
+				if inFragment {
+					pending = append(pending, payload...)
+					if err := fn(pending); err != nil {
+						return err
+					}
+				}
+				inFragment = false
+			default:
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// appendLogRecord appends rec to a log file held in dst, fragmenting it across blocks as needed.
+func appendLogRecord(dst []byte, rec []byte) []byte {
+	return appendLogRecordAt(dst, 0, rec)
+}
+
+// appendLogRecordAt is appendLogRecord for bytes that will be written at offset base of an existing log.
+func appendLogRecordAt(dst []byte, base int64, rec []byte) []byte {
+	for first := true; ; first = false {
+		left := logBlockSize - int((base+int64(len(dst)))%logBlockSize)
+		if left < logHeaderLen {
+			dst = append(dst, make([]byte, left)...)
+			left = logBlockSize
+		}
+		n := len(rec)
+		if n > left-logHeaderLen {
+			n = left - logHeaderLen
+		}
+		last := n == len(rec)
+		typ := byte(logRecordMiddle)
+		switch {
+		case first && last:
+			typ = logRecordFull
+		case first:
+			typ = logRecordFirst
+		case last:
+			typ = logRecordLast
+		}
+		sum := crc32.Update(crc32.Checksum([]byte{typ}, crcTable), crcTable, rec[:n])
+		dst = binary.LittleEndian.AppendUint32(dst, maskCRC(sum))
+		dst = binary.LittleEndian.AppendUint16(dst, uint16(n))
+		dst = append(dst, typ)
+		dst = append(dst, rec[:n]...)
+		rec = rec[n:]
+		if last {
+			return dst
+		}
+	}
+}
+
+// batchEntry is one operation of a WriteBatch stored in the write-ahead log.
+type batchEntry struct {
+	seq   uint64
+	kind  keyKind
+	key   []byte
+	value []byte
+}
+
+func encodeBatch(seq uint64, entries []batchEntry) []byte {
+	out := binary.LittleEndian.AppendUint64(nil, seq)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(entries)))
+	for _, e := range entries {
+		out = append(out, byte(e.kind))
+		out = appendLengthPrefixed(out, e.key)
+		if e.kind == kindValue {
+			out = appendLengthPrefixed(out, e.value)
+		}
+	}
+	return out
+}
+
+func decodeBatch(rec []byte) ([]batchEntry, error) {
+	if len(rec) < 12 {
+		return nil, ErrCorrupt
+	}
+	seq := binary.LittleEndian.Uint64(rec[0:8])
+	count := int(binary.LittleEndian.Uint32(rec[8:12]))
+	p := rec[12:]
+	out := make([]batchEntry, 0, count)
+	for i := 0; i < count; i++ {
+		if len(p) == 0 {
+			return nil, ErrCorrupt
+		}
+		kind := keyKind(p[0])
+		p = p[1:]
+		key, rest, ok := getLengthPrefixed(p)
+		if !ok {
+			return nil, ErrCorrupt
+		}
+		p = rest
+		e := batchEntry{seq: seq + uint64(i), kind: kind, key: append([]byte(nil), key...)}
+		switch kind {
+		case kindValue:
+			val, rest, ok := getLengthPrefixed(p)
+			if !ok {
+				return nil, ErrCorrupt
+			}
+			p = rest
+			e.value = append([]byte(nil), val...)
+		case kindDelete:
+		default:
+			return nil, ErrCorrupt
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
@@ -0,0 +1,173 @@
+package leveldb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// numLevels is the level count of Bedrock's LevelDB fork.
+const numLevels = 7
+
+// VersionEdit tags.
+const (
+	tagComparator     = 1
+	tagLogNumber      = 2
+	tagNextFileNumber = 3
+	tagLastSequence   = 4
+	tagCompactPointer = 5
+	tagDeletedFile    = 6
+	tagNewFile        = 7
+	tagPrevLogNumber  = 9
+)
+
+type fileMeta struct {
+	level    int
+	num      uint64
+	size     uint64
+	smallest []byte
+	largest  []byte
+}
+
+// manifestState is the version of the database described by the MANIFEST named in CURRENT.
+type manifestState struct {
+	comparator    string
+	logNumber     uint64
+	prevLogNumber uint64
+	nextFile      uint64
+	lastSeq       uint64
+	files         map[uint64]fileMeta
+}
+
+func loadManifest(dir string) (*manifestState, error) {
+	cur, err := os.ReadFile(filepath.Join(dir, "CURRENT"))
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(string(cur))
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, ErrCorrupt
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	st := &manifestState{files: map[uint64]fileMeta{}}
+	err = readLogRecords(data, func(rec []byte) error {
+		return st.apply(rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if st.comparator != "" && st.comparator != "leveldb.BytewiseComparator" {
+		return nil, fmt.Errorf("leveldb: unsupported comparator %q", st.comparator)
+	}
+	return st, nil
+}
+
+func (st *manifestState) apply(rec []byte) error {
+	p := rec
+	uvarint := func() (uint64, bool) {
+		v, n := binary.Uvarint(p)
+		if n <= 0 {
+			return 0, false
+		}
+		p = p[n:]
+		return v, true
+	}
+	bytesField := func() ([]byte, bool) {
+		b, rest, ok := getLengthPrefixed(p)
+		if !ok {
+			return nil, false
+		}
+		p = rest
+		return append([]byte(nil), b...), true
+	}
+	for len(p) > 0 {
+		tag, ok := uvarint()
+		if !ok {
+			return ErrCorrupt
+		}
+		switch tag {
+		case tagComparator:
+			b, ok := bytesField()
+			if !ok {
+				return ErrCorrupt
+			}
+			st.comparator = string(b)
+		case tagLogNumber:
+			if st.logNumber, ok = uvarint(); !ok {
+				return ErrCorrupt
+			}
+		case tagPrevLogNumber:
+			if st.prevLogNumber, ok = uvarint(); !ok {
+				return ErrCorrupt
+			}
+		case tagNextFileNumber:
+			if st.nextFile, ok = uvarint(); !ok {
+				return ErrCorrupt
+			}
+		case tagLastSequence:
+			if st.lastSeq, ok = uvarint(); !ok {
+				return ErrCorrupt
+			}
+		case tagCompactPointer:
+			if _, ok = uvarint(); !ok {
+				return ErrCorrupt
+			}
+			if _, ok = bytesField(); !ok {
+				return ErrCorrupt
+			}
+		case tagDeletedFile:
+			if _, ok = uvarint(); !ok {
+				return ErrCorrupt
+			}
+			num, ok := uvarint()
+			if !ok {
+				return ErrCorrupt
+			}
+			delete(st.files, num)
+		case tagNewFile:
+			var f fileMeta
+			level, ok := uvarint()
+			if !ok || level >= numLevels {
+				return ErrCorrupt
+			}
+			f.level = int(level)
+			if f.num, ok = uvarint(); !ok {
+				return ErrCorrupt
+			}
+			if f.size, ok = uvarint(); !ok {
+				return ErrCorrupt
+			}
+			if f.smallest, ok = bytesField(); !ok {
+				return ErrCorrupt
+			}
+			if f.largest, ok = bytesField(); !ok {
+				return ErrCorrupt
+			}
+			st.files[f.num] = f
+		default:
+			return ErrCorrupt
+		}
+	}
+	return nil
+}
+
+// levels returns the live table files grouped by level. Level 0 files may overlap and are ordered newest
+// first; deeper levels are ordered by key range.
+func (st *manifestState) levels() [numLevels][]fileMeta {
+	var out [numLevels][]fileMeta
+	for _, f := range st.files {
+		out[f.level] = append(out[f.level], f)
+	}
+	sort.Slice(out[0], func(i, j int) bool { return out[0][i].num > out[0][j].num })
+	for l := 1; l < numLevels; l++ {
+		files := out[l]
+		sort.Slice(files, func(i, j int) bool { return compareInternal(files[i].smallest, files[j].smallest) < 0 })
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package leveldb
+
+import "encoding/binary"
+
+// decodeSnappy decodes a block in the Snappy block format. The game does not write Snappy blocks, but
+// worlds converted by third-party tools built on stock LevelDB may contain them.
+func decodeSnappy(src []byte) ([]byte, error) {
+	n, m := binary.Uvarint(src)
+	if m <= 0 || n > 1<<30 {
+		return nil, ErrCorrupt
+	}
+	dst := make([]byte, 0, n)
+	s := src[m:]
+	for len(s) > 0 {
+		tag := s[0]
+		var length, offset int
+		switch tag & 3 {
+		case 0:
+			length = int(tag>>2) + 1
+			s = s[1:]
+			if length > 60 {
+				extra := length - 60
+				if len(s) < extra {
+					return nil, ErrCorrupt
+				}
+				length = 0
+				for i := extra - 1; i >= 0; i-- {
+					length = length<<8 | int(s[i])
+				}
+				length++
+				s = s[extra:]
+			}
+			if len(s) < length {
+				return nil, ErrCorrupt
+			}
+			dst = append(dst, s[:length]...)
+			s = s[length:]
+			continue
+		case 1:
+			if len(s) < 2 {
+				return nil, ErrCorrupt
+			}
+			length = 4 + int(tag>>2)&7
+			offset = int(tag&0xe0)<<3 | int(s[1])
+			s = s[2:]
+		case 2:
+			if len(s) < 3 {
+				return nil, ErrCorrupt
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint16(s[1:]))
+			s = s[3:]
+		case 3:
+			if len(s) < 5 {
+				return nil, ErrCorrupt
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint32(s[1:]))
+			s = s[5:]
+		}
+		if offset <= 0 || offset > len(dst) {
+			return nil, ErrCorrupt
+		}
+		// Copies may overlap their own output, so they are done byte by byte.
+		start := len(dst) - offset
+		for i := 0; i < length; i++ {
+			dst = append(dst, dst[start+i])
+		}
+	}
+	if uint64(len(dst)) != n {
+		return nil, ErrCorrupt
+	}
+	return dst, nil
+}
@@ -0,0 +1,247 @@
+package leveldb
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+	"os"
+)
+
+// block is a decoded table block: prefix-compressed entries followed by the restart array.
+type block struct {
+	data       []byte
+	restartOff int
+}
+
+func newBlock(data []byte) (*block, error) {
+	if len(data) < 4 {
+		return nil, ErrCorrupt
+	}
+	num := int(binary.LittleEndian.Uint32(data[len(data)-4:]))
+	off := len(data) - 4 - 4*num
+	if num < 0 || off < 0 {
+		return nil, ErrCorrupt
+	}
+	return &block{data: data, restartOff: off}, nil
+}
+
+type blockIter struct {
+	b   *block
+	off int
+	k   []byte
+	v   []byte
+	err error
+}
+
+func (b *block) iter() *blockIter {
+	return &blockIter{b: b}
+}
+
+func (it *blockIter) next() bool {
+	if it.err != nil || it.off >= it.b.restartOff {
+		return false
+	}
+	p := it.b.data[it.off:it.b.restartOff]
+	shared, n1 := binary.Uvarint(p)
+	if n1 <= 0 {
+		it.err = ErrCorrupt
+		return false
+	}
+	unshared, n2 := binary.Uvarint(p[n1:])
+	if n2 <= 0 {
+		it.err = ErrCorrupt
+		return false
+	}
+	vlen, n3 := binary.Uvarint(p[n1+n2:])
+	if n3 <= 0 {
+		it.err = ErrCorrupt
+		return false
+	}
+	h := n1 + n2 + n3
+	if shared > uint64(len(it.k)) || uint64(len(p)-h) < unshared+vlen {
+		it.err = ErrCorrupt
+		return false
+	}
+	// The key buffer is rebuilt rather than appended to, because callers may still hold the previous key.
+	key := make([]byte, 0, int(shared+unshared))
+	key = append(key, it.k[:shared]...)
+	key = append(key, p[h:h+int(unshared)]...)
+	it.k = key
+	it.v = p[h+int(unshared) : h+int(unshared)+int(vlen)]
+	it.off += h + int(unshared) + int(vlen)
+	return true
+}
+
+// table reads one .ldb/.sst file.
+type table struct {
+	f     *os.File
+	index *block
+}
+
+func openTable(path string) (*table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if fi.Size() < footerLen {
+		f.Close()
+		return nil, ErrCorrupt
+	}
+	footer := make([]byte, footerLen)
+	if _, err := f.ReadAt(footer, fi.Size()-footerLen); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if binary.LittleEndian.Uint64(footer[footerLen-8:]) != tableMagic {
+		f.Close()
+		return nil, ErrCorrupt
+	}
+	_, n := decodeBlockHandle(footer)
+	if n == 0 {
+		f.Close()
+		return nil, ErrCorrupt
+	}
+	indexHandle, m := decodeBlockHandle(footer[n:])
+	if m == 0 {
+		f.Close()
+		return nil, ErrCorrupt
+	}
+	t := &table{f: f}
+	if t.index, err = t.readBlock(indexHandle); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *table) close() error {
+	return t.f.Close()
+}
+
+func (t *table) readBlock(h blockHandle) (*block, error) {
+	buf := make([]byte, h.size+blockTrailerLen)
+	if _, err := t.f.ReadAt(buf, int64(h.offset)); err != nil {
+		return nil, err
+	}
+	data := buf[:h.size]
+	switch buf[h.size] {
+	case compressionNone:
+	case compressionSnappy:
+		out, err := decodeSnappy(data)
+		if err != nil {
+			return nil, err
+		}
+		data = out
+	case compressionZlib:
+		r, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrCorrupt
+		}
+		out, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, ErrCorrupt
+		}
+		data = out
+	case compressionZlibRaw:
+		r := flate.NewReader(bytes.NewReader(data))
+		out, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, ErrCorrupt
+		}
+		data = out
+	default:
+		return nil, ErrCorrupt
+	}
+	return newBlock(data)
+}
+
+// get returns the newest entry for ukey stored in the table.
+func (t *table) get(ukey []byte) ([]byte, keyKind, bool, error) {
+	lookup := makeInternalKey(ukey, maxSequence, kindValue)
+	idx := t.index.iter()
+	for idx.next() {
+		if compareInternal(idx.k, lookup) < 0 {
+			continue
+		}
+		h, n := decodeBlockHandle(idx.v)
+		if n == 0 {
+			return nil, 0, false, ErrCorrupt
+		}
+		b, err := t.readBlock(h)
+		if err != nil {
+			return nil, 0, false, err
+		}
+		it := b.iter()
+		for it.next() {
+			if compareInternal(it.k, lookup) < 0 {
+				continue
+			}
+			uk, _, kind, ok := parseInternalKey(it.k)
+			if !ok {
+				return nil, 0, false, ErrCorrupt
+			}
+			if !bytes.Equal(uk, ukey) {
+				return nil, 0, false, nil
+			}
+			return it.v, kind, true, nil
+		}
+		if it.err != nil {
+			return nil, 0, false, it.err
+		}
+	}
+	return nil, 0, false, idx.err
+}
+
+type tableIter struct {
+	t    *table
+	idx  *blockIter
+	data *blockIter
+	err  error
+}
+
+func (t *table) iter() *tableIter {
+	return &tableIter{t: t, idx: t.index.iter()}
+}
+
+func (ti *tableIter) next() bool {
+	for ti.err == nil {
+		if ti.data != nil {
+			if ti.data.next() {
+				return true
+			}
+			if ti.data.err != nil {
+				ti.err = ti.data.err
+				return false
+			}
+		}
+		if !ti.idx.next() {
+			ti.err = ti.idx.err
+			return false
+		}
+		h, n := decodeBlockHandle(ti.idx.v)
+		if n == 0 {
+			ti.err = ErrCorrupt
+			return false
+		}
+		b, err := ti.t.readBlock(h)
+		if err != nil {
+			ti.err = err
+			return false
+		}
+		ti.data = b.iter()
+	}
+	return false
+}
+
+func (ti *tableIter) key() []byte   { return ti.data.k }
+func (ti *tableIter) value() []byte { return ti.data.v }
+func (ti *tableIter) error() error  { return ti.err }
@@ -0,0 +1,386 @@
+package leveldb
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// writeBlockSize is the uncompressed size at which a data block is cut. Bedrock writes large blocks
+	// since its values are mostly chunk data.
+	writeBlockSize = 64 * 1024
+	// writeTableSize is the size at which the rewrite starts a new table file.
+	writeTableSize  = 8 * 1024 * 1024
+	restartInterval = 16
+)
+
+type blockBuilder struct {
+	buf      []byte
+	restarts []uint32
+	counter  int
+	lastKey  []byte
+}
+
+func (b *blockBuilder) add(key []byte, value []byte) {
+	shared := 0
+	if b.counter < restartInterval && len(b.buf) > 0 {
+		n := min(len(b.lastKey), len(key))
+		for shared < n && b.lastKey[shared] == key[shared] {
+			shared++
+		}
+	} else {
+		b.restarts = append(b.restarts, uint32(len(b.buf)))
+		b.counter = 0
+	}
+	b.buf = binary.AppendUvarint(b.buf, uint64(shared))
+	b.buf = binary.AppendUvarint(b.buf, uint64(len(key)-shared))
+	b.buf = binary.AppendUvarint(b.buf, uint64(len(value)))
+	b.buf = append(b.buf, key[shared:]...)
+	b.buf = append(b.buf, value...)
+	b.lastKey = append(b.lastKey[:0], key...)
+	b.counter++
+}
+
+func (b *blockBuilder) finish() []byte {
+	out := b.buf
+	for _, r := range b.restarts {
+		out = binary.LittleEndian.AppendUint32(out, r)
+	}
+	return binary.LittleEndian.AppendUint32(out, uint32(len(b.restarts)))
+}
+
+func (b *blockBuilder) reset() {
+	b.buf, b.restarts, b.counter, b.lastKey = b.buf[:0], b.restarts[:0], 0, b.lastKey[:0]
+}
+
+// tableWriter writes one table file with raw deflate data blocks, the compression current Bedrock
+// versions use.
+type tableWriter struct {
+	f        *os.File
+	w        *bufio.Writer
+	offset   uint64
+	data     blockBuilder
+	index    blockBuilder
+	smallest []byte
+	largest  []byte
+	z        bytes.Buffer
+	err      error
+}
+
+func newTableWriter(path string) (*tableWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &tableWriter{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+func (tw *tableWriter) writeBlock(raw []byte, compression byte) blockHandle {
+	data := raw
+	if compression == compressionZlibRaw {
+		tw.z.Reset()
+		fw, _ := flate.NewWriter(&tw.z, flate.DefaultCompression)
+		fw.Write(raw)
+		fw.Close()
+		data = tw.z.Bytes()
+	}
+	h := blockHandle{offset: tw.offset, size: uint64(len(data))}
+	sum := crc32.Update(crc32.Checksum(data, crcTable), crcTable, []byte{compression})
+	var trailer [blockTrailerLen]byte
+	trailer[0] = compression
+	binary.LittleEndian.PutUint32(trailer[1:], maskCRC(sum))
+	if _, err := tw.w.Write(data); err != nil && tw.err == nil {
+		tw.err = err
+	}
+	if _, err := tw.w.Write(trailer[:]); err != nil && tw.err == nil {
+		tw.err = err
+	}
+	tw.offset += uint64(len(data)) + blockTrailerLen
+	return h
+}
+
+func (tw *tableWriter) flushData() {
+	if len(tw.data.buf) == 0 {
+		return
+	}
+	h := tw.writeBlock(tw.data.finish(), compressionZlibRaw)
+	tw.index.add(tw.largest, appendBlockHandle(nil, h))
+	tw.data.reset()
+}
+
+func (tw *tableWriter) add(ikey []byte, value []byte) {
+	if tw.smallest == nil {
+		tw.smallest = append([]byte(nil), ikey...)
+	}
+	tw.largest = append(tw.largest[:0], ikey...)
+	tw.data.add(ikey, value)
+	if len(tw.data.buf) >= writeBlockSize {
+		tw.flushData()
+	}
+}
+
+// size estimates the file size so far, counting the pending block uncompressed.
+func (tw *tableWriter) size() uint64 {
+	return tw.offset + uint64(len(tw.data.buf))
+}
+
+// finish writes the index and footer and closes the file, returning its final size.
+func (tw *tableWriter) finish() (uint64, error) {
+	tw.flushData()
+	var empty blockBuilder
+	meta := tw.writeBlock(empty.finish(), compressionNone)
+	idx := tw.writeBlock(tw.index.finish(), compressionNone)
+	footer := appendBlockHandle(nil, meta)
+	footer = appendBlockHandle(footer, idx)
+	footer = append(footer, make([]byte, footerLen-8-len(footer))...)
+	footer = binary.LittleEndian.AppendUint64(footer, tableMagic)
+	if _, err := tw.w.Write(footer); err != nil && tw.err == nil {
+		tw.err = err
+	}
+	tw.offset += footerLen
+	if err := tw.w.Flush(); err != nil && tw.err == nil {
+		tw.err = err
+	}
+	if err := tw.f.Sync(); err != nil && tw.err == nil {
+		tw.err = err
+	}
+	if err := tw.f.Close(); err != nil && tw.err == nil {
+		tw.err = err
+	}
+	return tw.offset, tw.err
+}
+
+// RewriteStats describes the outcome of Rewrite.
+type RewriteStats struct {
+	Kept         int
+	Removed      int
+	RemovedBytes int64
+	SizeBefore   int64
+	SizeAfter    int64
+}
+
+// Rewrite compacts the database in dir into fresh table files, dropping every live key for which keep
+// returns false. Deleted and overwritten versions are discarded along the way. The new tables are
+// committed by writing a new MANIFEST and pointing CURRENT at it; only then are the old tables, logs and
+// manifests removed, so an interrupted rewrite leaves the previous state intact.
+func Rewrite(dir string, keep func(key []byte, value []byte) bool) (RewriteStats, error) {
+	var st RewriteStats
+	db, err := Open(dir)
+	if err != nil {
+		return st, err
+	}
+	st.SizeBefore = dbFilesSize(dir)
+
+	next := db.state.nextFile
+	// Logs written after the manifest was last saved may use numbers it does not know about yet.
+	for _, num := range dbFileNums(dir) {
+		if num >= next {
+			next = num + 1
+		}
+	}
+	lastSeq := db.state.lastSeq
+	for _, e := range db.mem {
+		if _, seq, _, _ := parseInternalKey(e.ikey); seq > lastSeq {
+			lastSeq = seq
+		}
+	}
+
+	var files []fileMeta
+	var created []string
+	cleanup := func() {
+		for _, p := range created {
+			_ = os.Remove(p)
+		}
+	}
+	var tw *tableWriter
+	closeTable := func(num uint64) error {
+		size, err := tw.finish()
+		if err != nil {
+			return err
+		}
+		files = append(files, fileMeta{level: numLevels - 1, num: num, size: size, smallest: tw.smallest, largest: append([]byte(nil), tw.largest...)})
+		tw = nil
+		return nil
+	}
+	var tableNum uint64
+	it := db.NewIterator()
+	for it.Next() {
+		k, v := it.Key(), it.Value()
+		if !keep(k, v) {
+			st.Removed++
+			st.RemovedBytes += int64(len(k) + len(v))
+			continue
+		}
+		st.Kept++
+		if tw == nil {
+			tableNum = next
+			next++
+			path := filepath.Join(dir, fmt.Sprintf("%06d.ldb", tableNum))
+			if tw, err = newTableWriter(path); err != nil {
+				db.Close()
+				cleanup()
+				return st, err
+			}
+			created = append(created, path)
+		}
+		// Sequence zero is what LevelDB itself assigns to keys compacted into the last level.
+		tw.add(makeInternalKey(k, 0, kindValue), v)
+		if tw.size() >= writeTableSize {
+			if err := closeTable(tableNum); err != nil {
+				db.Close()
+				cleanup()
+				return st, err
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		if tw != nil {
+			tw.finish()
+		}
+		db.Close()
+		cleanup()
+		return st, err
+	}
+	if tw != nil {
+		if err := closeTable(tableNum); err != nil {
+			db.Close()
+			cleanup()
+			return st, err
+		}
+	}
+	if err := db.Close(); err != nil {
+		cleanup()
+		return st, err
+	}
+
+	logNum := next
+	manifestNum := next + 1
+	next += 2
+	logPath := filepath.Join(dir, fmt.Sprintf("%06d.log", logNum))
+	if err := os.WriteFile(logPath, nil, 0o644); err != nil {
+		cleanup()
+		return st, err
+	}
+	created = append(created, logPath)
+
+	var edit []byte
+	edit = binary.AppendUvarint(edit, tagComparator)
+	edit = appendLengthPrefixed(edit, []byte("leveldb.BytewiseComparator"))
+	edit = binary.AppendUvarint(edit, tagLogNumber)
+	edit = binary.AppendUvarint(edit, logNum)
+	edit = binary.AppendUvarint(edit, tagPrevLogNumber)
+	edit = binary.AppendUvarint(edit, 0)
+	edit = binary.AppendUvarint(edit, tagNextFileNumber)
+	edit = binary.AppendUvarint(edit, next)
+	edit = binary.AppendUvarint(edit, tagLastSequence)
+	edit = binary.AppendUvarint(edit, lastSeq)
+	for _, f := range files {
+		edit = binary.AppendUvarint(edit, tagNewFile)
+		edit = binary.AppendUvarint(edit, uint64(f.level))
+		edit = binary.AppendUvarint(edit, f.num)
+		edit = binary.AppendUvarint(edit, f.size)
+		edit = appendLengthPrefixed(edit, f.smallest)
+		edit = appendLengthPrefixed(edit, f.largest)
+	}
+	manifestName := fmt.Sprintf("MANIFEST-%06d", manifestNum)
+	manifestPath := filepath.Join(dir, manifestName)
+	if err := writeFileSync(manifestPath, appendLogRecord(nil, edit)); err != nil {
+		cleanup()
+		return st, err
+	}
+	created = append(created, manifestPath)
+
+	tmp := filepath.Join(dir, fmt.Sprintf("%06d.dbtmp", manifestNum))
+	if err := writeFileSync(tmp, []byte(manifestName+"\n")); err != nil {
+		_ = os.Remove(tmp)
+		cleanup()
+		return st, err
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, "CURRENT")); err != nil {
+		_ = os.Remove(tmp)
+		cleanup()
+		return st, err
+	}
+
+	live := map[string]struct{}{manifestName: {}, filepath.Base(logPath): {}}
+	for _, f := range files {
+		live[fmt.Sprintf("%06d.ldb", f.num)] = struct{}{}
+	}
+	ents, _ := os.ReadDir(dir)
+	for _, e := range ents {
+		name := e.Name()
+		if _, ok := live[name]; ok || e.IsDir() {
+			continue
+		}
+		if isDBDataFile(name) {
+			_ = os.Remove(filepath.Join(dir, name))
+		}
+	}
+	st.SizeAfter = dbFilesSize(dir)
+	return st, nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// isDBDataFile reports whether name is a table, log or manifest file that a rewrite makes obsolete.
+func isDBDataFile(name string) bool {
+	if strings.HasPrefix(name, "MANIFEST-") {
+		return true
+	}
+	for _, ext := range []string{".ldb", ".sst", ".log"} {
+		if base, ok := strings.CutSuffix(name, ext); ok {
+			_, err := strconv.ParseUint(base, 10, 64)
+			return err == nil
+		}
+	}
+	return false
+}
+
+func dbFileNums(dir string) []uint64 {
+	ents, _ := os.ReadDir(dir)
+	var out []uint64
+	for _, e := range ents {
+		name := e.Name()
+		base := strings.TrimPrefix(name, "MANIFEST-")
+		if i := strings.IndexByte(base, '.'); i >= 0 {
+			base = base[:i]
+		}
+		if num, err := strconv.ParseUint(base, 10, 64); err == nil {
+			out = append(out, num)
+		}
+	}
+	return out
+}
+
+func dbFilesSize(dir string) int64 {
+	ents, _ := os.ReadDir(dir)
+	var n int64
+	for _, e := range ents {
+		if fi, err := e.Info(); err == nil && !fi.IsDir() {
+			n += fi.Size()
+		}
+	}
+	return n
+}
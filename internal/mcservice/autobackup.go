@@ -0,0 +1,187 @@
+package mcservice
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/liteldev/LeviLauncher/internal/apppath"
+	"github.com/liteldev/LeviLauncher/internal/versions"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+const (
+	AutoBackupTriggerExit     = "exit"
+	AutoBackupTriggerInterval = "interval"
+
+	// minAutoBackupInterval keeps a misconfigured policy from snapshotting continuously.
+	minAutoBackupInterval = 5
+)
+
+// AutoBackupWorld reports the snapshot taken of one world by an automatic backup run.
+type AutoBackupWorld struct {
+	WorldDir   string `json:"worldDir"`
+	WorldName  string `json:"worldName"`
+	SnapshotID string `json:"snapshotId"`
+	Unchanged  bool   `json:"unchanged"`
+	ErrorCode  string `json:"errorCode"`
+}
+
+// AutoBackupEvent is emitted after every automatic backup run that found played worlds.
+type AutoBackupEvent struct {
+	VersionName string            `json:"versionName"`
+	Trigger     string            `json:"trigger"`
+	Worlds      []AutoBackupWorld `json:"worlds"`
+	Ts          int64             `json:"ts"`
+}
+
+// GetVersionAutoBackup returns the automatic backup policy of a version.
+func GetVersionAutoBackup(name string) versions.AutoBackupPolicy {
+	m, err := readVersionMetaByName(name)
+	if err != nil {
+		return versions.AutoBackupPolicy{}
+	}
+	return m.AutoBackup
+}
+
+// SetVersionAutoBackup stores the automatic backup policy of a version. It applies from the next launch.
+func SetVersionAutoBackup(name string, policy versions.AutoBackupPolicy) string {
+	if policy.IntervalMinutes < 0 {
+		return "ERR_INVALID_INTERVAL"
+	}
+	if policy.IntervalMinutes > 0 && policy.IntervalMinutes < minAutoBackupInterval {
+		policy.IntervalMinutes = minAutoBackupInterval
+	}
+	vdir, err := apppath.VersionsDir()
+	if err != nil || strings.TrimSpace(vdir) == "" {
+		return "ERR_ACCESS_VERSIONS_DIR"
+	}
+	dir := filepath.Join(vdir, strings.TrimSpace(name))
+	m, err := versions.ReadMeta(dir)
+	if err != nil {
+		return "ERR_READ_VERSION_META"
+	}
+	m.AutoBackup = policy
+	if err := versions.WriteMeta(dir, m); err != nil {
+		return "ERR_WRITE_VERSION_META"
+	}
+	return ""
+}
+
+func readVersionMetaByName(name string) (versions.VersionMeta, error) {
+	vdir, err := apppath.VersionsDir()
+	if err != nil {
+		return versions.VersionMeta{}, err
+	}
+	return versions.ReadMeta(filepath.Join(vdir, strings.TrimSpace(name)))
+}
+
+// AutoBackupSessionStart is the launch session hook that takes interval snapshots while the game runs.
+func AutoBackupSessionStart(ctx context.Context, versionDir string, started time.Time) {
+	name := filepath.Base(versionDir)
+	m, err := versions.ReadMeta(versionDir)
+	if err != nil || !m.AutoBackup.Enabled || m.AutoBackup.IntervalMinutes <= 0 {
+		return
+	}
+	interval := time.Duration(max(m.AutoBackup.IntervalMinutes, minAutoBackupInterval)) * time.Minute
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	since := started
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			runAutoBackup(name, since, AutoBackupTriggerInterval)
+			since = now.Add(-interval / 10)
+		}
+	}
+}
+
+// AutoBackupSessionExit is the launch session hook that snapshots the worlds played during the session
+// once the game has exited.
+func AutoBackupSessionExit(versionDir string, started time.Time) {
+	m, err := versions.ReadMeta(versionDir)
+	if err != nil || !m.AutoBackup.Enabled {
+		return
+	}
+	runAutoBackup(filepath.Base(versionDir), started, AutoBackupTriggerExit)
+}
+
+func runAutoBackup(versionName string, since time.Time, trigger string) {
+	dirs := recentWorldDirs(worldRootsOf(versionName), since)
+	if len(dirs) == 0 {
+		return
+	}
+	ev := AutoBackupEvent{VersionName: versionName, Trigger: trigger, Worlds: []AutoBackupWorld{}}
+	for _, dir := range dirs {
+		res := SnapshotWorld(dir, versionName, "auto-"+trigger)
+		ev.Worlds = append(ev.Worlds, AutoBackupWorld{
+			WorldDir:   dir,
+			WorldName:  res.Snapshot.WorldName,
+			SnapshotID: res.Snapshot.ID,
+			Unchanged:  res.Unchanged,
+			ErrorCode:  res.ErrorCode,
+		})
+	}
+	ev.Ts = time.Now().Unix()
+	if app := application.Get(); app != nil && app.Event != nil {
+		app.Event.Emit(EventWorldAutoBackup, ev)
+	}
+}
+
+// worldRootsOf returns the minecraftWorlds folder of every user of a version.
+func worldRootsOf(versionName string) []string {
+	users := GetContentRoots(versionName).UsersRoot
+	if strings.TrimSpace(users) == "" {
+		return nil
+	}
+	ents, err := os.ReadDir(users)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, e := range ents {
+		if e.IsDir() {
+			out = append(out, filepath.Join(users, e.Name(), "games", "com.mojang", "minecraftWorlds"))
+		}
+	}
+	return out
+}
+
+// recentWorldDirs returns the worlds under roots with a file modified at or after since. Worlds that
+// were not touched are skipped without being read.
+func recentWorldDirs(roots []string, since time.Time) []string {
+	var out []string
+	for _, root := range roots {
+		ents, err := os.ReadDir(root)
+		if err != nil {
+			continue
+		}
+		for _, e := range ents {
+			dir := filepath.Join(root, e.Name())
+			if e.IsDir() && modifiedSince(dir, since) {
+				out = append(out, dir)
+			}
+		}
+	}
+	return out
+}
+
+func modifiedSince(dir string, since time.Time) bool {
+	found := false
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if fi, err := d.Info(); err == nil && !fi.ModTime().Before(since) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
@@ -0,0 +1,35 @@
+package mcservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecentWorldDirsSkipsUntouchedWorlds(t *testing.T) {
+	root := t.TempDir()
+	old := time.Now().Add(-2 * time.Hour)
+	for _, name := range []string{"played", "idle"} {
+		dir := filepath.Join(root, name, "db")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		p := filepath.Join(dir, "000001.ldb")
+		if err := os.WriteFile(p, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	since := time.Now().Add(-time.Hour)
+	if err := os.WriteFile(filepath.Join(root, "played", "db", "000002.log"), []byte("y"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := recentWorldDirs([]string{root, filepath.Join(root, "missing")}, since)
+	if len(got) != 1 || filepath.Base(got[0]) != "played" {
+		t.Fatalf("recentWorldDirs = %v, want only the played world", got)
+	}
+}
@@ -0,0 +1,54 @@
+package mcservice
+
+import (
+	"context"
+	"sync"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+var (
+	contentTransferMu     sync.Mutex
+	contentTransferCancel context.CancelFunc
+)
+
+// StartContentTransfer claims the batch transfer slot and returns the context the batch runs under.
+// It reports false while another batch is running.
+func StartContentTransfer() (context.Context, bool) {
+	contentTransferMu.Lock()
+	defer contentTransferMu.Unlock()
+	if contentTransferCancel != nil {
+		return nil, false
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	contentTransferCancel = cancel
+	return ctx, true
+}
+
+// FinishContentTransfer releases the batch transfer slot.
+func FinishContentTransfer() {
+	contentTransferMu.Lock()
+	defer contentTransferMu.Unlock()
+	if contentTransferCancel != nil {
+		contentTransferCancel()
+		contentTransferCancel = nil
+	}
+}
+
+// CancelContentTransfer stops the running batch transfer after its current item.
+func CancelContentTransfer() {
+	contentTransferMu.Lock()
+	defer contentTransferMu.Unlock()
+	if contentTransferCancel != nil {
+		contentTransferCancel()
+	}
+}
+
+func EmitContentTransferProgress(p types.ContentTransferProgress) {
+	app := application.Get()
+	if app == nil || app.Event == nil {
+		return
+	}
+	app.Event.Emit(EventContentTransferProgress, p)
+}
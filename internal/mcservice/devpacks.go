@@ -0,0 +1,349 @@
+package mcservice
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v3/pkg/application"
+
+	"github.com/liteldev/LeviLauncher/internal/apppath"
+	"github.com/liteldev/LeviLauncher/internal/devpack"
+	"github.com/liteldev/LeviLauncher/internal/dirlink"
+	"github.com/liteldev/LeviLauncher/internal/packlint"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+const (
+	DevPackModeLink   = "link"
+	DevPackModeMirror = "mirror"
+
+	DevPackTypeBehavior  = "behavior"
+	DevPackTypeResources = "resources"
+
+	DevPackStateSynced  = "synced"
+	DevPackStateInvalid = "invalid"
+	DevPackStateError   = "error"
+
+	devPacksFileName = "devpacks.json"
+)
+
+var (
+	devPackWatchMu  sync.Mutex
+	devPackWatchers = map[string]context.CancelFunc{}
+	devPackSyncMu   sync.Mutex
+)
+
+func devPacksFile(versionName string) (string, bool) {
+	name := strings.TrimSpace(versionName)
+	if name == "" {
+		return "", false
+	}
+	vdir, err := apppath.VersionsDir()
+	if err != nil || strings.TrimSpace(vdir) == "" {
+		return "", false
+	}
+	dir := filepath.Join(vdir, name)
+	if !utils.DirExists(dir) {
+		return "", false
+	}
+	return filepath.Join(dir, devPacksFileName), true
+}
+
+func readDevPacks(versionName string) []types.DevPack {
+	p, ok := devPacksFile(versionName)
+	if !ok {
+		return []types.DevPack{}
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return []types.DevPack{}
+	}
+	var list []types.DevPack
+	if err := json.Unmarshal(b, &list); err != nil {
+		return []types.DevPack{}
+	}
+	return list
+}
+
+func writeDevPacks(versionName string, list []types.DevPack) error {
+	p, ok := devPacksFile(versionName)
+	if !ok {
+		return os.ErrNotExist
+	}
+	out := make([]types.DevPack, len(list))
+	for i, d := range list {
+		d.Watching = false
+		out[i] = d
+	}
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, b, 0o644)
+}
+
+func findDevPack(versionName string, id string) (types.DevPack, bool) {
+	for _, d := range readDevPacks(versionName) {
+		if d.ID == strings.TrimSpace(id) {
+			return d, true
+		}
+	}
+	return types.DevPack{}, false
+}
+
+func isDevPackWatching(id string) bool {
+	devPackWatchMu.Lock()
+	defer devPackWatchMu.Unlock()
+	_, ok := devPackWatchers[id]
+	return ok
+}
+
+func ListDevPacks(versionName string) []types.DevPack {
+	list := readDevPacks(versionName)
+	for i := range list {
+		list[i].Watching = isDevPackWatching(list[i].ID)
+	}
+	return list
+}
+
+// RegisterDevPack adds an external working folder as a development pack of versionName and starts
+// watching it. packType may be empty to detect it from the manifest.
+func RegisterDevPack(versionName string, sourcePath string, packType string, mode string) string {
+	src, err := filepath.Abs(strings.TrimSpace(sourcePath))
+	if err != nil || strings.TrimSpace(sourcePath) == "" || !utils.DirExists(src) {
+		return "ERR_INVALID_PATH"
+	}
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		mode = DevPackModeLink
+	}
+	if mode != DevPackModeLink && mode != DevPackModeMirror {
+		return "ERR_INVALID_MODE"
+	}
+	packType = strings.ToLower(strings.TrimSpace(packType))
+	if packType == "" {
+		switch packlint.ValidateDir(src).PackType {
+		case "behavior", "mixed":
+			packType = DevPackTypeBehavior
+		case "resources":
+			packType = DevPackTypeResources
+		}
+	}
+	if packType != DevPackTypeBehavior && packType != DevPackTypeResources {
+		return "ERR_UNSUPPORTED_PACK_TYPE"
+	}
+
+	list := readDevPacks(versionName)
+	for _, d := range list {
+		if sameInstanceBackupPath(d.SourcePath, src) && d.PackType == packType {
+			return "ERR_DUPLICATE_FOLDER"
+		}
+	}
+	roots := GetContentRoots(versionName)
+	if strings.TrimSpace(roots.ResourcePacks) == "" {
+		return "ERR_ACCESS_VERSIONS_DIR"
+	}
+	devRoot := filepath.Join(filepath.Dir(roots.ResourcePacks), "development_resource_packs")
+	if packType == DevPackTypeBehavior {
+		devRoot = filepath.Join(filepath.Dir(roots.ResourcePacks), "development_behavior_packs")
+	}
+	if err := os.MkdirAll(devRoot, 0o755); err != nil {
+		return "ERR_CREATE_TARGET_DIR"
+	}
+	target := filepath.Join(devRoot, uniqueEntryName(devRoot, utils.SanitizeFilename(filepath.Base(src)), true))
+
+	d := types.DevPack{
+		ID:          uuid.NewString(),
+		VersionName: strings.TrimSpace(versionName),
+		SourcePath:  src,
+		TargetPath:  target,
+		PackType:    packType,
+		Mode:        mode,
+		CreatedAt:   time.Now().Unix(),
+		AutoWatch:   true,
+	}
+	if mode == DevPackModeLink {
+		if err := dirlink.Create(target, src); err != nil {
+			return "ERR_LINK"
+		}
+	} else if _, err := devpack.Sync(src, target); err != nil {
+		_ = os.RemoveAll(target)
+		return "ERR_WRITE_FILE"
+	}
+	if err := writeDevPacks(versionName, append(list, d)); err != nil {
+		removeDevPackTarget(d)
+		return "ERR_WRITE_TARGET"
+	}
+	return StartDevPackWatch(versionName, d.ID)
+}
+
+func removeDevPackTarget(d types.DevPack) {
+	if d.Mode == DevPackModeLink {
+		if dirlink.IsLink(d.TargetPath) {
+			_ = dirlink.Remove(d.TargetPath)
+		}
+		return
+	}
+	if dirlink.IsLink(d.TargetPath) {
+		return
+	}
+	_ = os.RemoveAll(d.TargetPath)
+}
+
+// UnregisterDevPack stops watching a development pack and removes its copy or link from the game. The
+// working folder is never touched.
+func UnregisterDevPack(versionName string, id string) string {
+	list := readDevPacks(versionName)
+	for i, d := range list {
+		if d.ID != strings.TrimSpace(id) {
+			continue
+		}
+		stopDevPackWatcher(d.ID)
+		removeDevPackTarget(d)
+		if err := writeDevPacks(versionName, append(list[:i], list[i+1:]...)); err != nil {
+			return "ERR_WRITE_TARGET"
+		}
+		return ""
+	}
+	return "ERR_NOT_FOUND"
+}
+
+// StartDevPackWatch starts watching a development pack and remembers it, so ResumeDevPackWatches
+// restarts the watcher after a launcher restart.
+func StartDevPackWatch(versionName string, id string) string {
+	d, ok := findDevPack(versionName, id)
+	if !ok {
+		return "ERR_NOT_FOUND"
+	}
+	if !d.AutoWatch {
+		if code := setDevPackAutoWatch(versionName, d.ID, true); code != "" {
+			return code
+		}
+	}
+	startDevPackWatcher(d)
+	return ""
+}
+
+func startDevPackWatcher(d types.DevPack) {
+	devPackWatchMu.Lock()
+	if _, running := devPackWatchers[d.ID]; running {
+		devPackWatchMu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	devPackWatchers[d.ID] = cancel
+	devPackWatchMu.Unlock()
+
+	go func() {
+		emitDevPackSync(syncDevPack(d, nil))
+		devpack.Watch(ctx, d.SourcePath, devpack.DefaultInterval, func(changed []string) {
+			emitDevPackSync(syncDevPack(d, changed))
+		})
+	}()
+}
+
+// StopDevPackWatch stops watching a development pack until StartDevPackWatch is called again.
+func StopDevPackWatch(versionName string, id string) string {
+	stopDevPackWatcher(strings.TrimSpace(id))
+	return setDevPackAutoWatch(versionName, id, false)
+}
+
+func stopDevPackWatcher(id string) {
+	devPackWatchMu.Lock()
+	defer devPackWatchMu.Unlock()
+	if cancel, ok := devPackWatchers[id]; ok {
+		cancel()
+		delete(devPackWatchers, id)
+	}
+}
+
+func setDevPackAutoWatch(versionName string, id string, watch bool) string {
+	list := readDevPacks(versionName)
+	for i := range list {
+		if list[i].ID != strings.TrimSpace(id) {
+			continue
+		}
+		list[i].AutoWatch = watch
+		if err := writeDevPacks(versionName, list); err != nil {
+			return "ERR_WRITE_TARGET"
+		}
+		return ""
+	}
+	return "ERR_NOT_FOUND"
+}
+
+// ResumeDevPackWatches restarts the watchers of every development pack that was being watched when the
+// launcher last exited.
+func ResumeDevPackWatches() {
+	for _, m := range ListVersionMetas() {
+		for _, d := range readDevPacks(m.Name) {
+			if d.AutoWatch {
+				startDevPackWatcher(d)
+			}
+		}
+	}
+}
+
+// SyncDevPack runs one sync and validation pass immediately.
+func SyncDevPack(versionName string, id string) types.DevPackSyncEvent {
+	d, ok := findDevPack(versionName, id)
+	if !ok {
+		return types.DevPackSyncEvent{ID: id, VersionName: versionName, State: DevPackStateError, ErrorCode: "ERR_NOT_FOUND", Ts: time.Now().Unix()}
+	}
+	ev := syncDevPack(d, nil)
+	emitDevPackSync(ev)
+	return ev
+}
+
+func syncDevPack(d types.DevPack, changed []string) types.DevPackSyncEvent {
+	devPackSyncMu.Lock()
+	defer devPackSyncMu.Unlock()
+	ev := types.DevPackSyncEvent{
+		ID:          d.ID,
+		VersionName: d.VersionName,
+		State:       DevPackStateSynced,
+		Changed:     changed,
+		Ts:          time.Now().Unix(),
+	}
+	if ev.Changed == nil {
+		ev.Changed = []string{}
+	}
+	if !utils.DirExists(d.SourcePath) {
+		ev.State = DevPackStateError
+		ev.ErrorCode = "ERR_INVALID_PATH"
+		return ev
+	}
+	if d.Mode == DevPackModeMirror {
+		stats, err := devpack.Sync(d.SourcePath, d.TargetPath)
+		ev.Stats = stats
+		if err != nil {
+			ev.State = DevPackStateError
+			ev.ErrorCode = "ERR_WRITE_FILE"
+			return ev
+		}
+	} else if !dirlink.SameTarget(d.TargetPath, d.SourcePath) {
+		ev.State = DevPackStateError
+		ev.ErrorCode = "ERR_LINK"
+		return ev
+	}
+	ev.Report = packlint.ValidateDir(d.SourcePath)
+	if ev.Report.ErrorCount > 0 {
+		ev.State = DevPackStateInvalid
+	}
+	return ev
+}
+
+func emitDevPackSync(ev types.DevPackSyncEvent) {
+	app := application.Get()
+	if app == nil || app.Event == nil {
+		return
+	}
+	app.Event.Emit(EventDevPackSync, ev)
+}
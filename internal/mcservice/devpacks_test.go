@@ -0,0 +1,43 @@
+package mcservice
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/versions"
+)
+
+func TestDevPackWatchSurvivesRestart(t *testing.T) {
+	root, _, versionsDir := setupInstanceBackupEnv(t)
+	const uuid = "0f8d6c4e-2b1a-4c3d-9e8f-1a2b3c4d5e6f"
+	createTestInstance(t, versionsDir, "A", versions.VersionMeta{EnableIsolation: true})
+	src := writeDuplicateTestPack(t, filepath.Join(root, "work"), "rp", uuid, "texture")
+
+	if code := RegisterDevPack("A", src, DevPackTypeResources, DevPackModeMirror); code != "" {
+		t.Fatalf("register: %s", code)
+	}
+	list := ListDevPacks("A")
+	if len(list) != 1 || !list[0].AutoWatch || !list[0].Watching {
+		t.Fatalf("after register: %+v", list)
+	}
+	id := list[0].ID
+	t.Cleanup(func() { stopDevPackWatcher(id) })
+
+	// A launcher restart loses the watchers but keeps devpacks.json.
+	stopDevPackWatcher(id)
+	if list := ListDevPacks("A"); list[0].Watching {
+		t.Fatalf("watcher still reported after restart: %+v", list)
+	}
+	ResumeDevPackWatches()
+	if list := ListDevPacks("A"); !list[0].Watching {
+		t.Fatalf("watcher not resumed: %+v", list)
+	}
+
+	if code := StopDevPackWatch("A", id); code != "" {
+		t.Fatalf("stop: %s", code)
+	}
+	ResumeDevPackWatches()
+	if list := ListDevPacks("A"); list[0].AutoWatch || list[0].Watching {
+		t.Fatalf("stopped pack was resumed: %+v", list)
+	}
+}
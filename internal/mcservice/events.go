@@ -5,4 +5,9 @@ const (
 	EventExtractDone                   = "extract.done"
 	EventExtractProgress               = "extract.progress"
 	EventInstanceBackupRestoreProgress = "instance_backup.restore.progress"
+	EventDevPackSync                   = "devpack.sync"
+	EventWorldAutoBackup               = "world.autobackup"
+	EventContentTransferProgress       = "content.transfer.progress"
+	EventIsolationMigrationProgress    = "isolation.migration.progress"
+	EventWorldCompatLaunchPrompt       = "world.compat.launch_prompt"
 )
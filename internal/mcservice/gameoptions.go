@@ -0,0 +1,153 @@
+package mcservice
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/gameoptions"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+// gameOptionsPath returns the options.txt of a player of a version. A version without isolation uses the
+// shared game data.
+func gameOptionsPath(versionName string, player string) (string, string) {
+	p := strings.TrimSpace(player)
+	if p == "" || p == "." || p == ".." || strings.ContainsAny(p, `/\`) {
+		return "", "ERR_INVALID_PATH"
+	}
+	roots := GetContentRoots(versionName)
+	if strings.TrimSpace(roots.UsersRoot) == "" {
+		return "", "ERR_ACCESS_VERSIONS_DIR"
+	}
+	return filepath.Join(roots.UsersRoot, p, "games", "com.mojang", "minecraftpe", "options.txt"), ""
+}
+
+func GetGameOptionSchema() []types.GameOptionDef {
+	return gameoptions.Schema()
+}
+
+// ReadGameOptions reads every option of a player in file order. A player who has not started the game
+// yet has no options file; Exists is unset and Options is empty.
+func ReadGameOptions(versionName string, player string) types.GameOptions {
+	res := types.GameOptions{VersionName: strings.TrimSpace(versionName), Player: strings.TrimSpace(player), Options: []types.GameOption{}}
+	path, code := gameOptionsPath(versionName, player)
+	if code != "" {
+		res.ErrorCode = code
+		return res
+	}
+	res.Path = path
+	if !utils.FileExists(path) {
+		return res
+	}
+	f, err := gameoptions.Read(path)
+	if err != nil {
+		res.ErrorCode = "ERR_READ_FILE"
+		return res
+	}
+	res.Exists = true
+	res.Options = gameoptions.Typed(f)
+	return res
+}
+
+// editGameOptions loads the options file at path, or an empty one when the player has none yet, lets
+// edit change it and writes it back. The game rewrites the file when it exits, so editing is refused
+// while it runs.
+func editGameOptions(path string, edit func(f *gameoptions.File) string) string {
+	if len(ListMinecraftProcesses()) > 0 {
+		return "ERR_GAME_RUNNING"
+	}
+	f := gameoptions.Parse(nil)
+	if utils.FileExists(path) {
+		var err error
+		if f, err = gameoptions.Read(path); err != nil {
+			return "ERR_READ_FILE"
+		}
+	}
+	if code := edit(f); code != "" {
+		return code
+	}
+	if err := f.Write(path); err != nil {
+		return "ERR_WRITE_FILE"
+	}
+	return ""
+}
+
+// SetGameOptions changes the given options and keeps every other line of the file. All values are
+// checked before anything is written.
+func SetGameOptions(versionName string, player string, values map[string]string) string {
+	path, code := gameOptionsPath(versionName, player)
+	if code != "" {
+		return code
+	}
+	for k, v := range values {
+		if gameoptions.Validate(k, v) != nil {
+			return "ERR_INVALID_OPTION_VALUE"
+		}
+	}
+	return editGameOptions(path, func(f *gameoptions.File) string {
+		for _, k := range sortedKeys(values) {
+			f.Set(k, values[k])
+		}
+		return ""
+	})
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CopyGameOptions copies options from one player to another, across versions as well. With no keys the
+// whole file is copied; otherwise only keys matching one of them, where a trailing "*" matches a prefix,
+// are copied into the target file and the rest of it is kept.
+func CopyGameOptions(srcVersion string, srcPlayer string, dstVersion string, dstPlayer string, keys []string) string {
+	src, code := gameOptionsPath(srcVersion, srcPlayer)
+	if code != "" {
+		return code
+	}
+	dst, code := gameOptionsPath(dstVersion, dstPlayer)
+	if code != "" {
+		return code
+	}
+	if strings.EqualFold(filepath.Clean(src), filepath.Clean(dst)) {
+		return "ERR_SAME_LOCATION"
+	}
+	if !utils.FileExists(src) {
+		return "ERR_OPTIONS_NOT_FOUND"
+	}
+	if len(ListMinecraftProcesses()) > 0 {
+		return "ERR_GAME_RUNNING"
+	}
+	from, err := gameoptions.Read(src)
+	if err != nil {
+		return "ERR_READ_FILE"
+	}
+	to := from
+	if len(keys) > 0 {
+		to = gameoptions.Parse(nil)
+		if utils.FileExists(dst) {
+			if to, err = gameoptions.Read(dst); err != nil {
+				return "ERR_READ_FILE"
+			}
+		}
+		for _, k := range from.Keys() {
+			for _, pattern := range keys {
+				if gameoptions.MatchKey(strings.TrimSpace(pattern), k) {
+					v, _ := from.Get(k)
+					to.Set(k, v)
+					break
+				}
+			}
+		}
+	}
+	if err := to.Write(dst); err != nil {
+		return "ERR_WRITE_FILE"
+	}
+	return ""
+}
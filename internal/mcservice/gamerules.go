@@ -0,0 +1,291 @@
+package mcservice
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/content"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+const (
+	GameRuleTypeBool = "bool"
+	GameRuleTypeInt  = "int"
+)
+
+type gameRuleDef struct {
+	name    string
+	typ     string
+	since   string
+	defBool bool
+	defInt  int32
+	minInt  int32
+	maxInt  int32
+}
+
+func boolRule(name string, def bool, since string) gameRuleDef {
+	return gameRuleDef{name: name, typ: GameRuleTypeBool, since: since, defBool: def}
+}
+
+func intRule(name string, def int32, min int32, max int32, since string) gameRuleDef {
+	return gameRuleDef{name: name, typ: GameRuleTypeInt, since: since, defInt: def, minInt: min, maxInt: max}
+}
+
+// gameRuleDefs lists the game rules Bedrock stores in level.dat, under their level.dat names, with the
+// defaults of a new world and the game version that added them.
+var gameRuleDefs = []gameRuleDef{
+	boolRule("commandblockoutput", true, "1.0.0"),
+	boolRule("dodaylightcycle", true, "1.0.0"),
+	boolRule("doentitydrops", true, "1.0.0"),
+	boolRule("dofiretick", true, "1.0.0"),
+	boolRule("domobloot", true, "1.0.0"),
+	boolRule("domobspawning", true, "1.0.0"),
+	boolRule("dotiledrops", true, "1.0.0"),
+	boolRule("doweathercycle", true, "1.0.0"),
+	boolRule("keepinventory", false, "1.0.0"),
+	boolRule("mobgriefing", true, "1.0.0"),
+	boolRule("naturalregeneration", true, "1.0.0"),
+	boolRule("pvp", true, "1.0.0"),
+	boolRule("sendcommandfeedback", true, "1.0.0"),
+	boolRule("tntexplodes", true, "1.0.0"),
+	boolRule("drowningdamage", true, "1.2.0"),
+	boolRule("falldamage", true, "1.2.0"),
+	boolRule("firedamage", true, "1.2.0"),
+	boolRule("showcoordinates", false, "1.2.0"),
+	intRule("maxcommandchainlength", 65535, 0, 2147483647, "1.2.0"),
+	boolRule("commandblocksenabled", true, "1.5.0"),
+	boolRule("doinsomnia", true, "1.6.0"),
+	boolRule("showdeathmessages", true, "1.8.0"),
+	intRule("functioncommandlimit", 10000, 0, 2147483647, "1.8.0"),
+	intRule("randomtickspeed", 1, 0, 4096, "1.8.0"),
+	intRule("spawnradius", 5, 0, 128, "1.12.0"),
+	boolRule("doimmediaterespawn", false, "1.13.0"),
+	boolRule("showtags", true, "1.16.100"),
+	boolRule("freezedamage", true, "1.17.0"),
+	intRule("playerssleepingpercentage", 100, 0, 100, "1.17.10"),
+	boolRule("respawnblocksexplode", true, "1.19.10"),
+	boolRule("showbordereffect", true, "1.20.0"),
+	boolRule("dolimitedcrafting", false, "1.20.30"),
+	boolRule("recipesunlock", true, "1.20.30"),
+	boolRule("projectilescanbreakblocks", true, "1.21.0"),
+	boolRule("tntexplosiondropdecay", false, "1.21.0"),
+	boolRule("showdaysplayed", false, "1.21.20"),
+}
+
+// worldExperimentDefs lists the experiment toggles stored in the experiments compound of level.dat.
+var worldExperimentDefs = []struct {
+	name  string
+	title string
+	since string
+}{
+	{"gametest", "Beta APIs", "1.16.210"},
+	{"data_driven_items", "Holiday Creator Features", "1.16.100"},
+	{"upcoming_creator_features", "Upcoming Creator Features", "1.18.10"},
+	{"experimental_molang_features", "Experimental Molang Features", "1.19.60"},
+	{"data_driven_biomes", "Custom Biomes", "1.17.0"},
+	{"villager_trades_rebalance", "Villager Trade Rebalancing", "1.20.50"},
+	{"jigsaw_structures", "Data-Driven Jigsaw Structures", "1.21.20"},
+	{"experimental_creator_camera", "Experimental Creator Camera Features", "1.21.20"},
+	{"y_2025_drop_3", "Drop 3 2025", "1.21.90"},
+}
+
+// Flags the game sets in the experiments compound once any experiment has been turned on.
+const (
+	experimentsEverUsed        = "experiments_ever_used"
+	savedWithToggledExperiment = "saved_with_toggled_experiments"
+)
+
+func findGameRuleDef(name string) (gameRuleDef, bool) {
+	for _, d := range gameRuleDefs {
+		if d.name == name {
+			return d, true
+		}
+	}
+	return gameRuleDef{}, false
+}
+
+func gameRuleFromDef(d gameRuleDef) types.GameRule {
+	return types.GameRule{
+		Name:        d.name,
+		Type:        d.typ,
+		Since:       d.since,
+		BoolValue:   d.defBool,
+		IntValue:    d.defInt,
+		DefaultBool: d.defBool,
+		DefaultInt:  d.defInt,
+		Min:         d.minInt,
+		Max:         d.maxInt,
+	}
+}
+
+// GetWorldGameRules reads the known game rules and experiments of a world. Rules missing from level.dat
+// report their default with Present unset; the game fills them in the next time it saves the world.
+func GetWorldGameRules(worldDir string) types.WorldGameRules {
+	res := types.WorldGameRules{Rules: []types.GameRule{}, Experiments: []types.WorldExperiment{}}
+	if strings.TrimSpace(worldDir) == "" || !utils.DirExists(worldDir) {
+		res.ErrorCode = "ERR_INVALID_WORLD_DIR"
+		return res
+	}
+	fields, _, err := content.ReadLevelDatFields(worldDir)
+	if err != nil {
+		res.ErrorCode = "ERR_READ_LEVEL_DAT"
+		return res
+	}
+	byName := map[string]types.LevelDatField{}
+	for _, f := range fields {
+		byName[f.Name] = f
+	}
+	for _, d := range gameRuleDefs {
+		r := gameRuleFromDef(d)
+		if f, ok := byName[d.name]; ok {
+			n, err := strconv.ParseInt(strings.TrimSpace(f.ValueString), 10, 32)
+			if err == nil {
+				r.Present = true
+				r.BoolValue = n != 0
+				r.IntValue = int32(n)
+			}
+		}
+		res.Rules = append(res.Rules, r)
+	}
+
+	expFields, _, _ := content.ReadLevelDatFieldsAt(worldDir, []string{"experiments"})
+	exps := map[string]types.LevelDatField{}
+	for _, f := range expFields {
+		exps[f.Name] = f
+	}
+	for _, d := range worldExperimentDefs {
+		e := types.WorldExperiment{Name: d.name, Title: d.title, Since: d.since}
+		if f, ok := exps[d.name]; ok {
+			e.Present = true
+			e.Enabled = strings.TrimSpace(f.ValueString) == "1"
+		}
+		res.Experiments = append(res.Experiments, e)
+	}
+	return res
+}
+
+// worldGameVersion returns the lastOpenedWithVersion of a world, or an empty string when level.dat does
+// not record one.
+func worldGameVersion(worldDir string) (string, string) {
+	root, _, err := content.DecodeLevelDat(worldDir)
+	if err != nil {
+		return "", "ERR_READ_LEVEL_DAT"
+	}
+	return content.LastOpenedVersion(root), ""
+}
+
+// gameRuleKnownBy reports whether the game version a world was last opened in has the rule. A world
+// without a recorded version accepts every rule.
+func gameRuleKnownBy(d gameRuleDef, worldVersion string) bool {
+	return worldVersion == "" || content.CompareGameVersions(worldVersion, d.since, 3) >= 0
+}
+
+// SetWorldGameRules writes the given rules after checking each one against the known rule table and
+// against the game version the world was last opened in, which ignores rules added after it. Only Name
+// and the value matching the rule's type are read from each entry.
+func SetWorldGameRules(worldDir string, rules []types.GameRule) string {
+	if strings.TrimSpace(worldDir) == "" || !utils.DirExists(worldDir) {
+		return "ERR_INVALID_WORLD_DIR"
+	}
+	worldVersion, code := worldGameVersion(worldDir)
+	if code != "" {
+		return code
+	}
+	fields := make([]types.LevelDatField, 0, len(rules))
+	for _, r := range rules {
+		d, ok := findGameRuleDef(strings.TrimSpace(r.Name))
+		if !ok {
+			return "ERR_UNKNOWN_GAME_RULE"
+		}
+		if !gameRuleKnownBy(d, worldVersion) {
+			return "ERR_GAME_RULE_TOO_NEW"
+		}
+		f := types.LevelDatField{Name: d.name}
+		switch d.typ {
+		case GameRuleTypeBool:
+			f.Tag = "byte"
+			f.ValueString = "0"
+			if r.BoolValue {
+				f.ValueString = "1"
+			}
+		case GameRuleTypeInt:
+			if r.IntValue < d.minInt || r.IntValue > d.maxInt {
+				return "ERR_INVALID_GAME_RULE_VALUE"
+			}
+			f.Tag = "int"
+			f.ValueString = strconv.FormatInt(int64(r.IntValue), 10)
+		}
+		fields = append(fields, f)
+	}
+	return writeLevelDatFields(worldDir, nil, fields)
+}
+
+// ResetWorldGameRules writes the default value of every known game rule the world's game version has.
+func ResetWorldGameRules(worldDir string) string {
+	if strings.TrimSpace(worldDir) == "" || !utils.DirExists(worldDir) {
+		return "ERR_INVALID_WORLD_DIR"
+	}
+	worldVersion, code := worldGameVersion(worldDir)
+	if code != "" {
+		return code
+	}
+	rules := make([]types.GameRule, 0, len(gameRuleDefs))
+	for _, d := range gameRuleDefs {
+		if gameRuleKnownBy(d, worldVersion) {
+			rules = append(rules, gameRuleFromDef(d))
+		}
+	}
+	return SetWorldGameRules(worldDir, rules)
+}
+
+// SetWorldExperiments toggles experiments by name. Turning one on also sets the flags the game uses to
+// mark the world as having used experiments, which it never clears.
+func SetWorldExperiments(worldDir string, enabled map[string]bool) string {
+	if strings.TrimSpace(worldDir) == "" || !utils.DirExists(worldDir) {
+		return "ERR_INVALID_WORLD_DIR"
+	}
+	known := map[string]bool{}
+	for _, d := range worldExperimentDefs {
+		known[d.name] = true
+	}
+	var fields []types.LevelDatField
+	anyOn := false
+	for name, on := range enabled {
+		if !known[name] {
+			return "ERR_UNKNOWN_EXPERIMENT"
+		}
+		v := "0"
+		if on {
+			v = "1"
+			anyOn = true
+		}
+		fields = append(fields, types.LevelDatField{Name: name, Tag: "byte", ValueString: v})
+	}
+	if anyOn {
+		fields = append(fields,
+			types.LevelDatField{Name: experimentsEverUsed, Tag: "byte", ValueString: "1"},
+			types.LevelDatField{Name: savedWithToggledExperiment, Tag: "byte", ValueString: "1"},
+		)
+	}
+	return writeLevelDatFields(worldDir, []string{"experiments"}, fields)
+}
+
+func writeLevelDatFields(worldDir string, path []string, fields []types.LevelDatField) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	_, ver, err := content.ReadLevelDatFields(worldDir)
+	if err != nil {
+		return "ERR_READ_LEVEL_DAT"
+	}
+	if len(path) == 0 {
+		err = content.WriteLevelDatFields(worldDir, fields, ver)
+	} else {
+		err = content.WriteLevelDatFieldsAt(worldDir, path, fields, ver)
+	}
+	if err != nil {
+		return "ERR_WRITE_FILE"
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package mcservice
+
+import (
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/content"
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+func findGameRule(t *testing.T, res types.WorldGameRules, name string) types.GameRule {
+	t.Helper()
+	for _, r := range res.Rules {
+		if r.Name == name {
+			return r
+		}
+	}
+	t.Fatalf("rule %s not reported", name)
+	return types.GameRule{}
+}
+
+func TestWorldGameRulesValidateAndReset(t *testing.T) {
+	world := t.TempDir()
+	root := map[string]any{
+		"LevelName":       "rules",
+		"keepinventory":   byte(0),
+		"randomtickspeed": int32(1),
+		"experiments":     map[string]any{},
+	}
+	if err := content.EncodeLevelDat(world, 10, root); err != nil {
+		t.Fatalf("write level.dat: %v", err)
+	}
+
+	if code := SetWorldGameRules(world, []types.GameRule{{Name: "playerssleepingpercentage", IntValue: 101}}); code != "ERR_INVALID_GAME_RULE_VALUE" {
+		t.Fatalf("expected ERR_INVALID_GAME_RULE_VALUE, got %q", code)
+	}
+	if code := SetWorldGameRules(world, []types.GameRule{{Name: "nosuchrule"}}); code != "ERR_UNKNOWN_GAME_RULE" {
+		t.Fatalf("expected ERR_UNKNOWN_GAME_RULE, got %q", code)
+	}
+	if code := SetWorldGameRules(world, []types.GameRule{{Name: "keepinventory", BoolValue: true}, {Name: "randomtickspeed", IntValue: 3}}); code != "" {
+		t.Fatalf("set rules: %s", code)
+	}
+	res := GetWorldGameRules(world)
+	if r := findGameRule(t, res, "keepinventory"); !r.Present || !r.BoolValue {
+		t.Fatalf("keepinventory not written: %+v", r)
+	}
+	if r := findGameRule(t, res, "randomtickspeed"); r.IntValue != 3 {
+		t.Fatalf("randomtickspeed not written: %+v", r)
+	}
+	if r := findGameRule(t, res, "showcoordinates"); r.Present || r.BoolValue {
+		t.Fatalf("missing rule should report its default: %+v", r)
+	}
+
+	if code := ResetWorldGameRules(world); code != "" {
+		t.Fatalf("reset: %s", code)
+	}
+	res = GetWorldGameRules(world)
+	if r := findGameRule(t, res, "keepinventory"); r.BoolValue {
+		t.Fatalf("keepinventory not reset: %+v", r)
+	}
+	if r := findGameRule(t, res, "randomtickspeed"); r.IntValue != 1 {
+		t.Fatalf("randomtickspeed not reset: %+v", r)
+	}
+
+	if code := SetWorldExperiments(world, map[string]bool{"gametest": true}); code != "" {
+		t.Fatalf("set experiments: %s", code)
+	}
+	decoded, _, err := content.DecodeLevelDat(world)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	exp, _ := decoded["experiments"].(map[string]any)
+	if exp["gametest"] != byte(1) || exp[experimentsEverUsed] != byte(1) {
+		t.Fatalf("unexpected experiments: %+v", exp)
+	}
+}
+
+func TestSetWorldGameRulesRejectsRulesNewerThanWorld(t *testing.T) {
+	world := t.TempDir()
+	root := map[string]any{
+		"LevelName":             "old",
+		"lastOpenedWithVersion": []int32{1, 20, 0, 1, 0},
+		"keepinventory":         byte(0),
+	}
+	if err := content.EncodeLevelDat(world, 10, root); err != nil {
+		t.Fatalf("write level.dat: %v", err)
+	}
+
+	if code := SetWorldGameRules(world, []types.GameRule{{Name: "showdaysplayed", BoolValue: true}}); code != "ERR_GAME_RULE_TOO_NEW" {
+		t.Fatalf("expected ERR_GAME_RULE_TOO_NEW, got %q", code)
+	}
+	if code := SetWorldGameRules(world, []types.GameRule{{Name: "showbordereffect", BoolValue: false}}); code != "" {
+		t.Fatalf("rule of the world's release: %s", code)
+	}
+	if code := ResetWorldGameRules(world); code != "" {
+		t.Fatalf("reset: %s", code)
+	}
+	decoded, _, err := content.DecodeLevelDat(world)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if _, ok := decoded["showdaysplayed"]; ok {
+		t.Fatal("reset wrote a rule the world's version does not know")
+	}
+	if decoded["showbordereffect"] != byte(1) {
+		t.Fatalf("showbordereffect not reset: %v", decoded["showbordereffect"])
+	}
+}
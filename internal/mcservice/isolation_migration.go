@@ -0,0 +1,106 @@
+package mcservice
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/apppath"
+	"github.com/liteldev/LeviLauncher/internal/isomigrate"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+	"github.com/liteldev/LeviLauncher/internal/versions"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// isolationUsersDirs returns the Users folders of the shared GDK data and of the private data of a
+// version, whichever mode it is in now.
+func isolationUsersDirs(name string) (string, string, string) {
+	vdir, err := apppath.VersionsDir()
+	if err != nil || strings.TrimSpace(vdir) == "" {
+		return "", "", "ERR_ACCESS_VERSIONS_DIR"
+	}
+	n := strings.TrimSpace(name)
+	if n == "" || versions.ValidateFolderName(n) != "" {
+		return "", "", "ERR_INVALID_NAME"
+	}
+	meta, err := versions.ReadMeta(filepath.Join(vdir, n))
+	if err != nil {
+		return "", "", "ERR_READ_VERSION_META"
+	}
+	isPreview := strings.EqualFold(strings.TrimSpace(meta.Type), "preview")
+	gameDirName := "Minecraft Bedrock"
+	if isPreview {
+		gameDirName = "Minecraft Bedrock Preview"
+	}
+	shared := strings.TrimSpace(utils.GetMinecraftGDKDataPath(isPreview))
+	if shared == "" {
+		return "", "", "ERR_ACCESS_VERSIONS_DIR"
+	}
+	return filepath.Join(shared, "Users"), filepath.Join(vdir, n, gameDirName, "Users"), ""
+}
+
+func isolationMigrationDirs(name string, enable bool) (string, string, string) {
+	shared, isolated, code := isolationUsersDirs(name)
+	if enable {
+		return shared, isolated, code
+	}
+	return isolated, shared, code
+}
+
+// PreviewIsolationMigration lists what switching a version to isolated data (enable) or back to the
+// shared data would carry over, and which items collide with data already at the destination.
+func PreviewIsolationMigration(name string, enable bool) types.IsolationMigrationPreview {
+	res := types.IsolationMigrationPreview{VersionName: strings.TrimSpace(name), Enable: enable, Items: []types.IsolationMigrationItem{}}
+	src, dst, code := isolationMigrationDirs(name, enable)
+	if code != "" {
+		res.ErrorCode = code
+		return res
+	}
+	if meta, err := readVersionMetaByName(name); err == nil && meta.EnableIsolation == enable {
+		res.ErrorCode = "ERR_ISOLATION_UNCHANGED"
+		return res
+	}
+	res.SourceRoot, res.TargetRoot = src, dst
+	res.Items = isomigrate.Scan(src, dst)
+	return res
+}
+
+func emitIsolationMigrationProgress(p types.IsolationMigrationProgress) {
+	app := application.Get()
+	if app == nil || app.Event == nil {
+		return
+	}
+	app.Event.Emit(EventIsolationMigrationProgress, p)
+}
+
+// MigrateIsolation copies or moves the selected items between the shared data and the private data of a
+// version and switches its isolation flag once they are all in place. With nothing selected it only
+// switches the flag. A failure rolls the destination back and leaves the flag unchanged.
+func MigrateIsolation(name string, req types.IsolationMigrationRequest) types.IsolationMigrationResult {
+	src, dst, code := isolationMigrationDirs(name, req.Enable)
+	if code != "" {
+		return types.IsolationMigrationResult{Status: isomigrate.StatusFailed, SourcesLeft: []string{}, ErrorCode: code}
+	}
+	if meta, err := readVersionMetaByName(name); err != nil {
+		return types.IsolationMigrationResult{Status: isomigrate.StatusFailed, SourcesLeft: []string{}, ErrorCode: "ERR_READ_VERSION_META"}
+	} else if meta.EnableIsolation == req.Enable {
+		return types.IsolationMigrationResult{Status: isomigrate.StatusFailed, SourcesLeft: []string{}, ErrorCode: "ERR_ISOLATION_UNCHANGED"}
+	}
+	if len(ListMinecraftProcesses()) > 0 {
+		return types.IsolationMigrationResult{Status: isomigrate.StatusFailed, SourcesLeft: []string{}, ErrorCode: "ERR_GAME_RUNNING"}
+	}
+	commit := func() error {
+		vdir, err := apppath.VersionsDir()
+		if err != nil {
+			return err
+		}
+		dir := filepath.Join(vdir, strings.TrimSpace(name))
+		meta, err := versions.ReadMeta(dir)
+		if err != nil {
+			return err
+		}
+		meta.EnableIsolation = req.Enable
+		return versions.WriteMeta(dir, meta)
+	}
+	return isomigrate.Apply(src, dst, req, commit, emitIsolationMigrationProgress)
+}
@@ -0,0 +1,342 @@
+package mcservice
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/apppath"
+	"github.com/liteldev/LeviLauncher/internal/dirlink"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+// The content library keeps one copy of a pack under BaseRoot/library/<root>/<folder>. Versions use a
+// library pack through a directory link placed in their own pack folder, so the game loads it like any
+// other installed pack.
+
+func libraryRootDir(rootName string) (string, bool) {
+	if !isPackRootName(rootName) {
+		return "", false
+	}
+	dir, err := apppath.LibraryDir()
+	if err != nil || strings.TrimSpace(dir) == "" {
+		return "", false
+	}
+	return filepath.Join(dir, rootName), true
+}
+
+func isPackRootName(rootName string) bool {
+	for _, n := range packRootNames {
+		if n == rootName {
+			return true
+		}
+	}
+	return false
+}
+
+func versionPackRootDir(versionName string, rootName string) string {
+	roots := GetContentRoots(versionName)
+	if strings.TrimSpace(roots.ResourcePacks) == "" {
+		return ""
+	}
+	switch rootName {
+	case "resource_packs":
+		return roots.ResourcePacks
+	case "behavior_packs":
+		return roots.BehaviorPacks
+	case "skin_packs":
+		return filepath.Join(filepath.Dir(roots.ResourcePacks), "skin_packs")
+	}
+	return ""
+}
+
+// splitVersionPackPath returns the root folder name of a pack path that sits directly inside one of the
+// pack folders of versionName.
+func splitVersionPackPath(versionName string, packPath string) (string, bool) {
+	clean := filepath.Clean(strings.TrimSpace(packPath))
+	for _, rootName := range packRootNames {
+		root := versionPackRootDir(versionName, rootName)
+		if root != "" && sameInstanceBackupPath(filepath.Dir(clean), root) {
+			return rootName, true
+		}
+	}
+	return "", false
+}
+
+func splitLibraryPackPath(packPath string) (string, bool) {
+	clean := filepath.Clean(strings.TrimSpace(packPath))
+	for _, rootName := range packRootNames {
+		root, ok := libraryRootDir(rootName)
+		if ok && sameInstanceBackupPath(filepath.Dir(clean), root) {
+			return rootName, true
+		}
+	}
+	return "", false
+}
+
+func isLibraryTarget(target string) bool {
+	if strings.TrimSpace(target) == "" {
+		return false
+	}
+	_, ok := splitLibraryPackPath(target)
+	return ok
+}
+
+func ListLibraryPacks() []types.LibraryPack {
+	out := []types.LibraryPack{}
+	links := ListLibraryLinks("")
+	for _, rootName := range packRootNames {
+		root, ok := libraryRootDir(rootName)
+		if !ok {
+			continue
+		}
+		ents, err := os.ReadDir(root)
+		if err != nil {
+			continue
+		}
+		for _, e := range ents {
+			if !e.IsDir() {
+				continue
+			}
+			dir := filepath.Join(root, e.Name())
+			meta, ok := readInstanceBackupPackMetadata(dir, rootName, e.Name())
+			if !ok {
+				continue
+			}
+			size := utils.DirSize(dir)
+			p := types.LibraryPack{
+				Path:       dir,
+				RootName:   rootName,
+				FolderName: e.Name(),
+				Name:       meta.Name,
+				UUID:       meta.UUID,
+				Version:    meta.Version,
+				Size:       size,
+				AttachedTo: []string{},
+			}
+			for _, l := range links {
+				if sameInstanceBackupPath(l.Target, dir) {
+					p.AttachedTo = append(p.AttachedTo, l.Location)
+				}
+			}
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name)
+	})
+	return out
+}
+
+// AddPackToLibrary copies a pack installed in versionName into the library. When replace is set the
+// installed copy is swapped for a link to the library pack.
+func AddPackToLibrary(versionName string, packPath string, replace bool) string {
+	src := filepath.Clean(strings.TrimSpace(packPath))
+	rootName, ok := splitVersionPackPath(versionName, src)
+	if strings.TrimSpace(packPath) == "" || !ok {
+		return "ERR_INVALID_PATH"
+	}
+	if dirlink.IsLink(src) {
+		return "ERR_ALREADY_LINKED"
+	}
+	meta, ok := readInstanceBackupPackMetadata(src, rootName, filepath.Base(src))
+	if !ok {
+		return "ERR_INVALID_PACKAGE"
+	}
+	libRoot, ok := libraryRootDir(rootName)
+	if !ok {
+		return "ERR_ACCESS_LIBRARY_DIR"
+	}
+	if err := os.MkdirAll(libRoot, 0o755); err != nil {
+		return "ERR_ACCESS_LIBRARY_DIR"
+	}
+
+	target := findLibraryCopy(libRoot, rootName, meta.UUID, meta.Version, src)
+	if target == "" {
+		target = filepath.Join(libRoot, uniqueEntryName(libRoot, meta.FolderName, true))
+		if err := utils.CopyDir(src, target); err != nil {
+			_ = os.RemoveAll(target)
+			return "ERR_COPY"
+		}
+	}
+	if !replace {
+		return ""
+	}
+	return replaceWithLibraryLink(src, target)
+}
+
+// findLibraryCopy returns a library pack with the same identity and content as src, if any.
+func findLibraryCopy(libRoot string, rootName string, uuid string, version string, src string) string {
+	ents, err := os.ReadDir(libRoot)
+	if err != nil {
+		return ""
+	}
+	srcHash := ""
+	for _, e := range ents {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(libRoot, e.Name())
+		meta, ok := readInstanceBackupPackMetadata(dir, rootName, e.Name())
+		if !ok || !strings.EqualFold(meta.UUID, uuid) || meta.Version != version {
+			continue
+		}
+		if srcHash == "" {
+			if srcHash, _, _, _, err = hashPackDir(src); err != nil {
+				return ""
+			}
+		}
+		if h, _, _, _, err := hashPackDir(dir); err == nil && h == srcHash {
+			return dir
+		}
+	}
+	return ""
+}
+
+func replaceWithLibraryLink(src string, target string) string {
+	old := src + ".levi_library"
+	_ = os.RemoveAll(old)
+	if err := os.Rename(src, old); err != nil {
+		return "ERR_LINK"
+	}
+	if err := dirlink.Create(src, target); err != nil {
+		_ = os.Rename(old, src)
+		return "ERR_LINK"
+	}
+	_ = os.RemoveAll(old)
+	return ""
+}
+
+// AttachLibraryPack links a library pack into the matching pack folder of versionName. Only isolated
+// versions are accepted, since any other version would put the link into the shared game data.
+func AttachLibraryPack(versionName string, libraryPath string) string {
+	target := filepath.Clean(strings.TrimSpace(libraryPath))
+	rootName, ok := splitLibraryPackPath(target)
+	if strings.TrimSpace(libraryPath) == "" || !ok {
+		return "ERR_INVALID_PATH"
+	}
+	if fi, err := os.Stat(target); err != nil || !fi.IsDir() {
+		return "ERR_NOT_FOUND"
+	}
+	if !GetContentRoots(versionName).IsIsolation {
+		return "ERR_VERSION_NOT_ISOLATED"
+	}
+	destRoot := versionPackRootDir(versionName, rootName)
+	if destRoot == "" {
+		return "ERR_ACCESS_VERSIONS_DIR"
+	}
+	if err := os.MkdirAll(destRoot, 0o755); err != nil {
+		return "ERR_WRITE_TARGET"
+	}
+	if ents, err := os.ReadDir(destRoot); err == nil {
+		for _, e := range ents {
+			if dirlink.SameTarget(filepath.Join(destRoot, e.Name()), target) {
+				return ""
+			}
+		}
+	}
+	link := filepath.Join(destRoot, uniqueEntryName(destRoot, filepath.Base(target), true))
+	if err := dirlink.Create(link, target); err != nil {
+		return "ERR_LINK"
+	}
+	return ""
+}
+
+// DetachLibraryPack removes a link from versionName. The library pack itself is kept.
+func DetachLibraryPack(versionName string, linkPath string) string {
+	link := filepath.Clean(strings.TrimSpace(linkPath))
+	if _, ok := splitVersionPackPath(versionName, link); strings.TrimSpace(linkPath) == "" || !ok {
+		return "ERR_INVALID_PATH"
+	}
+	if !dirlink.IsLink(link) {
+		return "ERR_NOT_LINK"
+	}
+	if err := dirlink.Remove(link); err != nil {
+		return "ERR_DELETE"
+	}
+	return ""
+}
+
+// ListLibraryLinks lists the directory links inside the pack folders of versionName, or of every known
+// location when versionName is empty. Links to folders outside the library are included too, so packs
+// consolidated by duplicate cleanup show up as well.
+func ListLibraryLinks(versionName string) []types.LibraryLink {
+	out := []types.LibraryLink{}
+	locations := listPackLocations()
+	if name := strings.TrimSpace(versionName); name != "" {
+		roots := GetContentRoots(name)
+		if strings.TrimSpace(roots.ResourcePacks) == "" {
+			return out
+		}
+		locations = []packLocation{{Label: name, VersionName: name, Shared: filepath.Dir(roots.ResourcePacks)}}
+	}
+	for _, loc := range locations {
+		for _, rootName := range packRootNames {
+			root := filepath.Join(loc.Shared, rootName)
+			ents, err := os.ReadDir(root)
+			if err != nil {
+				continue
+			}
+			for _, e := range ents {
+				p := filepath.Join(root, e.Name())
+				if !dirlink.IsLink(p) {
+					continue
+				}
+				target, _ := dirlink.Target(p)
+				out = append(out, types.LibraryLink{
+					Location:    loc.Label,
+					VersionName: loc.VersionName,
+					RootName:    rootName,
+					Path:        p,
+					Target:      target,
+					InLibrary:   isLibraryTarget(target),
+					Broken:      dirlink.IsBroken(p),
+				})
+			}
+		}
+	}
+	return out
+}
+
+// RemoveBrokenLibraryLinks deletes links whose target no longer exists and returns how many were removed.
+func RemoveBrokenLibraryLinks(versionName string) int {
+	n := 0
+	for _, l := range ListLibraryLinks(versionName) {
+		if !l.Broken {
+			continue
+		}
+		if dirlink.Remove(l.Path) == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// DeleteLibraryPack removes a pack from the library. A pack that is still attached is only removed when
+// force is set, in which case its links are detached first.
+func DeleteLibraryPack(libraryPath string, force bool) string {
+	target := filepath.Clean(strings.TrimSpace(libraryPath))
+	if _, ok := splitLibraryPackPath(target); strings.TrimSpace(libraryPath) == "" || !ok {
+		return "ERR_INVALID_PATH"
+	}
+	var attached []types.LibraryLink
+	for _, l := range ListLibraryLinks("") {
+		if sameInstanceBackupPath(l.Target, target) {
+			attached = append(attached, l)
+		}
+	}
+	if len(attached) > 0 && !force {
+		return "ERR_PACK_IN_USE"
+	}
+	for _, l := range attached {
+		if err := dirlink.Remove(l.Path); err != nil {
+			return "ERR_DELETE"
+		}
+	}
+	if err := os.RemoveAll(target); err != nil {
+		return "ERR_DELETE"
+	}
+	return ""
+}
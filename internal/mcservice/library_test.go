@@ -0,0 +1,64 @@
+package mcservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/dirlink"
+	"github.com/liteldev/LeviLauncher/internal/versions"
+)
+
+func TestLibraryPackAttachDetachAndBrokenLinks(t *testing.T) {
+	_, _, versionsDir := setupInstanceBackupEnv(t)
+	const uuid = "0f8d6c4e-2b1a-4c3d-9e8f-1a2b3c4d5e6f"
+	a := createTestInstance(t, versionsDir, "A", versions.VersionMeta{EnableIsolation: true})
+	createTestInstance(t, versionsDir, "B", versions.VersionMeta{EnableIsolation: true})
+	createTestInstance(t, versionsDir, "C", versions.VersionMeta{})
+	src := writeDuplicateTestPack(t, filepath.Join(a, instanceBackupGameDataLabel(false)), "shared", uuid, "texture")
+
+	if code := AddPackToLibrary("A", src, true); code != "" {
+		t.Fatalf("add to library: %s", code)
+	}
+	if !dirlink.IsLink(src) {
+		t.Fatalf("expected the installed copy to be replaced with a link")
+	}
+	packs := ListLibraryPacks()
+	if len(packs) != 1 || len(packs[0].AttachedTo) != 1 || packs[0].AttachedTo[0] != "A" {
+		t.Fatalf("unexpected library packs: %+v", packs)
+	}
+
+	if code := AttachLibraryPack("C", packs[0].Path); code != "ERR_VERSION_NOT_ISOLATED" {
+		t.Fatalf("expected ERR_VERSION_NOT_ISOLATED for a shared-data version, got %q", code)
+	}
+	if code := AttachLibraryPack("B", packs[0].Path); code != "" {
+		t.Fatalf("attach: %s", code)
+	}
+	if code := AttachLibraryPack("B", packs[0].Path); code != "" {
+		t.Fatalf("attaching twice should be a no-op, got %s", code)
+	}
+	links := ListLibraryLinks("B")
+	if len(links) != 1 || !links[0].InLibrary || links[0].Broken {
+		t.Fatalf("unexpected links for B: %+v", links)
+	}
+	if code := DeleteLibraryPack(packs[0].Path, false); code != "ERR_PACK_IN_USE" {
+		t.Fatalf("expected ERR_PACK_IN_USE, got %q", code)
+	}
+	if code := DetachLibraryPack("B", links[0].Path); code != "" {
+		t.Fatalf("detach: %s", code)
+	}
+	if _, err := os.Stat(filepath.Join(packs[0].Path, "manifest.json")); err != nil {
+		t.Fatalf("detach must keep the library pack: %v", err)
+	}
+
+	if err := os.RemoveAll(packs[0].Path); err != nil {
+		t.Fatalf("remove library pack: %v", err)
+	}
+	links = ListLibraryLinks("A")
+	if len(links) != 1 || !links[0].Broken {
+		t.Fatalf("expected a broken link in A, got %+v", links)
+	}
+	if n := RemoveBrokenLibraryLinks(""); n != 1 {
+		t.Fatalf("expected 1 broken link removed, got %d", n)
+	}
+}
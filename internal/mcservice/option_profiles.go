@@ -0,0 +1,205 @@
+package mcservice
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/liteldev/LeviLauncher/internal/apppath"
+	"github.com/liteldev/LeviLauncher/internal/gameoptions"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+	"github.com/liteldev/LeviLauncher/internal/versions"
+)
+
+// Option profiles are named sets of options.txt values, such as every keybinding or the video settings,
+// kept in BaseRoot/option_profiles.json and applied to any player of any version. The default profile
+// is applied to the players of instances created with isolated data.
+
+var optionProfilesMu sync.Mutex
+
+func optionProfilesPath() string {
+	return filepath.Join(apppath.BaseRoot(), "option_profiles.json")
+}
+
+func ListOptionProfiles() types.OptionProfileList {
+	optionProfilesMu.Lock()
+	defer optionProfilesMu.Unlock()
+	list, _ := gameoptions.LoadProfiles(optionProfilesPath())
+	return list
+}
+
+func updateOptionProfiles(update func(list *types.OptionProfileList) string) string {
+	optionProfilesMu.Lock()
+	defer optionProfilesMu.Unlock()
+	list, err := gameoptions.LoadProfiles(optionProfilesPath())
+	if err != nil {
+		return "ERR_READ_FILE"
+	}
+	if code := update(&list); code != "" {
+		return code
+	}
+	if err := gameoptions.SaveProfiles(optionProfilesPath(), list); err != nil {
+		return "ERR_WRITE_FILE"
+	}
+	return ""
+}
+
+// CreateOptionProfile captures the options of a player that belong to req.Groups or match req.Keys into
+// a new profile.
+func CreateOptionProfile(versionName string, player string, req types.OptionProfileRequest) types.OptionProfileResult {
+	var res types.OptionProfileResult
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		res.ErrorCode = "ERR_INVALID_NAME"
+		return res
+	}
+	patterns := gameoptions.PatternsForGroups(req.Groups)
+	for _, k := range req.Keys {
+		if k = strings.TrimSpace(k); k != "" {
+			patterns = append(patterns, k)
+		}
+	}
+	path, code := gameOptionsPath(versionName, player)
+	if code != "" {
+		res.ErrorCode = code
+		return res
+	}
+	if !utils.FileExists(path) {
+		res.ErrorCode = "ERR_OPTIONS_NOT_FOUND"
+		return res
+	}
+	f, err := gameoptions.Read(path)
+	if err != nil {
+		res.ErrorCode = "ERR_READ_FILE"
+		return res
+	}
+	now := time.Now().Unix()
+	p := types.OptionProfile{ID: uuid.NewString(), Name: name, Patterns: patterns, Options: gameoptions.Capture(f, patterns), CreatedAt: now, UpdatedAt: now}
+	if len(p.Options) == 0 {
+		res.ErrorCode = "ERR_OPTION_PROFILE_EMPTY"
+		return res
+	}
+	res.ErrorCode = updateOptionProfiles(func(list *types.OptionProfileList) string {
+		list.Profiles = append(list.Profiles, p)
+		return ""
+	})
+	if res.ErrorCode == "" {
+		res.Profile = p
+	}
+	return res
+}
+
+func DeleteOptionProfile(id string) string {
+	return updateOptionProfiles(func(list *types.OptionProfileList) string {
+		for i, p := range list.Profiles {
+			if p.ID == id {
+				list.Profiles = append(list.Profiles[:i], list.Profiles[i+1:]...)
+				if list.DefaultID == id {
+					list.DefaultID = ""
+				}
+				return ""
+			}
+		}
+		return "ERR_OPTION_PROFILE_NOT_FOUND"
+	})
+}
+
+// SetDefaultOptionProfile sets the profile applied to new isolated instances; an empty id turns that
+// off.
+func SetDefaultOptionProfile(id string) string {
+	return updateOptionProfiles(func(list *types.OptionProfileList) string {
+		if id != "" {
+			if _, err := gameoptions.FindProfile(*list, id); err != nil {
+				return "ERR_OPTION_PROFILE_NOT_FOUND"
+			}
+		}
+		list.DefaultID = id
+		return ""
+	})
+}
+
+func defaultOptionProfileID() string {
+	return ListOptionProfiles().DefaultID
+}
+
+func ApplyOptionProfile(id string, versionName string, player string) string {
+	p, err := gameoptions.FindProfile(ListOptionProfiles(), id)
+	if err != nil {
+		return "ERR_OPTION_PROFILE_NOT_FOUND"
+	}
+	path, code := gameOptionsPath(versionName, player)
+	if code != "" {
+		return code
+	}
+	return editGameOptions(path, func(f *gameoptions.File) string {
+		if gameoptions.ApplyProfile(f, p) != nil {
+			return "ERR_INVALID_OPTION_VALUE"
+		}
+		return ""
+	})
+}
+
+// ApplyPendingOptionProfile applies the profile recorded for a new instance to each of its players. It
+// runs after CopyVersionDataFromVersion and CopyVersionDataFromGDK and before every launch. A freshly
+// created instance has no players until the game first runs, so its first session uses the game's own
+// defaults and the profile lands on the next launch. A version that lost isolation shares the system
+// game data, which the profile must not touch, so its pending profile is dropped. Every player is tried;
+// the profile stops being pending once it reached at least one of them, and the others are listed in
+// Failed.
+func ApplyPendingOptionProfile(name string) types.PendingOptionProfileResult {
+	res := types.PendingOptionProfileResult{VersionName: strings.TrimSpace(name), Applied: []string{}, Failed: []types.OptionProfilePlayerError{}}
+	vdir, err := apppath.VersionsDir()
+	if err != nil || strings.TrimSpace(vdir) == "" {
+		res.ErrorCode = "ERR_ACCESS_VERSIONS_DIR"
+		return res
+	}
+	dir := filepath.Join(vdir, strings.TrimSpace(name))
+	meta, err := versions.ReadMeta(dir)
+	if err != nil {
+		res.ErrorCode = "ERR_READ_VERSION_META"
+		return res
+	}
+	id := meta.PendingOptionProfile
+	if id == "" {
+		return res
+	}
+	res.ProfileID = id
+	roots := GetContentRoots(name)
+	if _, err := gameoptions.FindProfile(ListOptionProfiles(), id); err == nil && roots.IsIsolation {
+		players, _ := os.ReadDir(roots.UsersRoot)
+		for _, pl := range players {
+			if !pl.IsDir() || strings.EqualFold(pl.Name(), "Shared") {
+				continue
+			}
+			if code := ApplyOptionProfile(id, name, pl.Name()); code != "" {
+				res.Failed = append(res.Failed, types.OptionProfilePlayerError{Player: pl.Name(), ErrorCode: code})
+				continue
+			}
+			res.Applied = append(res.Applied, pl.Name())
+		}
+		if len(res.Applied) == 0 {
+			return res
+		}
+	}
+	meta.PendingOptionProfile = ""
+	if err := versions.WriteMeta(dir, meta); err != nil {
+		res.ErrorCode = "ERR_WRITE_VERSION_META"
+	}
+	return res
+}
+
+// LogPendingOptionProfile logs the players a pending option profile could not be applied to.
+func LogPendingOptionProfile(res types.PendingOptionProfileResult) {
+	if res.ErrorCode != "" {
+		log.Printf("Applying the pending option profile of %q failed: %s", res.VersionName, res.ErrorCode)
+	}
+	for _, f := range res.Failed {
+		log.Printf("Applying option profile %s to player %q of %q failed: %s", res.ProfileID, f.Player, res.VersionName, f.ErrorCode)
+	}
+}
@@ -0,0 +1,76 @@
+package mcservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/apppath"
+	"github.com/liteldev/LeviLauncher/internal/gameoptions"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/versions"
+)
+
+func writeTestOptions(t *testing.T, usersRoot string, player string, body string) string {
+	t.Helper()
+	path := filepath.Join(usersRoot, player, "games", "com.mojang", "minecraftpe", "options.txt")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestApplyPendingOptionProfile(t *testing.T) {
+	_, appDataDir, versionsDir := setupInstanceBackupEnv(t)
+	if err := gameoptions.SaveProfiles(filepath.Join(apppath.BaseRoot(), "option_profiles.json"), types.OptionProfileList{
+		Profiles: []types.OptionProfile{{ID: "p1", Name: "Quiet", Options: []types.GameOptionValue{{Key: "audio_main", Value: "0"}}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	isoDir := createTestInstance(t, versionsDir, "iso", versions.VersionMeta{EnableIsolation: true, PendingOptionProfile: "p1"})
+	if res := ApplyPendingOptionProfile("iso"); res.ErrorCode != "" || GetVersionMeta("iso").PendingOptionProfile != "p1" {
+		t.Fatalf("profile should stay pending without players: %+v %+v", res, GetVersionMeta("iso"))
+	}
+	gameData := createTestGameDataDir(t, isoDir, appDataDir, false, true)
+	path := writeTestOptions(t, filepath.Join(gameData, "Users"), "123", "audio_main:1\n")
+	// A player whose options.txt cannot be written must not stop the others.
+	if err := os.MkdirAll(filepath.Join(gameData, "Users", "000", "games", "com.mojang", "minecraftpe", "options.txt"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	res := ApplyPendingOptionProfile("iso")
+	if res.ErrorCode != "" || len(res.Applied) != 1 || res.Applied[0] != "123" || len(res.Failed) != 1 || res.Failed[0].Player != "000" {
+		t.Fatalf("apply: %+v", res)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "audio_main:0\n" {
+		t.Fatalf("options.txt = %q", b)
+	}
+	if GetVersionMeta("iso").PendingOptionProfile != "" {
+		t.Fatalf("pending profile was not cleared")
+	}
+}
+
+func TestApplyPendingOptionProfileSkipsSharedGameData(t *testing.T) {
+	_, appDataDir, versionsDir := setupInstanceBackupEnv(t)
+	if err := gameoptions.SaveProfiles(filepath.Join(apppath.BaseRoot(), "option_profiles.json"), types.OptionProfileList{
+		Profiles: []types.OptionProfile{{ID: "p1", Name: "Quiet", Options: []types.GameOptionValue{{Key: "audio_main", Value: "0"}}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	dir := createTestInstance(t, versionsDir, "plain", versions.VersionMeta{PendingOptionProfile: "p1"})
+	gameData := createTestGameDataDir(t, dir, appDataDir, false, false)
+	path := writeTestOptions(t, filepath.Join(gameData, "Users"), "123", "audio_main:1\n")
+
+	if res := ApplyPendingOptionProfile("plain"); res.ErrorCode != "" || len(res.Applied) != 0 {
+		t.Fatalf("apply: %+v", res)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "audio_main:1\n" {
+		t.Fatalf("shared options.txt was changed: %q", b)
+	}
+	if GetVersionMeta("plain").PendingOptionProfile != "" {
+		t.Fatalf("pending profile of a non-isolated version was kept")
+	}
+}
@@ -0,0 +1,485 @@
+package mcservice
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/liteldev/LeviLauncher/internal/dirlink"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+const (
+	PackDuplicateKindIdentity = "identity"
+	PackDuplicateKindContent  = "content"
+
+	PackConsolidateModeDelete   = "delete"
+	PackConsolidateModeHardlink = "hardlink"
+	PackConsolidateModeLink     = "link"
+)
+
+var packRootNames = []string{"resource_packs", "behavior_packs", "skin_packs"}
+
+type packLocation struct {
+	Label       string
+	VersionName string
+	Shared      string
+}
+
+type packDuplicateCandidate struct {
+	entry types.PackDuplicateEntry
+	files map[string]string
+	ids   map[string]os.FileInfo
+}
+
+// listPackLocations returns every com.mojang directory the launcher manages: the shared GDK data of
+// release and preview, plus the private data of each isolated version.
+func listPackLocations() []packLocation {
+	var out []packLocation
+	seen := map[string]struct{}{}
+	add := func(label string, versionName string, base string) {
+		base = strings.TrimSpace(base)
+		if base == "" {
+			return
+		}
+		shared := filepath.Join(base, "Users", "Shared", "games", "com.mojang")
+		if fi, err := os.Stat(shared); err != nil || !fi.IsDir() {
+			return
+		}
+		key := strings.ToLower(filepath.Clean(shared))
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		out = append(out, packLocation{Label: label, VersionName: versionName, Shared: shared})
+	}
+	add(instanceBackupGameDataLabel(false), "", utils.GetMinecraftGDKDataPath(false))
+	add(instanceBackupGameDataLabel(true), "", utils.GetMinecraftGDKDataPath(true))
+	for _, m := range ListVersionMetas() {
+		if !m.EnableIsolation {
+			continue
+		}
+		roots := GetContentRoots(m.Name)
+		if !roots.IsIsolation {
+			continue
+		}
+		add(m.Name, m.Name, roots.Base)
+	}
+	return out
+}
+
+// packDuplicateRoots lists the pack folders that duplicate scanning and consolidation may touch.
+func packDuplicateRoots() []string {
+	var out []string
+	for _, loc := range listPackLocations() {
+		for _, root := range packRootNames {
+			out = append(out, filepath.Join(loc.Shared, root))
+		}
+	}
+	return out
+}
+
+func FindDuplicatePacks() types.PackDuplicateReport {
+	report := types.PackDuplicateReport{Groups: []types.PackDuplicateGroup{}}
+	locations := listPackLocations()
+	report.ScannedLocations = len(locations)
+
+	var candidates []*packDuplicateCandidate
+	for _, loc := range locations {
+		for _, rootName := range packRootNames {
+			root := filepath.Join(loc.Shared, rootName)
+			ents, err := os.ReadDir(root)
+			if err != nil {
+				continue
+			}
+			for _, e := range ents {
+				dir := filepath.Join(root, e.Name())
+				c, ok := scanDuplicateCandidate(loc, rootName, dir)
+				if !ok {
+					continue
+				}
+				candidates = append(candidates, c)
+			}
+		}
+	}
+	report.ScannedPacks = len(candidates)
+
+	byIdentity := map[string][]*packDuplicateCandidate{}
+	byHash := map[string][]*packDuplicateCandidate{}
+	for _, c := range candidates {
+		id := strings.ToLower(c.entry.UUID) + "@" + c.entry.Version
+		byIdentity[id] = append(byIdentity[id], c)
+		if c.entry.ContentHash != "" {
+			byHash[c.entry.ContentHash] = append(byHash[c.entry.ContentHash], c)
+		}
+	}
+
+	grouped := map[*packDuplicateCandidate]struct{}{}
+	for id, list := range byIdentity {
+		if countRealPacks(list) < 2 {
+			continue
+		}
+		g := buildPackDuplicateGroup(PackDuplicateKindIdentity+":"+id, PackDuplicateKindIdentity, list)
+		report.Groups = append(report.Groups, g)
+		for _, c := range list {
+			grouped[c] = struct{}{}
+		}
+	}
+	// Content groups catch the same files installed under a different UUID or version, so packs that are
+	// already part of an identity group are left out.
+	for hash, list := range byHash {
+		var rest []*packDuplicateCandidate
+		for _, c := range list {
+			if _, ok := grouped[c]; !ok {
+				rest = append(rest, c)
+			}
+		}
+		if countRealPacks(rest) < 2 {
+			continue
+		}
+		report.Groups = append(report.Groups, buildPackDuplicateGroup(PackDuplicateKindContent+":"+hash, PackDuplicateKindContent, rest))
+	}
+
+	sort.SliceStable(report.Groups, func(i, j int) bool {
+		if report.Groups[i].WastedBytes != report.Groups[j].WastedBytes {
+			return report.Groups[i].WastedBytes > report.Groups[j].WastedBytes
+		}
+		return report.Groups[i].ID < report.Groups[j].ID
+	})
+	for _, g := range report.Groups {
+		report.WastedBytes += g.WastedBytes
+	}
+	return report
+}
+
+func scanDuplicateCandidate(loc packLocation, rootName string, dir string) (*packDuplicateCandidate, bool) {
+	fi, err := os.Stat(dir)
+	if err != nil || !fi.IsDir() {
+		return nil, false
+	}
+	meta, ok := readInstanceBackupPackMetadata(dir, rootName, filepath.Base(dir))
+	if !ok {
+		return nil, false
+	}
+	c := &packDuplicateCandidate{
+		entry: types.PackDuplicateEntry{
+			Path:        filepath.Clean(dir),
+			Location:    loc.Label,
+			VersionName: loc.VersionName,
+			RootName:    rootName,
+			FolderName:  meta.FolderName,
+			Name:        meta.Name,
+			UUID:        meta.UUID,
+			Version:     meta.Version,
+		},
+	}
+	hashDir := dir
+	if dirlink.IsLink(dir) {
+		c.entry.IsLink = true
+		c.entry.LinkTarget, _ = dirlink.Target(dir)
+		hashDir = c.entry.LinkTarget
+	}
+	hash, files, ids, size, err := hashPackDir(hashDir)
+	if err == nil {
+		c.entry.ContentHash = hash
+		c.files = files
+		c.ids = ids
+		if !c.entry.IsLink {
+			c.entry.Size = size
+		}
+	}
+	return c, true
+}
+
+// hashPackDir hashes a pack folder over its relative file paths and file contents, so two copies hash
+// equally regardless of where they are installed.
+func hashPackDir(dir string) (string, map[string]string, map[string]os.FileInfo, int64, error) {
+	files := map[string]string{}
+	ids := map[string]os.FileInfo{}
+	var rels []string
+	var total int64
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		fi, err := os.Stat(p)
+		if err != nil {
+			return err
+		}
+		sum, err := cachedFileHash(p, fi)
+		if err != nil {
+			return err
+		}
+		files[rel] = sum
+		ids[rel] = fi
+		total += fi.Size()
+		rels = append(rels, rel)
+		return nil
+	})
+	if err != nil {
+		return "", nil, nil, 0, err
+	}
+	sort.Strings(rels)
+	h := sha256.New()
+	for _, rel := range rels {
+		io.WriteString(h, strings.ToLower(rel))
+		h.Write([]byte{0})
+		io.WriteString(h, files[rel])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), files, ids, total, nil
+}
+
+type packFileHash struct {
+	size    int64
+	modTime int64
+	sum     string
+}
+
+var (
+	packFileHashMu    sync.Mutex
+	packFileHashCache = map[string]packFileHash{}
+)
+
+// cachedFileHash returns the hash of the file at p, reusing the hash of an earlier scan while the file
+// keeps its size and modification time.
+func cachedFileHash(p string, fi os.FileInfo) (string, error) {
+	key := strings.ToLower(filepath.Clean(p))
+	packFileHashMu.Lock()
+	c, ok := packFileHashCache[key]
+	packFileHashMu.Unlock()
+	if ok && c.size == fi.Size() && c.modTime == fi.ModTime().UnixNano() {
+		return c.sum, nil
+	}
+	sum, err := hashFile(p)
+	if err != nil {
+		return "", err
+	}
+	packFileHashMu.Lock()
+	packFileHashCache[key] = packFileHash{size: fi.Size(), modTime: fi.ModTime().UnixNano(), sum: sum}
+	packFileHashMu.Unlock()
+	return sum, nil
+}
+
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func countRealPacks(list []*packDuplicateCandidate) int {
+	n := 0
+	for _, c := range list {
+		if !c.entry.IsLink {
+			n++
+		}
+	}
+	return n
+}
+
+func buildPackDuplicateGroup(id string, kind string, list []*packDuplicateCandidate) types.PackDuplicateGroup {
+	sort.SliceStable(list, func(i, j int) bool {
+		return strings.ToLower(list[i].entry.Path) < strings.ToLower(list[j].entry.Path)
+	})
+	g := types.PackDuplicateGroup{ID: id, Kind: kind, SameContent: true, Entries: []types.PackDuplicateEntry{}}
+	first := list[0].entry
+	g.UUID = first.UUID
+	g.Version = first.Version
+	g.Name = first.Name
+	buckets := map[string][]*packDuplicateCandidate{}
+	for _, c := range list {
+		g.Entries = append(g.Entries, c.entry)
+		if c.entry.ContentHash == "" || c.entry.ContentHash != first.ContentHash {
+			g.SameContent = false
+		}
+		if !c.entry.IsLink && c.entry.ContentHash != "" {
+			buckets[c.entry.ContentHash] = append(buckets[c.entry.ContentHash], c)
+		}
+	}
+	for _, bucket := range buckets {
+		if len(bucket) < 2 {
+			continue
+		}
+		keep := bucket[0]
+		for _, c := range bucket[1:] {
+			g.WastedBytes += unsharedBytes(keep, c)
+		}
+	}
+	return g
+}
+
+// unsharedBytes counts the bytes of dup that would be freed by consolidating it onto keep. Files that
+// are already hardlinked to the kept copy do not take extra space.
+func unsharedBytes(keep *packDuplicateCandidate, dup *packDuplicateCandidate) int64 {
+	var n int64
+	for rel, fi := range dup.ids {
+		if k, ok := keep.ids[rel]; ok && os.SameFile(k, fi) {
+			continue
+		}
+		n += fi.Size()
+	}
+	return n
+}
+
+func isPackDuplicatePath(p string, roots []string) bool {
+	clean := filepath.Clean(strings.TrimSpace(p))
+	for _, root := range roots {
+		if sameInstanceBackupPath(filepath.Dir(clean), root) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConsolidateDuplicatePacks replaces every pack in request.Paths with request.KeepPath. Mode "delete"
+// removes the duplicates, "hardlink" points each file of a duplicate at the kept file and "link"
+// replaces the duplicate folder with a directory link to the kept pack. Link modes require identical
+// content.
+func ConsolidateDuplicatePacks(request types.PackDuplicateConsolidateRequest) types.PackDuplicateConsolidateResult {
+	result := types.PackDuplicateConsolidateResult{Items: []types.PackDuplicateConsolidateItem{}}
+	mode := strings.ToLower(strings.TrimSpace(request.Mode))
+	switch mode {
+	case PackConsolidateModeDelete, PackConsolidateModeHardlink, PackConsolidateModeLink:
+	default:
+		result.ErrorCode = "ERR_INVALID_MODE"
+		return result
+	}
+	roots := packDuplicateRoots()
+	keepPath := filepath.Clean(strings.TrimSpace(request.KeepPath))
+	if strings.TrimSpace(request.KeepPath) == "" || !isPackDuplicatePath(keepPath, roots) {
+		result.ErrorCode = "ERR_INVALID_PATH"
+		return result
+	}
+	if dirlink.IsLink(keepPath) {
+		result.ErrorCode = "ERR_KEEP_IS_LINK"
+		return result
+	}
+	keep, ok := scanDuplicateCandidate(packLocation{}, "", keepPath)
+	if !ok || keep.entry.ContentHash == "" {
+		result.ErrorCode = "ERR_NOT_FOUND"
+		return result
+	}
+
+	for _, raw := range request.Paths {
+		p := filepath.Clean(strings.TrimSpace(raw))
+		item := types.PackDuplicateConsolidateItem{Path: p}
+		switch {
+		case strings.TrimSpace(raw) == "" || !isPackDuplicatePath(p, roots):
+			item.ErrorCode = "ERR_INVALID_PATH"
+		case sameInstanceBackupPath(p, keepPath):
+			item.Status = "skipped"
+		default:
+			item.FreedBytes, item.ErrorCode = consolidateDuplicatePack(mode, keep, p)
+			if item.ErrorCode == "" {
+				item.Status = "done"
+			}
+		}
+		if item.ErrorCode != "" {
+			item.Status = "failed"
+		}
+		result.FreedBytes += item.FreedBytes
+		result.Items = append(result.Items, item)
+	}
+	return result
+}
+
+func consolidateDuplicatePack(mode string, keep *packDuplicateCandidate, p string) (int64, string) {
+	dup, ok := scanDuplicateCandidate(packLocation{}, "", p)
+	if !ok {
+		return 0, "ERR_NOT_FOUND"
+	}
+	sameContent := dup.entry.ContentHash != "" && dup.entry.ContentHash == keep.entry.ContentHash
+	// Delete mode also accepts a pack that differs in content but has the same UUID and version; a
+	// different version of the pack is never a duplicate.
+	sameIdentity := dup.entry.UUID != "" && strings.EqualFold(dup.entry.UUID, keep.entry.UUID) && dup.entry.Version == keep.entry.Version
+	if !sameContent && (mode != PackConsolidateModeDelete || !sameIdentity) {
+		return 0, "ERR_CONTENT_MISMATCH"
+	}
+	if dup.entry.IsLink {
+		if mode == PackConsolidateModeLink && dirlink.SameTarget(p, keep.entry.Path) {
+			return 0, ""
+		}
+		if mode == PackConsolidateModeHardlink {
+			return 0, "ERR_IS_LINK"
+		}
+	}
+	freed := unsharedBytes(keep, dup)
+	if dup.entry.IsLink {
+		freed = 0
+	}
+
+	switch mode {
+	case PackConsolidateModeDelete:
+		var err error
+		if dup.entry.IsLink {
+			err = dirlink.Remove(p)
+		} else {
+			err = os.RemoveAll(p)
+		}
+		if err != nil {
+			return 0, "ERR_DELETE"
+		}
+		if !sameContent {
+			freed = dup.entry.Size
+		}
+	case PackConsolidateModeHardlink:
+		for rel := range dup.files {
+			target := filepath.Join(p, filepath.FromSlash(rel))
+			source := filepath.Join(keep.entry.Path, filepath.FromSlash(rel))
+			if k, ok := keep.ids[rel]; ok && os.SameFile(k, dup.ids[rel]) {
+				continue
+			}
+			tmp := target + ".levi_link"
+			_ = os.Remove(tmp)
+			if err := os.Link(source, tmp); err != nil {
+				return 0, "ERR_HARDLINK"
+			}
+			if err := os.Rename(tmp, target); err != nil {
+				_ = os.Remove(tmp)
+				return 0, "ERR_HARDLINK"
+			}
+		}
+	case PackConsolidateModeLink:
+		old := p + ".levi_dedup"
+		_ = os.RemoveAll(old)
+		if dup.entry.IsLink {
+			if err := dirlink.Remove(p); err != nil {
+				return 0, "ERR_LINK"
+			}
+		} else if err := os.Rename(p, old); err != nil {
+			return 0, "ERR_LINK"
+		}
+		if err := dirlink.Create(p, keep.entry.Path); err != nil {
+			if !dup.entry.IsLink {
+				_ = os.Rename(old, p)
+			} else if dup.entry.LinkTarget != "" {
+				_ = dirlink.Create(p, dup.entry.LinkTarget)
+			}
+			return 0, "ERR_LINK"
+		}
+		_ = os.RemoveAll(old)
+	}
+	return freed, ""
+}
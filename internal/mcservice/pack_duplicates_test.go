@@ -0,0 +1,105 @@
+package mcservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/versions"
+)
+
+func writeDuplicateTestPack(t *testing.T, gameDataDir string, folder string, uuid string, body string) string {
+	t.Helper()
+	dir := filepath.Join(gameDataDir, "Users", "Shared", "games", "com.mojang", "resource_packs", folder)
+	if err := os.MkdirAll(filepath.Join(dir, "textures"), 0o755); err != nil {
+		t.Fatalf("mkdir pack: %v", err)
+	}
+	manifest := `{"format_version":2,"header":{"name":"Dup","uuid":"` + uuid + `","version":[1,0,0]},"modules":[{"type":"resources","uuid":"` + uuid + `","version":[1,0,0]}]}`
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(manifest), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "textures", "a.png"), []byte(body), 0o644); err != nil {
+		t.Fatalf("write texture: %v", err)
+	}
+	return dir
+}
+
+func TestFindDuplicatePacksAcrossIsolatedInstances(t *testing.T) {
+	_, _, versionsDir := setupInstanceBackupEnv(t)
+	const uuid = "0f8d6c4e-2b1a-4c3d-9e8f-1a2b3c4d5e6f"
+	var packs []string
+	for _, name := range []string{"A", "B", "C"} {
+		versionDir := createTestInstance(t, versionsDir, name, versions.VersionMeta{EnableIsolation: true})
+		gameDataDir := filepath.Join(versionDir, instanceBackupGameDataLabel(false))
+		packs = append(packs, writeDuplicateTestPack(t, gameDataDir, "dup", uuid, "texture-bytes"))
+	}
+
+	report := FindDuplicatePacks()
+	if len(report.Groups) != 1 {
+		t.Fatalf("expected 1 duplicate group, got %+v", report.Groups)
+	}
+	group := report.Groups[0]
+	if group.Kind != PackDuplicateKindIdentity || !group.SameContent || len(group.Entries) != 3 {
+		t.Fatalf("unexpected group: %+v", group)
+	}
+	if group.WastedBytes != 2*group.Entries[0].Size {
+		t.Fatalf("expected two copies of wasted space, got %d (pack size %d)", group.WastedBytes, group.Entries[0].Size)
+	}
+
+	result := ConsolidateDuplicatePacks(types.PackDuplicateConsolidateRequest{
+		KeepPath: packs[0],
+		Paths:    packs[1:],
+		Mode:     PackConsolidateModeHardlink,
+	})
+	if result.ErrorCode != "" || result.FreedBytes != group.WastedBytes {
+		t.Fatalf("unexpected consolidate result: %+v", result)
+	}
+	if again := FindDuplicatePacks(); again.WastedBytes != 0 {
+		t.Fatalf("expected no wasted space after hardlinking, got %d", again.WastedBytes)
+	}
+}
+
+func TestConsolidateDuplicatePacksRejectsDifferentContentForLinks(t *testing.T) {
+	_, _, versionsDir := setupInstanceBackupEnv(t)
+	const uuid = "0f8d6c4e-2b1a-4c3d-9e8f-1a2b3c4d5e6f"
+	a := createTestInstance(t, versionsDir, "A", versions.VersionMeta{EnableIsolation: true})
+	b := createTestInstance(t, versionsDir, "B", versions.VersionMeta{EnableIsolation: true})
+	keep := writeDuplicateTestPack(t, filepath.Join(a, instanceBackupGameDataLabel(false)), "dup", uuid, "one")
+	other := writeDuplicateTestPack(t, filepath.Join(b, instanceBackupGameDataLabel(false)), "dup", uuid, "two")
+
+	result := ConsolidateDuplicatePacks(types.PackDuplicateConsolidateRequest{KeepPath: keep, Paths: []string{other}, Mode: PackConsolidateModeLink})
+	if len(result.Items) != 1 || result.Items[0].ErrorCode != "ERR_CONTENT_MISMATCH" {
+		t.Fatalf("expected content mismatch, got %+v", result.Items)
+	}
+	if _, err := os.Stat(filepath.Join(other, "textures", "a.png")); err != nil {
+		t.Fatalf("duplicate must be left untouched: %v", err)
+	}
+
+	outside := t.TempDir()
+	result = ConsolidateDuplicatePacks(types.PackDuplicateConsolidateRequest{KeepPath: keep, Paths: []string{outside}, Mode: PackConsolidateModeDelete})
+	if len(result.Items) != 1 || result.Items[0].ErrorCode != "ERR_INVALID_PATH" {
+		t.Fatalf("expected invalid path for folder outside pack roots, got %+v", result.Items)
+	}
+}
+
+func TestConsolidateDuplicatePacksDeleteKeepsOtherVersions(t *testing.T) {
+	_, _, versionsDir := setupInstanceBackupEnv(t)
+	const uuid = "0f8d6c4e-2b1a-4c3d-9e8f-1a2b3c4d5e6f"
+	a := createTestInstance(t, versionsDir, "A", versions.VersionMeta{EnableIsolation: true})
+	b := createTestInstance(t, versionsDir, "B", versions.VersionMeta{EnableIsolation: true})
+	keep := writeDuplicateTestPack(t, filepath.Join(a, instanceBackupGameDataLabel(false)), "dup", uuid, "one")
+	newer := writeDuplicateTestPack(t, filepath.Join(b, instanceBackupGameDataLabel(false)), "dup", uuid, "two")
+	manifest := `{"format_version":2,"header":{"name":"Dup","uuid":"` + uuid + `","version":[1,1,0]},"modules":[{"type":"resources","uuid":"` + uuid + `","version":[1,1,0]}]}`
+	if err := os.WriteFile(filepath.Join(newer, "manifest.json"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := ConsolidateDuplicatePacks(types.PackDuplicateConsolidateRequest{KeepPath: keep, Paths: []string{newer}, Mode: PackConsolidateModeDelete})
+	if len(result.Items) != 1 || result.Items[0].ErrorCode != "ERR_CONTENT_MISMATCH" {
+		t.Fatalf("expected content mismatch for another version, got %+v", result.Items)
+	}
+	if _, err := os.Stat(filepath.Join(newer, "manifest.json")); err != nil {
+		t.Fatalf("other version must be kept: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package mcservice
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/liteldev/LeviLauncher/internal/apppath"
+	"github.com/liteldev/LeviLauncher/internal/content"
+	"github.com/liteldev/LeviLauncher/internal/scriptapi"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+func scriptModuleTablePath() string {
+	return filepath.Join(apppath.ConfigDir(), "script_modules.json")
+}
+
+// worldBetaAPIs reports whether the Beta APIs experiment is on in a decoded level.dat.
+func worldBetaAPIs(root map[string]any) bool {
+	data := root
+	if v, ok := root["Data"].(map[string]any); ok {
+		data = v
+	}
+	exp, _ := data["experiments"].(map[string]any)
+	on, _ := exp["gametest"].(byte)
+	return on != 0
+}
+
+// worldBehaviorPackIDs returns the lower-cased UUIDs listed in a world's world_behavior_packs.json.
+func worldBehaviorPackIDs(worldDir string) map[string]bool {
+	ids := map[string]bool{}
+	b, err := os.ReadFile(filepath.Join(worldDir, "world_behavior_packs.json"))
+	if err != nil {
+		return ids
+	}
+	var refs []struct {
+		PackID string `json:"pack_id"`
+	}
+	_ = json.Unmarshal(utils.JsonCompatBytes(b), &refs)
+	for _, r := range refs {
+		ids[strings.ToLower(strings.TrimSpace(r.PackID))] = true
+	}
+	return ids
+}
+
+// CheckScriptCompatibility checks the script modules declared by behavior packs against the game
+// version of versionName. Without a world it checks every behavior pack of the version and assumes Beta
+// APIs are available; with one it checks the packs the world uses, against the world's experiments.
+// Only packs that depend on script modules are reported.
+func CheckScriptCompatibility(versionName string, worldDir string) types.ScriptCompatReport {
+	res := types.ScriptCompatReport{WorldDir: strings.TrimSpace(worldDir), Packs: []types.ScriptPackCompat{}}
+	meta, err := readVersionMetaByName(versionName)
+	if err != nil {
+		res.ErrorCode = "ERR_READ_VERSION_META"
+		return res
+	}
+	res.GameVersion = strings.TrimSpace(meta.GameVersion)
+	if res.GameVersion == "" {
+		res.ErrorCode = "ERR_UNKNOWN_GAME_VERSION"
+		return res
+	}
+	table := scriptapi.Load(scriptModuleTablePath())
+	res.TableRevision, res.TableGame = table.Revision, table.Game
+
+	roots := GetContentRoots(versionName)
+	packRoots := []string{roots.BehaviorPacks, filepath.Join(filepath.Dir(roots.BehaviorPacks), "development_behavior_packs")}
+	var active map[string]bool
+	res.BetaAPIsEnabled = true
+	if res.WorldDir != "" {
+		root, _, err := content.DecodeLevelDat(res.WorldDir)
+		if err != nil {
+			res.ErrorCode = "ERR_READ_LEVEL_DAT"
+			return res
+		}
+		res.BetaAPIsEnabled = worldBetaAPIs(root)
+		active = worldBehaviorPackIDs(res.WorldDir)
+		packRoots = append([]string{filepath.Join(res.WorldDir, "behavior_packs")}, packRoots...)
+	}
+
+	seen := map[string]bool{}
+	for _, pr := range packRoots {
+		if strings.TrimSpace(pr) == "" || pr == "." {
+			continue
+		}
+		ents, err := os.ReadDir(pr)
+		if err != nil {
+			continue
+		}
+		for _, e := range ents {
+			dir := filepath.Join(pr, e.Name())
+			meta, ok := readInstanceBackupPackMetadata(dir, "behavior_packs", e.Name())
+			if !ok {
+				continue
+			}
+			id := strings.ToLower(meta.UUID)
+			if seen[id] || (active != nil && !active[id]) {
+				continue
+			}
+			b, err := os.ReadFile(filepath.Join(findInstanceBackupPackManifestDir(dir), "manifest.json"))
+			if err != nil {
+				continue
+			}
+			issues, modules, err := table.CheckManifest(utils.JsonCompatBytes(b), res.GameVersion, res.BetaAPIsEnabled)
+			if err != nil || modules == 0 {
+				continue
+			}
+			seen[id] = true
+			p := types.ScriptPackCompat{Path: dir, Name: meta.Name, UUID: meta.UUID, Version: meta.Version, Compatible: true, Issues: issues}
+			for _, is := range issues {
+				if is.Severity == scriptapi.SeverityError {
+					p.Compatible = false
+				}
+			}
+			if !p.Compatible {
+				res.Incompatible++
+			}
+			res.Packs = append(res.Packs, p)
+		}
+	}
+	return res
+}
+
+// InstallScriptModuleTable installs a script module table file. It is used instead of the bundled table
+// while its revision is higher.
+func InstallScriptModuleTable(path string) string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "ERR_READ_FILE"
+	}
+	if _, err := scriptapi.ParseTable(b); err != nil {
+		return "ERR_INVALID_TABLE"
+	}
+	dest := scriptModuleTablePath()
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return "ERR_CREATE_TARGET_DIR"
+	}
+	if err := os.WriteFile(dest, b, 0o644); err != nil {
+		return "ERR_WRITE_FILE"
+	}
+	return ""
+}
@@ -0,0 +1,182 @@
+package mcservice
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/structure"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+	"github.com/liteldev/LeviLauncher/internal/worlddb"
+)
+
+// ListWorldStructures lists the structures of a world: files in its structures folder and the
+// structures saved in its database with a structure block.
+func ListWorldStructures(worldDir string) []types.StructureEntry {
+	out := structure.List(filepath.Join(worldDir, "structures"))
+	w, code := openWorldDB(worldDir)
+	if code != "" {
+		return out
+	}
+	defer w.Close()
+	_ = w.ForEachKind(worlddb.KindStructureTemplate, func(key []byte, _ worlddb.KeyInfo, value []byte) error {
+		out = append(out, types.StructureEntry{
+			Identifier: strings.TrimPrefix(string(key), structure.DBKeyPrefix),
+			Source:     "db",
+			Size:       int64(len(value)),
+		})
+		return nil
+	})
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Identifier < out[j].Identifier })
+	return out
+}
+
+// ListPackStructures lists the structure files of a behavior pack.
+func ListPackStructures(packDir string) []types.StructureEntry {
+	if strings.TrimSpace(packDir) == "" {
+		return []types.StructureEntry{}
+	}
+	return structure.List(filepath.Join(packDir, "structures"))
+}
+
+func inspectStructureData(data []byte, id string) types.StructureInfo {
+	info, err := structure.Decode(data)
+	info.Identifier = id
+	if err != nil {
+		info.ErrorCode = "ERR_INVALID_STRUCTURE"
+	}
+	return info
+}
+
+// InspectStructure decodes a .mcstructure file.
+func InspectStructure(path string) types.StructureInfo {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return types.StructureInfo{ErrorCode: "ERR_READ_FILE"}
+	}
+	return inspectStructureData(b, structure.IdentifierOf(filepath.Base(path)))
+}
+
+// InspectWorldStructure decodes a structure saved in a world database.
+func InspectWorldStructure(worldDir string, id string) types.StructureInfo {
+	w, code := openWorldDB(worldDir)
+	if code != "" {
+		return types.StructureInfo{Identifier: id, ErrorCode: code}
+	}
+	defer w.Close()
+	v, err := w.Get([]byte(structure.DBKeyPrefix + id))
+	if err != nil {
+		return types.StructureInfo{Identifier: id, ErrorCode: "ERR_NOT_FOUND"}
+	}
+	return inspectStructureData(v, id)
+}
+
+// structureIDFromFile derives an identifier in the default namespace from a file name, replacing the
+// characters identifiers do not allow.
+func structureIDFromFile(path string) string {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+			return r
+		}
+		return '_'
+	}, base)
+	return structure.DefaultNamespace + ":" + strings.Trim(name, ".")
+}
+
+// ImportStructure copies a .mcstructure file into the structures folder of a behavior pack under id,
+// or under its file name in the default namespace when id is empty. The file is decoded first so a
+// broken structure never reaches the pack.
+func ImportStructure(packDir string, srcPath string, id string, overwrite bool) types.StructureImportResult {
+	var res types.StructureImportResult
+	if strings.TrimSpace(packDir) == "" || !utils.FileExists(filepath.Join(packDir, "manifest.json")) {
+		res.ErrorCode = "ERR_INVALID_PACK"
+		return res
+	}
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		res.ErrorCode = "ERR_READ_FILE"
+		return res
+	}
+	if _, err := structure.Decode(data); err != nil {
+		res.ErrorCode = "ERR_INVALID_STRUCTURE"
+		return res
+	}
+	if strings.TrimSpace(id) == "" {
+		id = structureIDFromFile(srcPath)
+	}
+	rel, ok := structure.RelPath(id)
+	if !ok {
+		res.ErrorCode = "ERR_INVALID_IDENTIFIER"
+		return res
+	}
+	dest := filepath.Join(packDir, "structures", rel)
+	if utils.FileExists(dest) && !overwrite {
+		res.ErrorCode = "ERR_TARGET_EXISTS"
+		return res
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		res.ErrorCode = "ERR_CREATE_TARGET_DIR"
+		return res
+	}
+	if err := os.WriteFile(dest, data, 0o644); err != nil {
+		res.ErrorCode = "ERR_WRITE_FILE"
+		return res
+	}
+	res.Identifier, res.Path = id, dest
+	return res
+}
+
+// ExportWorldStructures writes structures saved in a world database to destDir as .mcstructure files,
+// laid out like a structures folder. An empty ids exports every saved structure; existing files are kept
+// and the export gets a numbered name instead.
+func ExportWorldStructures(worldDir string, ids []string, destDir string) types.StructureExportResult {
+	res := types.StructureExportResult{Paths: []string{}}
+	if strings.TrimSpace(destDir) == "" {
+		res.ErrorCode = "ERR_TARGET_DIR_NOT_SPECIFIED"
+		return res
+	}
+	w, code := openWorldDB(worldDir)
+	if code != "" {
+		res.ErrorCode = code
+		return res
+	}
+	defer w.Close()
+	filter := len(ids) > 0
+	wanted := map[string]bool{}
+	for _, id := range ids {
+		wanted[strings.TrimSpace(id)] = true
+	}
+	err := w.ForEachKind(worlddb.KindStructureTemplate, func(key []byte, _ worlddb.KeyInfo, value []byte) error {
+		id := strings.TrimPrefix(string(key), structure.DBKeyPrefix)
+		if filter && !wanted[id] {
+			return nil
+		}
+		delete(wanted, id)
+		rel, ok := structure.RelPath(id)
+		if !ok {
+			rel = filepath.Base(utils.SanitizeFilename(id)) + structure.Ext
+		}
+		dest := filepath.Join(destDir, rel)
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return err
+		}
+		dest = filepath.Join(filepath.Dir(dest), uniqueEntryName(filepath.Dir(dest), filepath.Base(dest), false))
+		if err := os.WriteFile(dest, value, 0o644); err != nil {
+			return err
+		}
+		res.Paths = append(res.Paths, dest)
+		return nil
+	})
+	if err != nil {
+		res.ErrorCode = "ERR_WRITE_FILE"
+		return res
+	}
+	if filter && len(wanted) > 0 {
+		res.ErrorCode = "ERR_NOT_FOUND"
+	}
+	return res
+}
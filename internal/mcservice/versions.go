@@ -252,7 +252,7 @@ func SaveVersionMeta(name string, gameVersion string, typeStr string, enableIsol
 	}
 
 	// Try read old
-	oldMeta, _ := versions.ReadMeta(dir)
+	oldMeta, oldErr := versions.ReadMeta(dir)
 
 	meta := versions.VersionMeta{
 		Name:                       n,
@@ -273,6 +273,12 @@ func SaveVersionMeta(name string, gameVersion string, typeStr string, enableIsol
 	if oldMeta.Registered {
 		meta.Registered = true
 	}
+	meta.AutoBackup = oldMeta.AutoBackup
+	meta.PinnedWorld = oldMeta.PinnedWorld
+	meta.PendingOptionProfile = oldMeta.PendingOptionProfile
+	if oldErr != nil && enableIsolation {
+		meta.PendingOptionProfile = defaultOptionProfileID()
+	}
 
 	if _, err := peeditor.PrepareExecutableForLaunch(context.Background(), dir, enableConsole); err != nil {
 		return "ERR_PREPARE_EXE"
@@ -428,6 +434,7 @@ func CopyVersionDataFromVersion(sourceName string, targetName string) string {
 	if err := utils.CopyDir(srcBase, dstBase); err != nil {
 		return "ERR_INHERIT_COPY_FAILED"
 	}
+	LogPendingOptionProfile(ApplyPendingOptionProfile(t))
 	return ""
 }
 
@@ -464,6 +471,7 @@ func CopyVersionDataFromGDK(isPreview bool, targetName string) string {
 	if err := utils.CopyDir(srcBase, dstBase); err != nil {
 		return "ERR_INHERIT_COPY_FAILED"
 	}
+	LogPendingOptionProfile(ApplyPendingOptionProfile(t))
 	return ""
 }
 
@@ -0,0 +1,280 @@
+package mcservice
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/liteldev/LeviLauncher/internal/apppath"
+	"github.com/liteldev/LeviLauncher/internal/content"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+// worldBackupTimeLayout is the timestamp BackupWorld and BackupWorldWithVersion put in archive names.
+const worldBackupTimeLayout = "20060102-150405"
+
+func worldBackupsRoot() string {
+	return filepath.Join(apppath.BaseRoot(), "backups", "worlds")
+}
+
+// parseWorldBackupPath describes the archive at rel, a slash path below the backups root. BackupWorld
+// writes <level>/<level>_<ts>.mcworld and BackupWorldWithVersion writes
+// <version>/<folder>_<level>/<level>_<ts>.mcworld; anything else is not a world backup.
+func parseWorldBackupPath(rel string) (types.WorldBackupFile, bool) {
+	var f types.WorldBackupFile
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	name := parts[len(parts)-1]
+	if !strings.EqualFold(filepath.Ext(name), ".mcworld") {
+		return f, false
+	}
+	switch len(parts) {
+	case 2:
+		f.WorldKey = parts[0]
+	case 3:
+		f.VersionName, f.WorldKey = parts[0], parts[1]
+	default:
+		return f, false
+	}
+	f.FileName = name
+	stem := strings.TrimSuffix(name, filepath.Ext(name))
+	level := stem
+	if i := strings.LastIndexByte(stem, '_'); i > 0 {
+		if ts, err := time.ParseInLocation(worldBackupTimeLayout, stem[i+1:], time.Local); err == nil {
+			f.CreatedAt = ts.Unix()
+			level = stem[:i]
+		}
+	}
+	f.FolderName = f.WorldKey
+	if f.VersionName != "" {
+		if folder, ok := strings.CutSuffix(f.WorldKey, "_"+level); ok && folder != "" {
+			f.FolderName = folder
+		}
+	}
+	f.LevelName = level
+	return f, true
+}
+
+// ListWorldBackups lists the .mcworld backups under BaseRoot/backups/worlds grouped by version and
+// world. Level names and last played times come from the archives; groups are sorted by version and
+// world and their backups newest first.
+func ListWorldBackups() []types.WorldBackupGroup {
+	root := worldBackupsRoot()
+	byKey := map[string]*types.WorldBackupGroup{}
+	_ = filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		f, ok := parseWorldBackupPath(rel)
+		if !ok {
+			return nil
+		}
+		f.Path = p
+		if fi, err := d.Info(); err == nil {
+			f.Size = fi.Size()
+			if f.CreatedAt == 0 {
+				f.CreatedAt = fi.ModTime().Unix()
+			}
+		}
+		if info, err := content.ReadMcworldInfo(p); err == nil {
+			f.Readable = true
+			if info.LevelName != "" {
+				f.LevelName = info.LevelName
+			}
+			data := info.LevelDat
+			if v, ok := data["Data"].(map[string]any); ok {
+				data = v
+			}
+			if lp, ok := data["LastPlayed"].(int64); ok {
+				f.LastPlayed = lp
+			}
+		}
+		key := f.VersionName + "/" + f.WorldKey
+		g := byKey[key]
+		if g == nil {
+			g = &types.WorldBackupGroup{VersionName: f.VersionName, WorldKey: f.WorldKey, Backups: []types.WorldBackupFile{}}
+			byKey[key] = g
+		}
+		g.Backups = append(g.Backups, f)
+		g.TotalSize += f.Size
+		return nil
+	})
+	out := make([]types.WorldBackupGroup, 0, len(byKey))
+	for _, g := range byKey {
+		sort.SliceStable(g.Backups, func(i, j int) bool { return g.Backups[i].CreatedAt > g.Backups[j].CreatedAt })
+		g.LevelName = g.Backups[0].LevelName
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].VersionName != out[j].VersionName {
+			return out[i].VersionName < out[j].VersionName
+		}
+		return out[i].WorldKey < out[j].WorldKey
+	})
+	return out
+}
+
+// resolveWorldBackupPath checks that p is a world backup below the backups root and describes it.
+func resolveWorldBackupPath(p string) (types.WorldBackupFile, string) {
+	root := worldBackupsRoot()
+	clean := filepath.Clean(strings.TrimSpace(p))
+	rel, err := filepath.Rel(root, clean)
+	if strings.TrimSpace(p) == "" || err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return types.WorldBackupFile{}, "ERR_INVALID_PATH"
+	}
+	f, ok := parseWorldBackupPath(rel)
+	if !ok {
+		return f, "ERR_INVALID_PATH"
+	}
+	if !utils.FileExists(clean) {
+		return f, "ERR_NOT_FOUND"
+	}
+	f.Path = clean
+	return f, ""
+}
+
+// InspectWorldBackup reads the level.dat of a world backup without extracting the archive.
+func InspectWorldBackup(path string) types.WorldBackupLevelDat {
+	res := types.WorldBackupLevelDat{Path: path, Fields: []types.LevelDatField{}}
+	f, code := resolveWorldBackupPath(path)
+	if code != "" {
+		res.ErrorCode = code
+		return res
+	}
+	info, err := content.ReadMcworldInfo(f.Path)
+	if err != nil {
+		res.ErrorCode = "ERR_READ_LEVEL_DAT"
+		return res
+	}
+	res.LevelName = info.LevelName
+	res.Version = info.LevelDatVersion
+	res.Fields = content.LevelDatFieldsOf(info.LevelDat)
+	return res
+}
+
+// RestoreWorldBackup extracts a world backup into the minecraftWorlds folder of a version's player. The
+// world keeps its original folder name unless one is given; a taken name gets a numbered suffix.
+func RestoreWorldBackup(req types.WorldBackupRestoreRequest) types.WorldBackupRestoreResult {
+	var res types.WorldBackupRestoreResult
+	f, code := resolveWorldBackupPath(req.Path)
+	if code != "" {
+		res.ErrorCode = code
+		return res
+	}
+	users := strings.TrimSpace(GetContentRoots(req.VersionName).UsersRoot)
+	player := strings.TrimSpace(req.Player)
+	if users == "" || player == "" {
+		res.ErrorCode = "ERR_ACCESS_VERSIONS_DIR"
+		return res
+	}
+	worldsDir := filepath.Join(users, player, "games", "com.mojang", "minecraftWorlds")
+	if err := utils.CreateDir(worldsDir); err != nil {
+		res.ErrorCode = "ERR_CREATE_TARGET_DIR"
+		return res
+	}
+	desired := strings.TrimSpace(req.FolderName)
+	if desired == "" {
+		desired = f.FolderName
+	}
+	desired = utils.SanitizeFilename(desired)
+	res.FolderName = uniqueEntryName(worldsDir, desired, true)
+	res.Renamed = res.FolderName != desired
+
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		res.ErrorCode = "ERR_OPEN_ZIP"
+		return res
+	}
+	staging := filepath.Join(worldsDir, ".restore_"+time.Now().Format(worldBackupTimeLayout))
+	defer os.RemoveAll(staging)
+	if code := content.ImportMcworldToDir(data, f.FileName, staging, false); code != "" {
+		res.ErrorCode = code
+		return res
+	}
+	ents, err := os.ReadDir(staging)
+	if err != nil || len(ents) != 1 || !ents[0].IsDir() {
+		res.ErrorCode = "ERR_INVALID_PACKAGE"
+		return res
+	}
+	dest := filepath.Join(worldsDir, res.FolderName)
+	if err := os.Rename(filepath.Join(staging, ents[0].Name()), dest); err != nil {
+		res.ErrorCode = "ERR_WRITE_FILE"
+		return res
+	}
+	res.WorldDir = dest
+	return res
+}
+
+// DeleteWorldBackup removes one world backup, and the folders it leaves empty.
+func DeleteWorldBackup(path string) string {
+	f, code := resolveWorldBackupPath(path)
+	if code != "" {
+		return code
+	}
+	if err := os.Remove(f.Path); err != nil {
+		return "ERR_DELETE_FAILED"
+	}
+	removeEmptyBackupDirs(filepath.Dir(f.Path))
+	return ""
+}
+
+func removeEmptyBackupDirs(dir string) {
+	root := filepath.Clean(worldBackupsRoot())
+	for dir != root && strings.HasPrefix(dir, root+string(os.PathSeparator)) {
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// selectWorldBackupsToPrune picks the backups that a prune request removes from groups. A group keeps
+// its KeepLast newest backups when KeepLast is set, and drops backups older than MaxAgeDays when that
+// is set, but age alone never removes the newest backup of a world.
+func selectWorldBackupsToPrune(groups []types.WorldBackupGroup, req types.WorldBackupPruneRequest, now time.Time) []types.WorldBackupFile {
+	cutoff := now.AddDate(0, 0, -req.MaxAgeDays).Unix()
+	var out []types.WorldBackupFile
+	for _, g := range groups {
+		if req.VersionName != "" && g.VersionName != req.VersionName {
+			continue
+		}
+		if req.WorldKey != "" && g.WorldKey != req.WorldKey {
+			continue
+		}
+		for i, b := range g.Backups {
+			tooMany := req.KeepLast > 0 && i >= req.KeepLast
+			tooOld := req.MaxAgeDays > 0 && i > 0 && b.CreatedAt < cutoff
+			if tooMany || tooOld {
+				out = append(out, b)
+			}
+		}
+	}
+	return out
+}
+
+// PruneWorldBackups deletes world backups by age and count, optionally limited to one version or world.
+// A dry run only reports what would be removed.
+func PruneWorldBackups(req types.WorldBackupPruneRequest) types.WorldBackupPruneResult {
+	res := types.WorldBackupPruneResult{Removed: []string{}}
+	if req.KeepLast < 0 || req.MaxAgeDays < 0 || (req.KeepLast == 0 && req.MaxAgeDays == 0) {
+		res.ErrorCode = "ERR_INVALID_RETENTION"
+		return res
+	}
+	for _, b := range selectWorldBackupsToPrune(ListWorldBackups(), req, time.Now()) {
+		if !req.DryRun {
+			if err := os.Remove(b.Path); err != nil {
+				continue
+			}
+			removeEmptyBackupDirs(filepath.Dir(b.Path))
+		}
+		res.Removed = append(res.Removed, b.Path)
+		res.FreedBytes += b.Size
+	}
+	return res
+}
@@ -0,0 +1,61 @@
+package mcservice
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+func TestParseWorldBackupPath(t *testing.T) {
+	f, ok := parseWorldBackupPath(filepath.Join("1.21.50", "abcd=_My World", "My World_20250102-030405.mcworld"))
+	if !ok || f.VersionName != "1.21.50" || f.WorldKey != "abcd=_My World" || f.FolderName != "abcd=" || f.LevelName != "My World" {
+		t.Fatalf("versioned backup = %+v, %v", f, ok)
+	}
+	if want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.Local).Unix(); f.CreatedAt != want {
+		t.Fatalf("CreatedAt = %d, want %d", f.CreatedAt, want)
+	}
+	f, ok = parseWorldBackupPath(filepath.Join("Old", "Old_20240101-000000.mcworld"))
+	if !ok || f.VersionName != "" || f.WorldKey != "Old" || f.FolderName != "Old" {
+		t.Fatalf("legacy backup = %+v, %v", f, ok)
+	}
+	for _, rel := range []string{"loose.mcworld", filepath.Join("a", "b", "notes.txt"), filepath.Join("a", "b", "c", "d.mcworld")} {
+		if _, ok := parseWorldBackupPath(rel); ok {
+			t.Fatalf("%s parsed as a world backup", rel)
+		}
+	}
+}
+
+func TestSelectWorldBackupsToPrune(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := func(n int) int64 { return now.AddDate(0, 0, -n).Unix() }
+	groups := []types.WorldBackupGroup{
+		{VersionName: "v1", WorldKey: "a", Backups: []types.WorldBackupFile{
+			{Path: "a0", CreatedAt: day(1)}, {Path: "a1", CreatedAt: day(5)}, {Path: "a2", CreatedAt: day(40)},
+		}},
+		{VersionName: "v2", WorldKey: "b", Backups: []types.WorldBackupFile{
+			{Path: "b0", CreatedAt: day(90)},
+		}},
+	}
+	paths := func(list []types.WorldBackupFile) []string {
+		out := []string{}
+		for _, b := range list {
+			out = append(out, b.Path)
+		}
+		return out
+	}
+
+	got := paths(selectWorldBackupsToPrune(groups, types.WorldBackupPruneRequest{MaxAgeDays: 30}, now))
+	if len(got) != 1 || got[0] != "a2" {
+		t.Fatalf("age prune = %v, want [a2]", got)
+	}
+	got = paths(selectWorldBackupsToPrune(groups, types.WorldBackupPruneRequest{KeepLast: 1}, now))
+	if len(got) != 2 || got[0] != "a1" || got[1] != "a2" {
+		t.Fatalf("count prune = %v, want [a1 a2]", got)
+	}
+	got = paths(selectWorldBackupsToPrune(groups, types.WorldBackupPruneRequest{VersionName: "v2", KeepLast: 1, MaxAgeDays: 1}, now))
+	if len(got) != 0 {
+		t.Fatalf("filtered prune = %v, want none", got)
+	}
+}
@@ -0,0 +1,190 @@
+package mcservice
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/liteldev/LeviLauncher/internal/apppath"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+	"github.com/liteldev/LeviLauncher/internal/worldbackup"
+)
+
+func worldBackupStore() *worldbackup.Store {
+	return worldbackup.New(filepath.Join(apppath.BaseRoot(), "backups", "store"))
+}
+
+// WorldBackupID names the snapshots of a world the same way BackupWorldWithVersion names its folders:
+// by version and world folder.
+func WorldBackupID(worldDir string, versionName string) string {
+	safeVersion := utils.SanitizeFilename(strings.TrimSpace(versionName))
+	if strings.TrimSpace(versionName) == "" {
+		safeVersion = "default"
+	}
+	return safeVersion + "__" + utils.SanitizeFilename(utils.GetLastDirName(worldDir))
+}
+
+func toWorldSnapshot(s worldbackup.Snapshot) types.WorldSnapshot {
+	return types.WorldSnapshot{
+		ID:         s.ID,
+		WorldID:    s.WorldID,
+		WorldName:  s.WorldName,
+		SourcePath: s.SourcePath,
+		Label:      s.Label,
+		CreatedAt:  s.CreatedAt,
+		Files:      len(s.Files),
+		Size:       s.Size,
+		NewBytes:   s.NewBytes,
+	}
+}
+
+func worldBackupErrorCode(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, worldbackup.ErrNotFound):
+		return "ERR_NOT_FOUND"
+	case errors.Is(err, worldbackup.ErrInvalidID):
+		return "ERR_INVALID_ID"
+	case errors.Is(err, worldbackup.ErrDestExists):
+		return "ERR_TARGET_EXISTS"
+	case errors.Is(err, worldbackup.ErrCorruptSnap):
+		return "ERR_SNAPSHOT_CORRUPT"
+	}
+	return "ERR_WRITE_FILE"
+}
+
+// SnapshotWorld adds an incremental snapshot of a world to the backup store and then applies the
+// world's retention policy. A world that has not changed since its latest snapshot is not stored again.
+func SnapshotWorld(worldDir string, versionName string, label string) types.WorldSnapshotResult {
+	res := types.WorldSnapshotResult{Pruned: []string{}}
+	if strings.TrimSpace(worldDir) == "" || !utils.DirExists(worldDir) {
+		res.ErrorCode = "ERR_INVALID_WORLD_DIR"
+		return res
+	}
+	level := GetWorldLevelName(worldDir)
+	if level == "" {
+		level = utils.GetLastDirName(worldDir)
+	}
+	store := worldBackupStore()
+	worldID := WorldBackupID(worldDir, versionName)
+	snap, unchanged, err := store.Snapshot(worldDir, worldID, level, strings.TrimSpace(label))
+	if err != nil {
+		res.ErrorCode = worldBackupErrorCode(err)
+		return res
+	}
+	res.Snapshot = toWorldSnapshot(snap)
+	res.Unchanged = unchanged
+	if unchanged {
+		return res
+	}
+	if pruned, err := store.Prune(worldID, store.Retention(worldID), time.Now()); err == nil && len(pruned) > 0 {
+		res.Pruned = pruned
+		_, res.FreedBytes, _ = store.GC()
+	}
+	return res
+}
+
+// ListWorldSnapshots lists the snapshots of one world, or of every world when worldID is empty, newest
+// first.
+func ListWorldSnapshots(worldID string) []types.WorldSnapshot {
+	out := []types.WorldSnapshot{}
+	store := worldBackupStore()
+	ids := []string{strings.TrimSpace(worldID)}
+	if ids[0] == "" {
+		var err error
+		if ids, err = store.Worlds(); err != nil {
+			return out
+		}
+	}
+	for _, id := range ids {
+		list, err := store.List(id)
+		if err != nil {
+			continue
+		}
+		for _, s := range list {
+			out = append(out, toWorldSnapshot(s))
+		}
+	}
+	return out
+}
+
+// RestoreWorldSnapshot restores a snapshot into a new world folder next to the world it was taken from,
+// or inside targetWorldsDir when given, and returns the new folder.
+func RestoreWorldSnapshot(worldID string, snapshotID string, targetWorldsDir string) types.WorldSnapshotPathResult {
+	store := worldBackupStore()
+	snap, err := store.Get(worldID, snapshotID)
+	if err != nil {
+		return types.WorldSnapshotPathResult{ErrorCode: worldBackupErrorCode(err)}
+	}
+	parent := strings.TrimSpace(targetWorldsDir)
+	if parent == "" {
+		parent = filepath.Dir(snap.SourcePath)
+	}
+	if err := utils.CreateDir(parent); err != nil {
+		return types.WorldSnapshotPathResult{ErrorCode: "ERR_CREATE_TARGET_DIR"}
+	}
+	name := fmt.Sprintf("%s_restored_%s", utils.GetLastDirName(snap.SourcePath), snap.ID)
+	dest := filepath.Join(parent, uniqueEntryName(parent, utils.SanitizeFilename(name), true))
+	if err := store.Restore(worldID, snapshotID, dest); err != nil {
+		return types.WorldSnapshotPathResult{ErrorCode: worldBackupErrorCode(err)}
+	}
+	return types.WorldSnapshotPathResult{Path: dest}
+}
+
+// ExportWorldSnapshot writes a snapshot as a .mcworld file. Without destPath it goes next to the archives
+// BackupWorld creates.
+func ExportWorldSnapshot(worldID string, snapshotID string, destPath string) types.WorldSnapshotPathResult {
+	store := worldBackupStore()
+	snap, err := store.Get(worldID, snapshotID)
+	if err != nil {
+		return types.WorldSnapshotPathResult{ErrorCode: worldBackupErrorCode(err)}
+	}
+	dest := strings.TrimSpace(destPath)
+	if dest == "" {
+		safe := utils.SanitizeFilename(snap.WorldName)
+		dest = filepath.Join(apppath.BaseRoot(), "backups", "worlds", safe, fmt.Sprintf("%s_%s.mcworld", safe, snap.ID))
+	}
+	if err := store.Export(worldID, snapshotID, dest); err != nil {
+		return types.WorldSnapshotPathResult{ErrorCode: worldBackupErrorCode(err)}
+	}
+	return types.WorldSnapshotPathResult{Path: dest}
+}
+
+// DeleteWorldSnapshot removes one snapshot and releases the objects only it used.
+func DeleteWorldSnapshot(worldID string, snapshotID string) string {
+	store := worldBackupStore()
+	if err := store.Delete(worldID, snapshotID); err != nil {
+		return worldBackupErrorCode(err)
+	}
+	if _, _, err := store.GC(); err != nil {
+		return "ERR_WRITE_FILE"
+	}
+	return ""
+}
+
+func GetWorldBackupRetention(worldID string) types.WorldBackupRetention {
+	return types.WorldBackupRetention(worldBackupStore().Retention(worldID))
+}
+
+// SetWorldBackupRetention stores the retention policy of a world and applies it right away.
+func SetWorldBackupRetention(worldID string, retention types.WorldBackupRetention) string {
+	if retention.KeepLast < 0 || retention.KeepDaily < 0 || retention.KeepWeekly < 0 {
+		return "ERR_INVALID_RETENTION"
+	}
+	store := worldBackupStore()
+	r := worldbackup.Retention(retention)
+	if err := store.SetRetention(worldID, r); err != nil {
+		return worldBackupErrorCode(err)
+	}
+	if _, err := store.Prune(worldID, r, time.Now()); err != nil {
+		return worldBackupErrorCode(err)
+	}
+	if _, _, err := store.GC(); err != nil {
+		return "ERR_WRITE_FILE"
+	}
+	return ""
+}
@@ -0,0 +1,160 @@
+package mcservice
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/liteldev/LeviLauncher/internal/apppath"
+	"github.com/liteldev/LeviLauncher/internal/content"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+	"github.com/liteldev/LeviLauncher/internal/versions"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+var (
+	launchPromptMu sync.Mutex
+	launchPrompt   *types.WorldCompatLaunchPrompt
+)
+
+// CheckWorldCompatibility compares the versions recorded in a world's level.dat with the game version of
+// versionName, before the world is opened in it.
+func CheckWorldCompatibility(worldDir string, versionName string) types.WorldCompatResult {
+	res := types.WorldCompatResult{WorldDir: worldDir, VersionName: strings.TrimSpace(versionName), Status: content.CompatUnknown}
+	if strings.TrimSpace(worldDir) == "" || !utils.DirExists(worldDir) {
+		res.ErrorCode = "ERR_INVALID_WORLD_DIR"
+		return res
+	}
+	meta, err := readVersionMetaByName(versionName)
+	if err != nil {
+		res.ErrorCode = "ERR_READ_VERSION_META"
+		return res
+	}
+	root, _, err := content.DecodeLevelDat(worldDir)
+	if err != nil {
+		res.ErrorCode = "ERR_READ_LEVEL_DAT"
+		return res
+	}
+	check := content.CheckWorldCompat(root, meta.GameVersion)
+	check.WorldDir, check.VersionName = res.WorldDir, res.VersionName
+	return check
+}
+
+// WorldCompatErrorCode returns the error code that stops a transfer or launch until the user confirms,
+// or an empty string when the world can be opened safely or its versions are unknown.
+func WorldCompatErrorCode(res types.WorldCompatResult) string {
+	switch res.Status {
+	case content.CompatIncompatible:
+		return "ERR_WORLD_INCOMPATIBLE_VERSION"
+	case content.CompatDowngrade:
+		return "ERR_WORLD_NEWER_THAN_VERSION"
+	}
+	return ""
+}
+
+// ConfirmWorldCompatibility repeats the check once the user accepted its warning and, when backup is
+// set and the world is at risk, backs it up first. ownerVersion names the version the world belongs to
+// and only picks the backup folder.
+func ConfirmWorldCompatibility(worldDir string, ownerVersion string, versionName string, backup bool) types.WorldCompatResult {
+	res := CheckWorldCompatibility(worldDir, versionName)
+	if res.ErrorCode != "" || !backup || WorldCompatErrorCode(res) == "" {
+		return res
+	}
+	if res.BackupPath = BackupWorldWithVersion(worldDir, ownerVersion); res.BackupPath == "" {
+		res.ErrorCode = "ERR_BACKUP_FAILED"
+	}
+	return res
+}
+
+// GetVersionPinnedWorld returns the world pinned to a version, or an empty string.
+func GetVersionPinnedWorld(name string) string {
+	m, err := readVersionMetaByName(name)
+	if err != nil {
+		return ""
+	}
+	return m.PinnedWorld
+}
+
+// SetVersionPinnedWorld pins a world to a version so every launch of it checks that world first. An
+// empty worldDir removes the pin.
+func SetVersionPinnedWorld(name string, worldDir string) string {
+	worldDir = strings.TrimSpace(worldDir)
+	if worldDir != "" {
+		if !utils.FileExists(filepath.Join(worldDir, "level.dat")) {
+			return "ERR_INVALID_WORLD_DIR"
+		}
+		worldDir = filepath.Clean(worldDir)
+	}
+	vdir, err := apppath.VersionsDir()
+	if err != nil || strings.TrimSpace(vdir) == "" {
+		return "ERR_ACCESS_VERSIONS_DIR"
+	}
+	dir := filepath.Join(vdir, strings.TrimSpace(name))
+	m, err := versions.ReadMeta(dir)
+	if err != nil {
+		return "ERR_READ_VERSION_META"
+	}
+	m.PinnedWorld = worldDir
+	if err := versions.WriteMeta(dir, m); err != nil {
+		return "ERR_WRITE_VERSION_META"
+	}
+	return ""
+}
+
+// CheckPinnedWorld checks the world pinned to a version against it. A version without a pin reports ok.
+func CheckPinnedWorld(name string) types.WorldCompatResult {
+	pinned := GetVersionPinnedWorld(name)
+	if pinned == "" {
+		return types.WorldCompatResult{VersionName: strings.TrimSpace(name), Status: content.CompatOK}
+	}
+	return CheckWorldCompatibility(pinned, name)
+}
+
+// ConfirmPinnedWorld is ConfirmWorldCompatibility for the world pinned to a version.
+func ConfirmPinnedWorld(name string, backup bool) types.WorldCompatResult {
+	pinned := GetVersionPinnedWorld(name)
+	if pinned == "" {
+		return CheckPinnedWorld(name)
+	}
+	return ConfirmWorldCompatibility(pinned, name, name, backup)
+}
+
+// PromptPinnedWorld hands a launch that was stopped by its pinned world to the frontend, for launches that
+// no window started, such as desktop shortcuts. The prompt is kept until TakeWorldCompatLaunchPrompt so a
+// window that is still loading can pick it up. It reports whether the pinned world was the reason.
+func PromptPinnedWorld(name string, force bool) bool {
+	res := CheckPinnedWorld(name)
+	if WorldCompatErrorCode(res) == "" {
+		return false
+	}
+	p := types.WorldCompatLaunchPrompt{VersionName: strings.TrimSpace(name), Force: force, Result: res}
+	launchPromptMu.Lock()
+	launchPrompt = &p
+	launchPromptMu.Unlock()
+	app := application.Get()
+	if app == nil {
+		return true
+	}
+	if w := app.Window.Current(); w != nil {
+		w.Restore()
+		w.Focus()
+	}
+	if app.Event != nil {
+		app.Event.Emit(EventWorldCompatLaunchPrompt, p)
+	}
+	return true
+}
+
+// TakeWorldCompatLaunchPrompt returns the prompt recorded by PromptPinnedWorld and clears it. VersionName
+// is empty when there is none.
+func TakeWorldCompatLaunchPrompt() types.WorldCompatLaunchPrompt {
+	launchPromptMu.Lock()
+	defer launchPromptMu.Unlock()
+	if launchPrompt == nil {
+		return types.WorldCompatLaunchPrompt{}
+	}
+	p := *launchPrompt
+	launchPrompt = nil
+	return p
+}
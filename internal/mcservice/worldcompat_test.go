@@ -0,0 +1,112 @@
+package mcservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/content"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+	"github.com/liteldev/LeviLauncher/internal/versions"
+)
+
+func writeCompatTestWorld(t *testing.T, dir string, lastOpened []int32, minClient []int32) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	root := map[string]any{"LevelName": filepath.Base(dir), "lastOpenedWithVersion": lastOpened}
+	if minClient != nil {
+		root["MinimumCompatibleClientVersion"] = minClient
+	}
+	if err := content.EncodeLevelDat(dir, 10, root); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestCheckWorldCompatibility(t *testing.T) {
+	root, _, versionsDir := setupInstanceBackupEnv(t)
+	createTestInstance(t, versionsDir, "old", versions.VersionMeta{GameVersion: "1.21.80.3"})
+	createTestInstance(t, versionsDir, "new", versions.VersionMeta{GameVersion: "1.21.100.6"})
+	newer := writeCompatTestWorld(t, filepath.Join(root, "worlds", "newer"), []int32{1, 21, 90, 1, 0}, nil)
+	locked := writeCompatTestWorld(t, filepath.Join(root, "worlds", "locked"), []int32{1, 21, 90, 1, 0}, []int32{1, 21, 90, 0, 0})
+
+	cases := []struct {
+		world, version, status, code string
+	}{
+		{newer, "new", content.CompatOK, ""},
+		{newer, "old", content.CompatDowngrade, "ERR_WORLD_NEWER_THAN_VERSION"},
+		{locked, "old", content.CompatIncompatible, "ERR_WORLD_INCOMPATIBLE_VERSION"},
+	}
+	for _, c := range cases {
+		res := CheckWorldCompatibility(c.world, c.version)
+		if res.ErrorCode != "" || res.Status != c.status || WorldCompatErrorCode(res) != c.code {
+			t.Errorf("%s in %s: %+v", filepath.Base(c.world), c.version, res)
+		}
+		if res.WorldDir != c.world || res.VersionName != c.version || res.WorldVersion != "1.21.90.1.0" {
+			t.Errorf("%s in %s: %+v", filepath.Base(c.world), c.version, res)
+		}
+	}
+	if res := CheckWorldCompatibility(filepath.Join(root, "missing"), "old"); res.ErrorCode != "ERR_INVALID_WORLD_DIR" {
+		t.Fatalf("missing world = %+v", res)
+	}
+	if res := CheckWorldCompatibility(newer, "nope"); res.ErrorCode != "ERR_READ_VERSION_META" {
+		t.Fatalf("missing version = %+v", res)
+	}
+}
+
+func TestConfirmWorldCompatibility(t *testing.T) {
+	root, _, versionsDir := setupInstanceBackupEnv(t)
+	createTestInstance(t, versionsDir, "old", versions.VersionMeta{GameVersion: "1.21.80.3"})
+	createTestInstance(t, versionsDir, "new", versions.VersionMeta{GameVersion: "1.21.100.6"})
+	world := writeCompatTestWorld(t, filepath.Join(root, "worlds", "newer"), []int32{1, 21, 90, 1, 0}, nil)
+
+	if res := ConfirmWorldCompatibility(world, "new", "old", false); res.BackupPath != "" || res.Status != content.CompatDowngrade {
+		t.Fatalf("without backup = %+v", res)
+	}
+	if res := ConfirmWorldCompatibility(world, "new", "new", true); res.BackupPath != "" || res.ErrorCode != "" {
+		t.Fatalf("compatible world backed up = %+v", res)
+	}
+	res := ConfirmWorldCompatibility(world, "new", "old", true)
+	if res.ErrorCode != "" || !utils.FileExists(res.BackupPath) {
+		t.Fatalf("with backup = %+v", res)
+	}
+	if rel, err := filepath.Rel(filepath.Join(root, "base", "backups", "worlds", "new"), res.BackupPath); err != nil || filepath.IsAbs(rel) || rel[0] == '.' {
+		t.Fatalf("backup %q is not under the owner version", res.BackupPath)
+	}
+
+	if code := SetVersionPinnedWorld("old", world); code != "" {
+		t.Fatalf("pin = %q", code)
+	}
+	if res := CheckPinnedWorld("old"); WorldCompatErrorCode(res) != "ERR_WORLD_NEWER_THAN_VERSION" {
+		t.Fatalf("pinned = %+v", res)
+	}
+	if res := CheckPinnedWorld("new"); res.Status != content.CompatOK {
+		t.Fatalf("unpinned = %+v", res)
+	}
+}
+
+func TestPromptPinnedWorld(t *testing.T) {
+	root, _, versionsDir := setupInstanceBackupEnv(t)
+	createTestInstance(t, versionsDir, "old", versions.VersionMeta{GameVersion: "1.21.80.3"})
+	createTestInstance(t, versionsDir, "new", versions.VersionMeta{GameVersion: "1.21.100.6"})
+	world := writeCompatTestWorld(t, filepath.Join(root, "worlds", "newer"), []int32{1, 21, 90, 1, 0}, nil)
+	if code := SetVersionPinnedWorld("old", world); code != "" {
+		t.Fatalf("pin = %q", code)
+	}
+
+	if PromptPinnedWorld("new", true) {
+		t.Fatal("a version without a pinned world must not prompt")
+	}
+	if !PromptPinnedWorld("old", true) {
+		t.Fatal("expected a prompt for the pinned world")
+	}
+	p := TakeWorldCompatLaunchPrompt()
+	if p.VersionName != "old" || !p.Force || p.Result.Status != content.CompatDowngrade {
+		t.Fatalf("prompt = %+v", p)
+	}
+	if again := TakeWorldCompatLaunchPrompt(); again.VersionName != "" {
+		t.Fatalf("prompt was not cleared: %+v", again)
+	}
+}
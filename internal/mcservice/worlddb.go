@@ -0,0 +1,281 @@
+package mcservice
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/liteldev/LeviLauncher/internal/apppath"
+	"github.com/liteldev/LeviLauncher/internal/content"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+	"github.com/liteldev/LeviLauncher/internal/worlddb"
+)
+
+// worldDBRawPreview caps the hex preview returned for values that are not NBT.
+const worldDBRawPreview = 4096
+
+func openWorldDB(worldDir string) (*worlddb.World, string) {
+	if strings.TrimSpace(worldDir) == "" || !utils.DirExists(worldDir) {
+		return nil, "ERR_INVALID_WORLD_DIR"
+	}
+	w, err := worlddb.Open(worldDir)
+	if err != nil {
+		return nil, "ERR_OPEN_WORLD_DB"
+	}
+	return w, ""
+}
+
+// ListWorldDBRecords pages through the keys of a world database, optionally restricted to one record kind.
+// Counts always covers every kind so the UI can show the breakdown next to the filtered page.
+func ListWorldDBRecords(worldDir string, kind string, offset int, limit int) types.WorldDBRecordPage {
+	res := types.WorldDBRecordPage{Records: []types.WorldDBRecord{}, Counts: map[string]int{}}
+	w, code := openWorldDB(worldDir)
+	if code != "" {
+		res.ErrorCode = code
+		return res
+	}
+	defer w.Close()
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = 200
+	}
+	kind = strings.TrimSpace(kind)
+	err := w.ForEach(func(key []byte, info worlddb.KeyInfo, value []byte) error {
+		res.Counts[info.Kind]++
+		if kind != "" && info.Kind != kind {
+			return nil
+		}
+		if res.Total >= offset && len(res.Records) < limit {
+			res.Records = append(res.Records, types.WorldDBRecord{
+				Key:   hex.EncodeToString(key),
+				Kind:  info.Kind,
+				Label: info.Label,
+				Size:  len(value),
+			})
+		}
+		res.Total++
+		return nil
+	})
+	if err != nil {
+		res.ErrorCode = "ERR_READ_WORLD_DB"
+	}
+	return res
+}
+
+// ReadWorldDBRecord returns the value of one record, keyed by its hex encoding as listed by
+// ListWorldDBRecords. NBT values are decoded; other values come back as a hex preview.
+func ReadWorldDBRecord(worldDir string, keyHex string) types.WorldDBRecordValue {
+	var res types.WorldDBRecordValue
+	key, err := hex.DecodeString(strings.TrimSpace(keyHex))
+	if err != nil || len(key) == 0 {
+		res.ErrorCode = "ERR_INVALID_KEY"
+		return res
+	}
+	w, code := openWorldDB(worldDir)
+	if code != "" {
+		res.ErrorCode = code
+		return res
+	}
+	defer w.Close()
+	value, err := w.Get(key)
+	if err != nil {
+		if errors.Is(err, worlddb.ErrNotFound) {
+			res.ErrorCode = "ERR_NOT_FOUND"
+		} else {
+			res.ErrorCode = "ERR_READ_WORLD_DB"
+		}
+		return res
+	}
+	info := worlddb.Classify(key)
+	res.Record = types.WorldDBRecord{Key: hex.EncodeToString(key), Kind: info.Kind, Label: info.Label, Size: len(value)}
+	if worlddb.IsNBTKind(info) {
+		if tags, err := worlddb.DecodeNBT(value); err == nil {
+			res.NBT = tags
+			return res
+		}
+	}
+	raw := value
+	if len(raw) > worldDBRawPreview {
+		raw = raw[:worldDBRawPreview]
+		res.Truncated = true
+	}
+	res.Raw = hex.EncodeToString(raw)
+	return res
+}
+
+// GetWorldStats reports where the size of a world goes: terrain per dimension, actors and block entities
+// from the database, the database against the packs bundled with the world, and when and with which game
+// version the world was last played.
+func GetWorldStats(worldDir string) types.WorldStats {
+	res := types.WorldStats{Dimensions: []types.WorldDimensionStats{}, Records: map[string]int{}, RecordBytes: map[string]int64{}}
+	w, code := openWorldDB(worldDir)
+	if code != "" {
+		res.ErrorCode = code
+		return res
+	}
+	defer w.Close()
+	st, err := w.Stats()
+	if err != nil {
+		res.ErrorCode = "ERR_READ_WORLD_DB"
+		return res
+	}
+	for _, d := range st.Dimensions {
+		res.Dimensions = append(res.Dimensions, types.WorldDimensionStats(d))
+		res.Chunks += d.Chunks
+	}
+	res.Actors = st.Actors
+	res.BlockEntities = st.BlockEntities
+	res.Records = st.Records
+	res.RecordBytes = st.RecordBytes
+
+	res.TotalSize = utils.DirSize(worldDir)
+	res.DBSize = utils.DirSize(filepath.Join(worldDir, "db"))
+	res.ResourcePacksSize = utils.DirSize(filepath.Join(worldDir, "resource_packs"))
+	res.BehaviorPacksSize = utils.DirSize(filepath.Join(worldDir, "behavior_packs"))
+
+	res.LevelName = GetWorldLevelName(worldDir)
+	if root, _, err := content.DecodeLevelDat(worldDir); err == nil {
+		if res.LevelName == "" {
+			res.LevelName, _ = root["LevelName"].(string)
+		}
+		if v, ok := root["LastPlayed"].(int64); ok {
+			res.LastPlayed = v
+		}
+		res.GameVersion = content.LastOpenedVersion(root)
+	}
+	return res
+}
+
+// PruneWorld deletes the chunks outside the keep areas of each listed dimension and compacts the world
+// database. A dry run only reports what would be removed; a real run first archives the world with
+// BackupWorld and refuses to continue without that backup.
+func PruneWorld(req types.WorldPruneRequest) types.WorldPruneResult {
+	res := types.WorldPruneResult{DryRun: req.DryRun}
+	worldDir := strings.TrimSpace(req.WorldDir)
+	if worldDir == "" || !utils.DirExists(worldDir) {
+		res.ErrorCode = "ERR_INVALID_WORLD_DIR"
+		return res
+	}
+	if len(req.Areas) == 0 {
+		res.ErrorCode = "ERR_NO_KEEP_AREAS"
+		return res
+	}
+	areas := make([]worlddb.KeepArea, 0, len(req.Areas))
+	for _, a := range req.Areas {
+		if a.Radius < 0 || (a.Radius == 0 && (a.MinX > a.MaxX || a.MinZ > a.MaxZ)) {
+			res.ErrorCode = "ERR_INVALID_AREA"
+			return res
+		}
+		areas = append(areas, worlddb.KeepArea(a))
+	}
+	if !req.DryRun {
+		// The game keeps the database open while a world is loaded and would overwrite the rewrite.
+		if len(ListMinecraftProcesses()) > 0 {
+			res.ErrorCode = "ERR_GAME_RUNNING"
+			return res
+		}
+		res.BackupPath = BackupWorld(worldDir)
+		if res.BackupPath == "" {
+			res.ErrorCode = "ERR_BACKUP_FAILED"
+			return res
+		}
+	}
+	pr, err := worlddb.Prune(worldDir, areas, req.DryRun)
+	res.Chunks, res.KeptChunks = pr.Chunks, pr.KeptChunks
+	res.Records, res.Actors, res.Bytes = pr.Records, pr.Actors, pr.Bytes
+	res.SizeBefore, res.SizeAfter = pr.SizeBefore, pr.SizeAfter
+	if err != nil {
+		res.ErrorCode = "ERR_PRUNE_WORLD"
+	}
+	return res
+}
+
+// ListWorldPlayers decodes every player stored in the world: the local player and the players that
+// joined it as a server.
+func ListWorldPlayers(worldDir string) []types.WorldPlayer {
+	out := []types.WorldPlayer{}
+	w, code := openWorldDB(worldDir)
+	if code != "" {
+		return out
+	}
+	defer w.Close()
+	keys, err := w.Players()
+	if err != nil {
+		return out
+	}
+	for _, k := range keys {
+		tags, err := w.ReadNBT([]byte(k))
+		if err != nil || len(tags) == 0 {
+			continue
+		}
+		out = append(out, worlddb.DecodePlayer(k, tags[0]))
+	}
+	return out
+}
+
+// WriteWorldPlayer writes the typed fields of a player back into its record. The previous record is
+// saved under backups/players first, so an edit can always be undone by hand.
+func WriteWorldPlayer(worldDir string, player types.WorldPlayer) types.WorldPlayerWriteResult {
+	var res types.WorldPlayerWriteResult
+	if !worlddb.IsPlayerKey(player.Key) {
+		res.ErrorCode = "ERR_INVALID_KEY"
+		return res
+	}
+	w, code := openWorldDB(worldDir)
+	if code != "" {
+		res.ErrorCode = code
+		return res
+	}
+	raw, err := w.Get([]byte(player.Key))
+	w.Close()
+	if err != nil {
+		if errors.Is(err, worlddb.ErrNotFound) {
+			res.ErrorCode = "ERR_NOT_FOUND"
+		} else {
+			res.ErrorCode = "ERR_READ_WORLD_DB"
+		}
+		return res
+	}
+	tags, err := worlddb.DecodeNBT(raw)
+	if err != nil || len(tags) == 0 {
+		res.ErrorCode = "ERR_DECODE_NBT"
+		return res
+	}
+	if len(ListMinecraftProcesses()) > 0 {
+		res.ErrorCode = "ERR_GAME_RUNNING"
+		return res
+	}
+	if err := worlddb.ApplyPlayer(tags[0], player); err != nil {
+		res.ErrorCode = "ERR_INVALID_ITEM_STACK"
+		return res
+	}
+
+	level := GetWorldLevelName(worldDir)
+	if level == "" {
+		level = utils.GetLastDirName(worldDir)
+	}
+	backupDir := filepath.Join(apppath.BaseRoot(), "backups", "players", utils.SanitizeFilename(level))
+	if err := utils.CreateDir(backupDir); err != nil {
+		res.ErrorCode = "ERR_BACKUP_FAILED"
+		return res
+	}
+	ts := time.Now().Format("20060102-150405")
+	res.BackupPath = filepath.Join(backupDir, fmt.Sprintf("%s_%s.nbt", utils.SanitizeFilename(player.Key), ts))
+	if err := os.WriteFile(res.BackupPath, raw, 0644); err != nil {
+		res.BackupPath = ""
+		res.ErrorCode = "ERR_BACKUP_FAILED"
+		return res
+	}
+
+	if err := worlddb.PutNBT(worldDir, []byte(player.Key), tags[0]); err != nil {
+		res.ErrorCode = "ERR_WRITE_WORLD_DB"
+	}
+	return res
+}
@@ -0,0 +1,91 @@
+package mcservice
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/liteldev/LeviLauncher/internal/apppath"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+	"github.com/liteldev/LeviLauncher/internal/worldindex"
+)
+
+var (
+	worldIndexOnce      sync.Once
+	worldIndexInst      *worldindex.Index
+	worldIndexRefreshMu sync.Mutex
+)
+
+func worldIndex() *worldindex.Index {
+	worldIndexOnce.Do(func() {
+		worldIndexInst = worldindex.Load(filepath.Join(apppath.BaseRoot(), "cache", "world_index.json"))
+	})
+	return worldIndexInst
+}
+
+// worldIndexLocations returns the Users folder of the shared GDK data of release and preview and of each
+// isolated version. Unlike listPackLocations it does not require a Shared folder, since a version whose
+// players never installed a pack still has worlds.
+func worldIndexLocations() []worldindex.Location {
+	var out []worldindex.Location
+	seen := map[string]struct{}{}
+	add := func(label string, versionName string, users string) {
+		users = strings.TrimSpace(users)
+		if users == "" {
+			return
+		}
+		if fi, err := os.Stat(users); err != nil || !fi.IsDir() {
+			return
+		}
+		key := strings.ToLower(filepath.Clean(users))
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		out = append(out, worldindex.Location{Label: label, VersionName: versionName, UsersDir: users})
+	}
+	for _, preview := range []bool{false, true} {
+		if base := strings.TrimSpace(utils.GetMinecraftGDKDataPath(preview)); base != "" {
+			add(instanceBackupGameDataLabel(preview), "", filepath.Join(base, "Users"))
+		}
+	}
+	for _, m := range ListVersionMetas() {
+		if !m.EnableIsolation {
+			continue
+		}
+		roots := GetContentRoots(m.Name)
+		if !roots.IsIsolation {
+			continue
+		}
+		add(m.Name, m.Name, roots.UsersRoot)
+	}
+	return out
+}
+
+// RefreshWorldIndex rescans every location for worlds, re-reading only the worlds that changed, and
+// saves the index.
+func RefreshWorldIndex() types.WorldIndexRefreshResult {
+	worldIndexRefreshMu.Lock()
+	defer worldIndexRefreshMu.Unlock()
+	ix := worldIndex()
+	res := ix.Refresh(worldIndexLocations())
+	if err := ix.Save(); err != nil {
+		res.ErrorCode = "ERR_WRITE_FILE"
+	}
+	return res
+}
+
+// SearchWorlds queries the world index, building it first if it was never refreshed.
+func SearchWorlds(query types.WorldSearchQuery) types.WorldSearchResult {
+	ix := worldIndex()
+	if ix.UpdatedAt() == 0 {
+		if r := RefreshWorldIndex(); r.ErrorCode != "" {
+			res := ix.Search(query)
+			res.ErrorCode = r.ErrorCode
+			return res
+		}
+	}
+	return ix.Search(query)
+}
@@ -0,0 +1,36 @@
+package mcservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/versions"
+)
+
+func TestWorldIndexLocationsIncludesVersionsWithoutSharedFolder(t *testing.T) {
+	_, appDataDir, versionsDir := setupInstanceBackupEnv(t)
+	isoDir := createTestInstance(t, versionsDir, "iso", versions.VersionMeta{EnableIsolation: true})
+	gameData := createTestGameDataDir(t, isoDir, appDataDir, false, true)
+	writeWorld(t, filepath.Join(gameData, "Users", "123", "games", "com.mojang", "minecraftWorlds"), "w1", "World")
+	createTestInstance(t, versionsDir, "plain", versions.VersionMeta{})
+
+	if _, err := os.Stat(filepath.Join(gameData, "Users", "Shared")); !os.IsNotExist(err) {
+		t.Fatalf("test setup should not create a Shared folder")
+	}
+	var found bool
+	for _, loc := range worldIndexLocations() {
+		if loc.VersionName == "plain" {
+			t.Fatalf("non-isolated version listed as its own location: %+v", loc)
+		}
+		if loc.VersionName == "iso" {
+			found = true
+			if loc.UsersDir != filepath.Join(gameData, "Users") {
+				t.Fatalf("unexpected users dir %q", loc.UsersDir)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("isolated version without a Shared folder was not listed")
+	}
+}
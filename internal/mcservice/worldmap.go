@@ -0,0 +1,137 @@
+package mcservice
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/apppath"
+	"github.com/liteldev/LeviLauncher/internal/content"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/worldmap"
+)
+
+const (
+	defaultWorldMapRadius = 128
+	maxWorldMapRadius     = 1024
+	defaultWorldMapTile   = 512
+	// maxWorldMapSide bounds the area one RenderWorldMap call may cover, in blocks per side.
+	maxWorldMapSide = 8192
+)
+
+// worldSpawnXZ returns the spawn column stored in level.dat, or 0,0 when it cannot be read.
+func worldSpawnXZ(worldDir string) (int32, int32) {
+	root, _, err := content.DecodeLevelDat(worldDir)
+	if err != nil {
+		return 0, 0
+	}
+	data := root
+	if v, ok := root["Data"].(map[string]any); ok {
+		data = v
+	}
+	x, _ := data["SpawnX"].(int32)
+	z, _ := data["SpawnZ"].(int32)
+	return x, z
+}
+
+func spawnArea(worldDir string, radius int) worldmap.Area {
+	x, z := worldSpawnXZ(worldDir)
+	r := int32(radius)
+	return worldmap.Area{MinX: x - r, MinZ: z - r, MaxX: x + r - 1, MaxZ: z + r - 1}
+}
+
+// worldMapCacheDir returns the folder the tiles of a world are written to.
+func worldMapCacheDir(worldDir string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(filepath.Clean(worldDir))))
+	return filepath.Join(apppath.BaseRoot(), "cache", "worldmap", hex.EncodeToString(sum[:8]))
+}
+
+// RenderWorldMap renders a top-down map of an area of a world into PNG tiles under the launcher cache.
+// An empty area renders 256x256 blocks around the world spawn.
+func RenderWorldMap(req types.WorldMapRequest) types.WorldMapResult {
+	res := types.WorldMapResult{Tiles: []types.WorldMapTile{}}
+	area := worldmap.Area{MinX: req.MinX, MinZ: req.MinZ, MaxX: req.MaxX, MaxZ: req.MaxZ}
+	if area == (worldmap.Area{}) {
+		area = spawnArea(req.WorldDir, defaultWorldMapRadius)
+	}
+	if !area.Valid() {
+		res.ErrorCode = "ERR_INVALID_AREA"
+		return res
+	}
+	if area.Width() > maxWorldMapSide || area.Height() > maxWorldMapSide {
+		res.ErrorCode = "ERR_AREA_TOO_LARGE"
+		return res
+	}
+	tileSize := req.TileSize
+	if tileSize <= 0 {
+		tileSize = defaultWorldMapTile
+	}
+	tileSize = min(tileSize, worldmap.MaxRenderSize)
+
+	w, code := openWorldDB(req.WorldDir)
+	if code != "" {
+		res.ErrorCode = code
+		return res
+	}
+	defer w.Close()
+	outDir := worldMapCacheDir(req.WorldDir)
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		res.ErrorCode = "ERR_CREATE_TARGET_DIR"
+		return res
+	}
+	for _, tile := range worldmap.Tiles(area, tileSize) {
+		img, st, err := worldmap.Render(w, req.Dimension, tile)
+		if err != nil {
+			res.ErrorCode = "ERR_RENDER_WORLD_MAP"
+			return res
+		}
+		res.Chunks += st.Chunks
+		res.MissingChunks += st.MissingChunks
+		res.UnsupportedSubChunks += st.UnsupportedSubChunks
+		p := filepath.Join(outDir, fmt.Sprintf("%d_%d_%d_%d_%d.png", req.Dimension, tile.MinX, tile.MinZ, tile.MaxX, tile.MaxZ))
+		f, err := os.Create(p)
+		if err != nil {
+			res.ErrorCode = "ERR_WRITE_FILE"
+			return res
+		}
+		err = png.Encode(f, img)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			res.ErrorCode = "ERR_WRITE_FILE"
+			return res
+		}
+		res.Tiles = append(res.Tiles, types.WorldMapTile{MinX: tile.MinX, MinZ: tile.MinZ, MaxX: tile.MaxX, MaxZ: tile.MaxZ, Path: p})
+	}
+	return res
+}
+
+// GetWorldMapDataUrl renders the area within radius blocks of the world spawn as a PNG data URL, for
+// display next to the world icon. It returns an empty string when the world cannot be rendered.
+func GetWorldMapDataUrl(worldDir string, dimension int32, radius int) string {
+	if radius <= 0 {
+		radius = defaultWorldMapRadius
+	}
+	radius = min(radius, maxWorldMapRadius)
+	w, code := openWorldDB(worldDir)
+	if code != "" {
+		return ""
+	}
+	defer w.Close()
+	img, _, err := worldmap.Render(w, dimension, spawnArea(worldDir, radius))
+	if err != nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return ""
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
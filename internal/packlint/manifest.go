@@ -0,0 +1,323 @@
+package packlint
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	uuidPattern   = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	semverPattern = regexp.MustCompile(`^\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+)
+
+var knownModuleTypes = map[string]struct{}{
+	"resources":      {},
+	"data":           {},
+	"client_data":    {},
+	"interface":      {},
+	"world_template": {},
+	"skin_pack":      {},
+	"script":         {},
+	"javascript":     {},
+}
+
+// Top-level folders that only make sense in one kind of pack. Folders shared by both kinds, such as
+// entities or animation_controllers, are not listed.
+var (
+	behaviorOnlyDirs = []string{"loot_tables", "functions", "recipes", "spawn_rules", "trading", "scripts", "structures", "feature_rules", "features", "dimensions"}
+	resourceOnlyDirs = []string{"textures", "sounds", "models", "particles", "render_controllers", "attachables", "fogs", "ui", "font", "materials"}
+)
+
+type manifestInfo struct {
+	formatVersion int
+	moduleTypes   []string
+}
+
+func (m manifestInfo) has(moduleType string) bool {
+	for _, t := range m.moduleTypes {
+		if t == moduleType {
+			return true
+		}
+	}
+	return false
+}
+
+func (m manifestInfo) isResource() bool { return m.has("resources") }
+
+func (m manifestInfo) isSkinPack() bool { return m.has("skin_pack") }
+
+func (m manifestInfo) isWorldTemplate() bool { return m.has("world_template") }
+
+func (m manifestInfo) packType() string {
+	switch {
+	case m.isSkinPack():
+		return "skin_pack"
+	case m.isWorldTemplate():
+		return "world_template"
+	case m.has("data") && m.isResource():
+		return "mixed"
+	case m.has("data"):
+		return "behavior"
+	case m.isResource():
+		return "resources"
+	}
+	return ""
+}
+
+func (l *linter) checkManifest() manifestInfo {
+	const file = "manifest.json"
+	var info manifestInfo
+	var raw map[string]any
+	if !l.readJSON(file, &raw) {
+		return info
+	}
+
+	switch fv := raw["format_version"].(type) {
+	case nil:
+		l.add(SeverityError, CodeFormatVersion, file, "format_version", "format_version is missing")
+	case float64:
+		if fv != math.Trunc(fv) || fv < 1 || fv > 3 {
+			l.add(SeverityError, CodeFormatVersion, file, "format_version", fmt.Sprintf("unsupported format_version %v, expected 1, 2 or 3", fv))
+		} else {
+			info.formatVersion = int(fv)
+		}
+	default:
+		l.add(SeverityError, CodeFormatVersion, file, "format_version", "format_version must be a number")
+	}
+	l.report.FormatVersion = info.formatVersion
+
+	if mods, ok := raw["modules"].([]any); ok {
+		for _, it := range mods {
+			if mod, ok := it.(map[string]any); ok {
+				if tp, ok := mod["type"].(string); ok {
+					info.moduleTypes = append(info.moduleTypes, strings.ToLower(strings.TrimSpace(tp)))
+				}
+			}
+		}
+	}
+	l.report.PackType = info.packType()
+
+	seen := map[string]string{}
+	l.checkHeader(raw, info, seen)
+	l.checkModules(raw, info, seen)
+	l.checkDependencies(raw, info, seen)
+	l.checkModuleContent(info)
+	return info
+}
+
+func (l *linter) checkHeader(raw map[string]any, info manifestInfo, seen map[string]string) {
+	const file = "manifest.json"
+	header, ok := raw["header"].(map[string]any)
+	if !ok {
+		l.add(SeverityError, CodeHeaderFieldMissing, file, "header", "header is missing")
+		return
+	}
+	if name, _ := header["name"].(string); strings.TrimSpace(name) == "" {
+		l.add(SeverityError, CodeHeaderFieldMissing, file, "header.name", "header.name is missing")
+	} else {
+		l.report.Name = strings.TrimSpace(name)
+	}
+	if _, ok := header["description"].(string); !ok {
+		l.add(SeverityWarning, CodeHeaderFieldMissing, file, "header.description", "header.description is missing")
+	}
+	if uuid, ok := l.checkUUID(header["uuid"], "header.uuid", seen); ok {
+		l.report.UUID = uuid
+	}
+	if v, ok := l.checkVersion(header["version"], "header.version", info.formatVersion); ok {
+		l.report.Version = v
+	}
+
+	if info.isSkinPack() || info.isWorldTemplate() {
+		return
+	}
+	mev, has := header["min_engine_version"]
+	switch {
+	case !has && info.formatVersion >= 2:
+		l.add(SeverityWarning, CodeHeaderFieldMissing, file, "header.min_engine_version", "header.min_engine_version is missing, the pack is treated as legacy content")
+	case has && info.formatVersion == 1:
+		l.add(SeverityInfo, CodeVersionFormatMismatch, file, "header.min_engine_version", "min_engine_version is ignored in format_version 1")
+	case has:
+		l.checkVersion(mev, "header.min_engine_version", info.formatVersion)
+	}
+}
+
+func (l *linter) checkModules(raw map[string]any, info manifestInfo, seen map[string]string) {
+	const file = "manifest.json"
+	mods, ok := raw["modules"].([]any)
+	if !ok || len(mods) == 0 {
+		l.add(SeverityError, CodeModulesMissing, file, "modules", "manifest declares no modules")
+		return
+	}
+	for i, it := range mods {
+		field := fmt.Sprintf("modules[%d]", i)
+		mod, ok := it.(map[string]any)
+		if !ok {
+			l.add(SeverityError, CodeModulesMissing, file, field, "module must be an object")
+			continue
+		}
+		tp, _ := mod["type"].(string)
+		tp = strings.ToLower(strings.TrimSpace(tp))
+		if tp == "" {
+			l.add(SeverityError, CodeHeaderFieldMissing, file, field+".type", "module type is missing")
+		} else if _, known := knownModuleTypes[tp]; !known {
+			l.add(SeverityError, CodeUnknownModuleType, file, field+".type", fmt.Sprintf("unknown module type %q", tp))
+		}
+		l.checkUUID(mod["uuid"], field+".uuid", seen)
+		l.checkVersion(mod["version"], field+".version", info.formatVersion)
+
+		if tp == "script" || tp == "javascript" {
+			entry, _ := mod["entry"].(string)
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				l.add(SeverityError, CodeScriptModuleIncomplete, file, field+".entry", "script module has no entry")
+			} else if !fileExists(l.fsys, strings.TrimPrefix(strings.ReplaceAll(entry, "\\", "/"), "./")) {
+				l.add(SeverityError, CodeScriptModuleIncomplete, file, field+".entry", fmt.Sprintf("script entry %q does not exist", entry))
+			}
+			if lang, _ := mod["language"].(string); strings.TrimSpace(lang) == "" && tp == "script" {
+				l.add(SeverityWarning, CodeScriptModuleIncomplete, file, field+".language", "script module has no language")
+			}
+		}
+	}
+
+	switch {
+	case info.has("resources") && info.has("data"):
+		l.add(SeverityError, CodeModuleTypeConflict, file, "modules", "a pack cannot contain both resources and data modules")
+	case info.isSkinPack() && len(info.moduleTypes) > 1:
+		l.add(SeverityError, CodeModuleTypeConflict, file, "modules", "skin_pack modules cannot be combined with other module types")
+	case info.isWorldTemplate() && (info.has("resources") || info.has("data")):
+		l.add(SeverityError, CodeModuleTypeConflict, file, "modules", "world_template modules cannot be combined with resources or data modules")
+	}
+	if (info.has("script") || info.has("javascript")) && !info.has("data") {
+		l.add(SeverityError, CodeModuleTypeConflict, file, "modules", "script modules are only loaded from behavior packs with a data module")
+	}
+}
+
+func (l *linter) checkDependencies(raw map[string]any, info manifestInfo, seen map[string]string) {
+	const file = "manifest.json"
+	deps, ok := raw["dependencies"].([]any)
+	if !ok {
+		return
+	}
+	header, _ := raw["header"].(map[string]any)
+	own, _ := header["uuid"].(string)
+	for i, it := range deps {
+		field := fmt.Sprintf("dependencies[%d]", i)
+		dep, ok := it.(map[string]any)
+		if !ok {
+			l.add(SeverityError, CodeHeaderFieldMissing, file, field, "dependency must be an object")
+			continue
+		}
+		if name, ok := dep["module_name"].(string); ok && strings.TrimSpace(name) != "" {
+			// Script module versions such as "1.8.0-beta" are strings in every format version.
+			if s, ok := dep["version"].(string); ok {
+				if !semverPattern.MatchString(strings.TrimSpace(s)) {
+					l.add(SeverityError, CodeInvalidVersion, file, field+".version", fmt.Sprintf("invalid module version %q", s))
+				}
+			} else {
+				l.checkVersion(dep["version"], field+".version", info.formatVersion)
+			}
+			continue
+		}
+		id, ok := dep["uuid"].(string)
+		if !ok {
+			l.add(SeverityError, CodeHeaderFieldMissing, file, field, "dependency has neither uuid nor module_name")
+			continue
+		}
+		id = strings.TrimSpace(id)
+		if !uuidPattern.MatchString(id) {
+			l.add(SeverityError, CodeInvalidUUID, file, field+".uuid", fmt.Sprintf("invalid uuid %q", id))
+		} else if strings.EqualFold(id, strings.TrimSpace(own)) {
+			l.add(SeverityError, CodeDuplicateUUID, file, field+".uuid", "pack depends on its own header uuid")
+		} else if prev, dup := seen[strings.ToLower(id)]; dup {
+			l.add(SeverityError, CodeDuplicateUUID, file, field+".uuid", fmt.Sprintf("dependency uuid is already used by %s", prev))
+		}
+		l.checkVersion(dep["version"], field+".version", info.formatVersion)
+	}
+}
+
+// checkModuleContent flags packs whose folder layout contradicts the declared module type, e.g. a
+// resources module shipped with loot_tables and functions but no textures.
+func (l *linter) checkModuleContent(info manifestInfo) {
+	const file = "manifest.json"
+	count := func(dirs []string) []string {
+		var found []string
+		for _, d := range dirs {
+			if dirExists(l.fsys, d) {
+				found = append(found, d)
+			}
+		}
+		return found
+	}
+	bp := count(behaviorOnlyDirs)
+	rp := count(resourceOnlyDirs)
+	switch {
+	case info.isResource() && !info.has("data") && len(bp) > 0 && len(rp) == 0:
+		l.add(SeverityWarning, CodeModuleTypeMismatch, file, "modules", fmt.Sprintf("resources module but the pack only contains behavior folders (%s)", strings.Join(bp, ", ")))
+	case info.has("data") && !info.isResource() && len(rp) > 0 && len(bp) == 0:
+		l.add(SeverityWarning, CodeModuleTypeMismatch, file, "modules", fmt.Sprintf("data module but the pack only contains resource folders (%s)", strings.Join(rp, ", ")))
+	}
+}
+
+func (l *linter) checkUUID(v any, field string, seen map[string]string) (string, bool) {
+	const file = "manifest.json"
+	s, ok := v.(string)
+	if !ok || strings.TrimSpace(s) == "" {
+		l.add(SeverityError, CodeHeaderFieldMissing, file, field, field+" is missing")
+		return "", false
+	}
+	s = strings.TrimSpace(s)
+	if !uuidPattern.MatchString(s) {
+		l.add(SeverityError, CodeInvalidUUID, file, field, fmt.Sprintf("invalid uuid %q", s))
+		return s, false
+	}
+	key := strings.ToLower(s)
+	if prev, dup := seen[key]; dup {
+		l.add(SeverityError, CodeDuplicateUUID, file, field, fmt.Sprintf("uuid is already used by %s", prev))
+		return s, false
+	}
+	seen[key] = field
+	return s, true
+}
+
+// checkVersion validates a version value. Arrays of three non-negative integers are accepted by every
+// format version; semver strings are only understood from format_version 3 on.
+func (l *linter) checkVersion(v any, field string, formatVersion int) (string, bool) {
+	const file = "manifest.json"
+	switch val := v.(type) {
+	case nil:
+		l.add(SeverityError, CodeHeaderFieldMissing, file, field, field+" is missing")
+		return "", false
+	case []any:
+		if len(val) != 3 {
+			l.add(SeverityError, CodeInvalidVersion, file, field, fmt.Sprintf("version array must have 3 numbers, got %d", len(val)))
+			return "", false
+		}
+		parts := make([]string, 0, 3)
+		for _, x := range val {
+			f, ok := x.(float64)
+			if !ok || f < 0 || f != math.Trunc(f) {
+				l.add(SeverityError, CodeInvalidVersion, file, field, "version array must contain non-negative integers")
+				return "", false
+			}
+			parts = append(parts, strconv.Itoa(int(f)))
+		}
+		return strings.Join(parts, "."), true
+	case string:
+		s := strings.TrimSpace(val)
+		if formatVersion > 0 && formatVersion < 3 {
+			l.add(SeverityError, CodeVersionFormatMismatch, file, field, fmt.Sprintf("string version %q requires format_version 3, use an array like [1, 0, 0]", s))
+			return s, false
+		}
+		if !semverPattern.MatchString(s) {
+			l.add(SeverityError, CodeInvalidVersion, file, field, fmt.Sprintf("invalid version %q", s))
+			return s, false
+		}
+		return s, true
+	default:
+		l.add(SeverityError, CodeInvalidVersion, file, field, "version must be an array or a string")
+		return "", false
+	}
+}
@@ -0,0 +1,279 @@
+// Package packlint validates Minecraft Bedrock resource, behavior and skin packs.
+//
+// The checks are purely static: they read manifest.json and the JSON files of a pack and report
+// problems that usually make the game ignore or partially load the pack. A pack is accepted from a
+// directory, an .mcpack/.zip archive or any fs.FS, so the same API can be used by the launcher UI and
+// by CI jobs.
+package packlint
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+const (
+	CodeManifestMissing        = "MANIFEST_MISSING"
+	CodeJSONSyntax             = "JSON_SYNTAX"
+	CodeFormatVersion          = "FORMAT_VERSION"
+	CodeHeaderFieldMissing     = "HEADER_FIELD_MISSING"
+	CodeInvalidUUID            = "INVALID_UUID"
+	CodeDuplicateUUID          = "DUPLICATE_UUID"
+	CodeInvalidVersion         = "INVALID_VERSION"
+	CodeVersionFormatMismatch  = "VERSION_FORMAT_MISMATCH"
+	CodeModulesMissing         = "MODULES_MISSING"
+	CodeUnknownModuleType      = "UNKNOWN_MODULE_TYPE"
+	CodeModuleTypeConflict     = "MODULE_TYPE_CONFLICT"
+	CodeModuleTypeMismatch     = "MODULE_TYPE_MISMATCH"
+	CodeScriptModuleIncomplete = "SCRIPT_MODULE_INCOMPLETE"
+	CodePackIconMissing        = "PACK_ICON_MISSING"
+	CodePackIconInvalid        = "PACK_ICON_INVALID"
+	CodeTextureMissing         = "TEXTURE_MISSING"
+)
+
+type linter struct {
+	fsys   fs.FS
+	report *types.PackLintReport
+}
+
+func (l *linter) add(severity string, code string, file string, field string, message string) {
+	l.report.Issues = append(l.report.Issues, types.PackLintIssue{
+		Severity: severity,
+		Code:     code,
+		File:     file,
+		Field:    field,
+		Message:  message,
+	})
+}
+
+func (l *linter) addAt(severity string, code string, file string, line int, column int, message string) {
+	l.report.Issues = append(l.report.Issues, types.PackLintIssue{
+		Severity: severity,
+		Code:     code,
+		File:     file,
+		Line:     line,
+		Column:   column,
+		Message:  message,
+	})
+}
+
+// ValidatePath validates a pack directory or a .mcpack/.zip archive.
+func ValidatePath(p string) types.PackLintReport {
+	target := strings.TrimSpace(p)
+	fi, err := os.Stat(target)
+	if err != nil {
+		r := types.PackLintReport{Path: target, Issues: []types.PackLintIssue{}}
+		r.Issues = append(r.Issues, types.PackLintIssue{Severity: SeverityError, Code: CodeManifestMissing, Message: "pack path does not exist"})
+		finish(&r)
+		return r
+	}
+	if fi.IsDir() {
+		return ValidateDir(target)
+	}
+	b, err := os.ReadFile(target)
+	if err != nil {
+		r := types.PackLintReport{Path: target, Issues: []types.PackLintIssue{}}
+		r.Issues = append(r.Issues, types.PackLintIssue{Severity: SeverityError, Code: CodeManifestMissing, Message: err.Error()})
+		finish(&r)
+		return r
+	}
+	r := ValidateArchive(b)
+	r.Path = target
+	return r
+}
+
+// ValidateDir validates the pack rooted at dir.
+func ValidateDir(dir string) types.PackLintReport {
+	r := ValidateFS(os.DirFS(dir))
+	r.Path = dir
+	return r
+}
+
+// ValidateArchive validates a zipped pack held in memory.
+func ValidateArchive(data []byte) types.PackLintReport {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		r := types.PackLintReport{Issues: []types.PackLintIssue{}}
+		r.Issues = append(r.Issues, types.PackLintIssue{Severity: SeverityError, Code: CodeManifestMissing, Message: "archive cannot be opened: " + err.Error()})
+		finish(&r)
+		return r
+	}
+	return ValidateFS(zr)
+}
+
+// ValidateFS validates the pack contained in fsys. The pack root is the shallowest directory holding a
+// manifest.json, which allows archives that wrap the pack in a top-level folder.
+func ValidateFS(fsys fs.FS) types.PackLintReport {
+	r := types.PackLintReport{Issues: []types.PackLintIssue{}}
+	root := findManifestRoot(fsys)
+	if root == "" {
+		r.Issues = append(r.Issues, types.PackLintIssue{
+			Severity: SeverityError,
+			Code:     CodeManifestMissing,
+			File:     "manifest.json",
+			Message:  "manifest.json not found",
+		})
+		finish(&r)
+		return r
+	}
+	r.Root = root
+	sub := fsys
+	if root != "." {
+		if s, err := fs.Sub(fsys, root); err == nil {
+			sub = s
+		}
+	}
+	l := &linter{fsys: sub, report: &r}
+	l.checkJSONFiles()
+	mf := l.checkManifest()
+	l.checkPackIcon(mf)
+	if mf.isResource() {
+		l.checkTextureLists()
+	}
+	finish(&r)
+	return r
+}
+
+func finish(r *types.PackLintReport) {
+	sort.SliceStable(r.Issues, func(i, j int) bool {
+		a, b := r.Issues[i], r.Issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	r.ErrorCount = 0
+	r.WarningCount = 0
+	for _, it := range r.Issues {
+		switch it.Severity {
+		case SeverityError:
+			r.ErrorCount++
+		case SeverityWarning:
+			r.WarningCount++
+		}
+	}
+	r.Valid = r.ErrorCount == 0
+}
+
+func findManifestRoot(fsys fs.FS) string {
+	if fileExists(fsys, "manifest.json") {
+		return "."
+	}
+	queue := []string{"."}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		ents, err := fs.ReadDir(fsys, cur)
+		if err != nil {
+			continue
+		}
+		for _, e := range ents {
+			if !e.IsDir() {
+				continue
+			}
+			sub := path.Join(cur, e.Name())
+			if fileExists(fsys, path.Join(sub, "manifest.json")) {
+				return sub
+			}
+			queue = append(queue, sub)
+		}
+	}
+	return ""
+}
+
+func fileExists(fsys fs.FS, name string) bool {
+	fi, err := fs.Stat(fsys, name)
+	return err == nil && !fi.IsDir()
+}
+
+func dirExists(fsys fs.FS, name string) bool {
+	fi, err := fs.Stat(fsys, name)
+	return err == nil && fi.IsDir()
+}
+
+// checkJSONFiles parses every .json file of the pack with comment tolerance and reports syntax errors
+// together with their line and column.
+func (l *linter) checkJSONFiles() {
+	_ = fs.WalkDir(l.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if !strings.EqualFold(path.Ext(p), ".json") {
+			return nil
+		}
+		b, err := fs.ReadFile(l.fsys, p)
+		if err != nil {
+			return nil
+		}
+		l.report.FilesChecked++
+		if line, col, msg, ok := jsonSyntaxError(b); !ok {
+			l.addAt(SeverityError, CodeJSONSyntax, p, line, col, msg)
+		}
+		return nil
+	})
+}
+
+// readJSON decodes a pack JSON file, returning false when the file is missing or malformed. Syntax
+// errors are already reported by checkJSONFiles.
+func (l *linter) readJSON(name string, v any) bool {
+	b, err := fs.ReadFile(l.fsys, name)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(utils.JsonCompatBytes(stripBOM(b)), v) == nil
+}
+
+func jsonSyntaxError(data []byte) (int, int, string, bool) {
+	clean := utils.JsonCompatBytesKeepLines(stripBOM(data))
+	var v any
+	err := json.Unmarshal(clean, &v)
+	if err == nil {
+		return 0, 0, "", true
+	}
+	var se *json.SyntaxError
+	if errors.As(err, &se) {
+		line, col := lineColumn(clean, se.Offset)
+		return line, col, se.Error(), false
+	}
+	return 0, 0, err.Error(), false
+}
+
+func stripBOM(b []byte) []byte {
+	return bytes.TrimPrefix(b, []byte{0xEF, 0xBB, 0xBF})
+}
+
+// lineColumn converts a byte offset into a 1-based line and column. utils.JsonCompatBytesKeepLines keeps
+// every line break of the source, so the line matches the original file.
+func lineColumn(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	line, col := 1, 1
+	for i := int64(0); i < offset; i++ {
+		if data[i] == '\n' {
+			line++
+			col = 1
+			continue
+		}
+		col++
+	}
+	if col > 1 {
+		col--
+	}
+	return line, col
+}
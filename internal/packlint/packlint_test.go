@@ -0,0 +1,142 @@
+package packlint
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+const validResourceManifest = `{
+	// comments are tolerated like in game
+	"format_version": 2,
+	"header": {
+		"name": "Test RP",
+		"description": "desc",
+		"uuid": "0f8d6c4e-2b1a-4c3d-9e8f-1a2b3c4d5e6f",
+		"version": [1, 0, 0],
+		"min_engine_version": [1, 20, 0],
+	},
+	"modules": [
+		{"type": "resources", "uuid": "1f8d6c4e-2b1a-4c3d-9e8f-1a2b3c4d5e6f", "version": [1, 0, 0]}
+	]
+}`
+
+func writeTestPack(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", name, err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func findIssue(r types.PackLintReport, code string) (types.PackLintIssue, bool) {
+	for _, it := range r.Issues {
+		if it.Code == code {
+			return it, true
+		}
+	}
+	return types.PackLintIssue{}, false
+}
+
+func TestValidateDirAcceptsValidResourcePack(t *testing.T) {
+	dir := writeTestPack(t, map[string]string{
+		"manifest.json":                 validResourceManifest,
+		"pack_icon.png":                 string(pngSignature) + "rest",
+		"textures/terrain_texture.json": `{"texture_data": {"stone": {"textures": "textures/blocks/stone"}}}`,
+		"textures/blocks/stone.png":     "png",
+	})
+
+	r := ValidateDir(dir)
+	if !r.Valid || len(r.Issues) != 0 {
+		t.Fatalf("expected a clean report, got %+v", r.Issues)
+	}
+	if r.PackType != "resources" || r.Version != "1.0.0" || r.FormatVersion != 2 {
+		t.Fatalf("unexpected report summary: %+v", r)
+	}
+}
+
+func TestValidateDirReportsJSONSyntaxLine(t *testing.T) {
+	dir := writeTestPack(t, map[string]string{
+		"manifest.json": validResourceManifest,
+		"pack_icon.png": string(pngSignature),
+		"sounds.json":   "{\n  /* multi\n     line */\n  \"a\": 1\n  \"b\": 2\n}",
+	})
+
+	r := ValidateDir(dir)
+	issue, ok := findIssue(r, CodeJSONSyntax)
+	if !ok {
+		t.Fatalf("expected a syntax error, got %+v", r.Issues)
+	}
+	if issue.File != "sounds.json" || issue.Line != 5 {
+		t.Fatalf("expected sounds.json line 5, got %s line %d", issue.File, issue.Line)
+	}
+	if r.Valid {
+		t.Fatalf("report with syntax errors must not be valid")
+	}
+}
+
+func TestValidateDirManifestProblems(t *testing.T) {
+	dir := writeTestPack(t, map[string]string{
+		"manifest.json": `{
+			"format_version": 2,
+			"header": {"uuid": "not-a-uuid", "version": "1.0.0"},
+			"modules": [
+				{"type": "resources", "uuid": "1f8d6c4e-2b1a-4c3d-9e8f-1a2b3c4d5e6f", "version": [1, 0]},
+				{"type": "data", "uuid": "1f8d6c4e-2b1a-4c3d-9e8f-1a2b3c4d5e6f", "version": [1, 0, 0]}
+			]
+		}`,
+		"textures/terrain_texture.json": `{"texture_data": {"ore": {"textures": [{"path": "textures/blocks/ore"}]}}}`,
+	})
+
+	r := ValidateDir(dir)
+	for _, code := range []string{
+		CodeHeaderFieldMissing,
+		CodeInvalidUUID,
+		CodeVersionFormatMismatch,
+		CodeInvalidVersion,
+		CodeDuplicateUUID,
+		CodeModuleTypeConflict,
+		CodePackIconMissing,
+		CodeTextureMissing,
+	} {
+		if _, ok := findIssue(r, code); !ok {
+			t.Errorf("expected issue %s, got %+v", code, r.Issues)
+		}
+	}
+}
+
+func TestValidateArchiveFindsNestedManifest(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range map[string]string{
+		"MyPack/manifest.json": validResourceManifest,
+		"MyPack/pack_icon.png": string(pngSignature),
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+
+	r := ValidateArchive(buf.Bytes())
+	if r.Root != "MyPack" || !r.Valid {
+		t.Fatalf("unexpected report: root=%q issues=%+v", r.Root, r.Issues)
+	}
+}
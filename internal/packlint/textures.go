@@ -0,0 +1,130 @@
+package packlint
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// Extensions the game tries, in order, when a texture is referenced without one.
+var textureExtensions = []string{".png", ".tga", ".jpg", ".jpeg", ".texture_set.json"}
+
+func (l *linter) checkPackIcon(info manifestInfo) {
+	const icon = "pack_icon.png"
+	if info.isWorldTemplate() {
+		return
+	}
+	b, err := fs.ReadFile(l.fsys, icon)
+	if err != nil {
+		l.add(SeverityWarning, CodePackIconMissing, icon, "", "pack_icon.png is missing, the game shows a placeholder icon")
+		return
+	}
+	if !bytes.HasPrefix(b, pngSignature) {
+		l.add(SeverityWarning, CodePackIconInvalid, icon, "", "pack_icon.png is not a PNG image")
+	}
+}
+
+// checkTextureLists verifies that every texture path referenced from the atlas and list files of a
+// resource pack resolves to a file inside the pack.
+func (l *linter) checkTextureLists() {
+	refs := map[string][]string{}
+	addRefs := func(file string, paths []string) {
+		refs[file] = append(refs[file], paths...)
+	}
+
+	for _, atlas := range []string{"textures/terrain_texture.json", "textures/item_texture.json"} {
+		var doc struct {
+			TextureData map[string]any `json:"texture_data"`
+		}
+		if !l.readJSON(atlas, &doc) {
+			continue
+		}
+		keys := make([]string, 0, len(doc.TextureData))
+		for k := range doc.TextureData {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			entry, ok := doc.TextureData[k].(map[string]any)
+			if !ok {
+				continue
+			}
+			addRefs(atlas, collectTexturePaths(entry["textures"]))
+		}
+	}
+
+	const flipbook = "textures/flipbook_textures.json"
+	var flips []map[string]any
+	if l.readJSON(flipbook, &flips) {
+		for _, f := range flips {
+			addRefs(flipbook, collectTexturePaths(f["flipbook_texture"]))
+		}
+	}
+
+	const list = "textures/textures_list.json"
+	var listed []any
+	if l.readJSON(list, &listed) {
+		addRefs(list, collectTexturePaths(listed))
+	}
+
+	files := make([]string, 0, len(refs))
+	for f := range refs {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		reported := map[string]struct{}{}
+		for _, ref := range refs[file] {
+			if _, dup := reported[ref]; dup {
+				continue
+			}
+			if !l.textureExists(ref) {
+				reported[ref] = struct{}{}
+				l.add(SeverityWarning, CodeTextureMissing, file, "", fmt.Sprintf("referenced texture %q does not exist", ref))
+			}
+		}
+	}
+}
+
+// collectTexturePaths flattens the shapes used by texture lists: a plain string, an array of strings,
+// an object with a "path" field, or an array mixing those.
+func collectTexturePaths(v any) []string {
+	switch t := v.(type) {
+	case string:
+		if s := strings.TrimSpace(t); s != "" {
+			return []string{s}
+		}
+	case []any:
+		var out []string
+		for _, it := range t {
+			out = append(out, collectTexturePaths(it)...)
+		}
+		return out
+	case map[string]any:
+		if p, ok := t["path"]; ok {
+			return collectTexturePaths(p)
+		}
+	}
+	return nil
+}
+
+func (l *linter) textureExists(ref string) bool {
+	p := path.Clean(strings.TrimPrefix(strings.ReplaceAll(ref, "\\", "/"), "/"))
+	if strings.HasPrefix(p, "../") {
+		return true
+	}
+	if path.Ext(p) != "" && fileExists(l.fsys, p) {
+		return true
+	}
+	for _, ext := range textureExtensions {
+		if fileExists(l.fsys, p+ext) {
+			return true
+		}
+	}
+	return false
+}
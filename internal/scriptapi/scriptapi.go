@@ -0,0 +1,228 @@
+// Package scriptapi checks the script modules a behavior pack depends on against a game version.
+//
+// Which module versions a release supports comes from a table bundled with the launcher. A newer table
+// can be installed next to the launcher config; Load prefers whichever of the two has the higher
+// revision. Stable module versions keep working in every later release, while a beta version is only
+// loaded by the releases it shipped with and only in worlds with the Beta APIs experiment.
+package scriptapi
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/liteldev/LeviLauncher/internal/content"
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+const (
+	CodeModuleUnknown      = "MODULE_UNKNOWN"
+	CodeVersionInvalid     = "VERSION_INVALID"
+	CodeVersionTooNew      = "VERSION_TOO_NEW"
+	CodeVersionUnsupported = "VERSION_UNSUPPORTED"
+	CodeVersionUnverified  = "VERSION_UNVERIFIED"
+	CodeBetaMismatch       = "BETA_VERSION_MISMATCH"
+	CodeBetaAPIsDisabled   = "BETA_APIS_DISABLED"
+)
+
+//go:embed modules.json
+var bundledTable []byte
+
+var ErrInvalidTable = errors.New("scriptapi: invalid module table")
+
+// Module lists the supported versions of one script module. Stable maps a version to the first release
+// that supports it; Beta maps a version to the first and last release that load it, or to a single
+// release.
+type Module struct {
+	Name   string              `json:"name"`
+	UUID   string              `json:"uuid,omitempty"`
+	Stable map[string]string   `json:"stable,omitempty"`
+	Beta   map[string][]string `json:"beta,omitempty"`
+}
+
+// Table is a script module compatibility table. Game is the newest release it describes; versions of
+// later releases can only be checked against the stable entries.
+type Table struct {
+	Revision int      `json:"revision"`
+	Game     string   `json:"game"`
+	Modules  []Module `json:"modules"`
+}
+
+// ParseTable decodes and validates a module table.
+func ParseTable(b []byte) (*Table, error) {
+	var t Table
+	if err := json.Unmarshal(b, &t); err != nil {
+		return nil, err
+	}
+	if t.Revision <= 0 || strings.TrimSpace(t.Game) == "" || len(t.Modules) == 0 {
+		return nil, ErrInvalidTable
+	}
+	for _, m := range t.Modules {
+		if strings.TrimSpace(m.Name) == "" {
+			return nil, ErrInvalidTable
+		}
+		for _, r := range m.Beta {
+			if len(r) == 0 || len(r) > 2 {
+				return nil, fmt.Errorf("%w: module %s has a bad beta range", ErrInvalidTable, m.Name)
+			}
+		}
+	}
+	return &t, nil
+}
+
+// Bundled returns the table built into the launcher.
+func Bundled() *Table {
+	t, err := ParseTable(bundledTable)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Load returns the table at overridePath when it parses and is newer than the bundled table, and the
+// bundled table otherwise.
+func Load(overridePath string) *Table {
+	t := Bundled()
+	if overridePath == "" {
+		return t
+	}
+	b, err := os.ReadFile(overridePath)
+	if err != nil {
+		return t
+	}
+	if o, err := ParseTable(b); err == nil && o.Revision > t.Revision {
+		return o
+	}
+	return t
+}
+
+func (t *Table) module(name string, uuid string) (Module, bool) {
+	for _, m := range t.Modules {
+		if (name != "" && strings.EqualFold(m.Name, name)) || (uuid != "" && m.UUID != "" && strings.EqualFold(m.UUID, uuid)) {
+			return m, true
+		}
+	}
+	return Module{}, false
+}
+
+// moduleVersion returns the version of a manifest dependency as a string, accepting both the
+// "1.2.0-beta" form and the [1, 2, 0] array form.
+func moduleVersion(v any) (string, bool) {
+	switch x := v.(type) {
+	case string:
+		s := strings.TrimSpace(x)
+		core, _, _ := strings.Cut(s, "-")
+		parts := strings.Split(core, ".")
+		if len(parts) != 3 {
+			return s, false
+		}
+		for _, p := range parts {
+			if _, err := strconv.Atoi(p); err != nil {
+				return s, false
+			}
+		}
+		return s, true
+	case []any:
+		if len(x) != 3 {
+			return fmt.Sprint(x), false
+		}
+		parts := make([]string, 3)
+		for i, n := range x {
+			f, ok := n.(float64)
+			if !ok {
+				return fmt.Sprint(x), false
+			}
+			parts[i] = strconv.Itoa(int(f))
+		}
+		return strings.Join(parts, "."), true
+	}
+	return "", false
+}
+
+// CheckDependency checks one script module dependency against a game version. betaAPIs reports whether
+// the world has the Beta APIs experiment; pass true when no world is being checked.
+func (t *Table) CheckDependency(name string, uuid string, version any, gameVersion string, betaAPIs bool) []types.ScriptModuleIssue {
+	var issues []types.ScriptModuleIssue
+	m, ok := t.module(name, uuid)
+	if !ok {
+		return issues
+	}
+	ver, valid := moduleVersion(version)
+	add := func(severity, code, required, msg string) {
+		issues = append(issues, types.ScriptModuleIssue{Severity: severity, Code: code, Module: m.Name, Version: ver, RequiredGame: required, Message: msg})
+	}
+	if !valid {
+		add(SeverityError, CodeVersionInvalid, "", fmt.Sprintf("%s has an invalid version %q", m.Name, ver))
+		return issues
+	}
+	beyondTable := content.CompareGameVersions(gameVersion, t.Game, 3) > 0
+
+	if strings.Contains(ver, "-") {
+		if !betaAPIs {
+			add(SeverityError, CodeBetaAPIsDisabled, "", fmt.Sprintf("%s %s needs the Beta APIs experiment", m.Name, ver))
+		}
+		r, known := m.Beta[ver]
+		switch {
+		case known && content.CompareGameVersions(gameVersion, r[0], 3) >= 0 && content.CompareGameVersions(gameVersion, r[len(r)-1], 3) <= 0:
+		case beyondTable:
+			add(SeverityWarning, CodeVersionUnverified, "", fmt.Sprintf("%s %s cannot be verified for %s", m.Name, ver, gameVersion))
+		case known:
+			add(SeverityError, CodeBetaMismatch, r[0], fmt.Sprintf("%s %s only loads in %s", m.Name, ver, strings.Join(r, " to ")))
+		default:
+			add(SeverityError, CodeVersionUnsupported, "", fmt.Sprintf("%s %s is not supported by %s", m.Name, ver, gameVersion))
+		}
+		return issues
+	}
+
+	since, known := m.Stable[ver]
+	switch {
+	case known && content.CompareGameVersions(gameVersion, since, 3) >= 0:
+	case known:
+		add(SeverityError, CodeVersionTooNew, since, fmt.Sprintf("%s %s needs %s or later", m.Name, ver, since))
+	case beyondTable:
+		add(SeverityWarning, CodeVersionUnverified, "", fmt.Sprintf("%s %s cannot be verified for %s", m.Name, ver, gameVersion))
+	default:
+		add(SeverityError, CodeVersionUnsupported, "", fmt.Sprintf("%s %s is not supported by %s", m.Name, ver, gameVersion))
+	}
+	return issues
+}
+
+// CheckManifest checks the script module dependencies of a behavior pack manifest and returns the issues
+// and the number of script module dependencies. Modules under @minecraft/ missing from the table are
+// reported as warnings; pack dependencies are skipped.
+func (t *Table) CheckManifest(manifest []byte, gameVersion string, betaAPIs bool) ([]types.ScriptModuleIssue, int, error) {
+	var raw struct {
+		Dependencies []map[string]any `json:"dependencies"`
+	}
+	if err := json.Unmarshal(manifest, &raw); err != nil {
+		return nil, 0, err
+	}
+	issues := []types.ScriptModuleIssue{}
+	modules := 0
+	for _, dep := range raw.Dependencies {
+		name, _ := dep["module_name"].(string)
+		uuid, _ := dep["uuid"].(string)
+		name, uuid = strings.TrimSpace(name), strings.TrimSpace(uuid)
+		if _, ok := t.module(name, uuid); !ok {
+			if strings.HasPrefix(name, "@minecraft/") {
+				modules++
+				ver, _ := moduleVersion(dep["version"])
+				issues = append(issues, types.ScriptModuleIssue{Severity: SeverityWarning, Code: CodeModuleUnknown, Module: name, Version: ver, Message: fmt.Sprintf("%s is not in the compatibility table", name)})
+			}
+			continue
+		}
+		modules++
+		issues = append(issues, t.CheckDependency(name, uuid, dep["version"], gameVersion, betaAPIs)...)
+	}
+	return issues, modules, nil
+}
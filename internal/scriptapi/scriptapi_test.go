@@ -0,0 +1,62 @@
+package scriptapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckManifest(t *testing.T) {
+	table := Bundled()
+	manifest := []byte(`{
+		"format_version": 2,
+		"dependencies": [
+			{"module_name": "@minecraft/server", "version": "1.16.0"},
+			{"uuid": "2bd50a27-ab5f-4f40-a596-3641627c635e", "version": [1, 2, 0]},
+			{"uuid": "00000000-0000-0000-0000-000000000000", "version": [1, 0, 0]}
+		]
+	}`)
+	issues, modules, err := table.CheckManifest(manifest, "1.21.50.7", true)
+	if err != nil || modules != 2 || len(issues) != 0 {
+		t.Fatalf("1.21.50: modules=%d issues=%+v err=%v", modules, issues, err)
+	}
+	issues, _, _ = table.CheckManifest(manifest, "1.21.40.3", true)
+	if len(issues) != 1 || issues[0].Code != CodeVersionTooNew || issues[0].RequiredGame != "1.21.50" {
+		t.Fatalf("1.21.40: issues=%+v", issues)
+	}
+
+	beta := []byte(`{"dependencies": [{"module_name": "@minecraft/server", "version": "1.17.0-beta"}]}`)
+	if issues, _, _ := table.CheckManifest(beta, "1.21.50.7", true); len(issues) != 0 {
+		t.Fatalf("matching beta: %+v", issues)
+	}
+	issues, _, _ = table.CheckManifest(beta, "1.21.60.10", false)
+	if len(issues) != 2 || issues[0].Code != CodeBetaAPIsDisabled || issues[1].Code != CodeBetaMismatch {
+		t.Fatalf("mismatched beta: %+v", issues)
+	}
+	issues, _, _ = table.CheckManifest(beta, "9.0.0", true)
+	if len(issues) != 1 || issues[0].Code != CodeVersionUnverified {
+		t.Fatalf("beyond table: %+v", issues)
+	}
+
+	unknown := []byte(`{"dependencies": [{"module_name": "@minecraft/server", "version": "9.0.0"}, {"module_name": "@minecraft/server-foo", "version": "1.0.0"}]}`)
+	issues, modules, _ = table.CheckManifest(unknown, "1.21.50", true)
+	if modules != 2 || len(issues) != 2 || issues[0].Code != CodeVersionUnsupported || issues[1].Code != CodeModuleUnknown {
+		t.Fatalf("unknown: %+v", issues)
+	}
+}
+
+func TestLoadPrefersNewerTable(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "table.json")
+	if got := Load(p).Revision; got != Bundled().Revision {
+		t.Fatalf("missing override: revision %d", got)
+	}
+	os.WriteFile(p, []byte(`{"revision": 1000, "game": "2.0.0", "modules": [{"name": "@minecraft/server", "stable": {"1.0.0": "1.19.50"}}]}`), 0o644)
+	if got := Load(p); got.Revision != 1000 || got.Game != "2.0.0" {
+		t.Fatalf("newer override not used: %+v", got)
+	}
+	os.WriteFile(p, []byte(`{"revision": 1000, "modules": []}`), 0o644)
+	if got := Load(p).Revision; got != Bundled().Revision {
+		t.Fatalf("invalid override used: revision %d", got)
+	}
+}
@@ -0,0 +1,240 @@
+// Package structure reads Bedrock .mcstructure files.
+//
+// A structure is a little-endian NBT compound holding its size, a block palette, one or two layers of
+// palette indexes and the entities it captured. The same compound is stored in world databases under
+// structuretemplate_ keys for structures saved with a structure block.
+package structure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/nbt"
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+const (
+	// Ext is the file extension of structure files.
+	Ext = ".mcstructure"
+	// DBKeyPrefix prefixes the world database keys of saved structures.
+	DBKeyPrefix = "structuretemplate_"
+	// DefaultNamespace is the namespace of structures stored directly in a structures folder.
+	DefaultNamespace = "mystructure"
+)
+
+var ErrInvalidStructure = errors.New("structure: invalid structure")
+
+// ParseIdentifier splits an identifier into namespace and name. A missing namespace means
+// DefaultNamespace. Names may contain slashes for nested folders.
+func ParseIdentifier(id string) (string, string, bool) {
+	ns, name, ok := strings.Cut(strings.TrimSpace(id), ":")
+	if !ok {
+		ns, name = DefaultNamespace, ns
+	}
+	if !validPart(ns, false) || !validPart(name, true) {
+		return "", "", false
+	}
+	return ns, name, true
+}
+
+func validPart(s string, slashes bool) bool {
+	if s == "" {
+		return false
+	}
+	for _, seg := range strings.Split(s, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return false
+		}
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+		case r == '/' && slashes:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// RelPath returns the path of a structure below a structures folder: <namespace>/<name>.mcstructure,
+// or just <name>.mcstructure in the default namespace.
+func RelPath(id string) (string, bool) {
+	ns, name, ok := ParseIdentifier(id)
+	if !ok {
+		return "", false
+	}
+	if ns == DefaultNamespace {
+		return filepath.FromSlash(name + Ext), true
+	}
+	return filepath.FromSlash(ns + "/" + name + Ext), true
+}
+
+// IdentifierOf returns the identifier of the structure file at rel, a path below a structures folder.
+func IdentifierOf(rel string) string {
+	rel = strings.TrimSuffix(filepath.ToSlash(rel), Ext)
+	ns, name, ok := strings.Cut(rel, "/")
+	if !ok {
+		return DefaultNamespace + ":" + rel
+	}
+	return ns + ":" + name
+}
+
+// List returns the structure files below the structures folder dir, sorted by identifier.
+func List(dir string) []types.StructureEntry {
+	out := []types.StructureEntry{}
+	_ = filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(p), Ext) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return nil
+		}
+		e := types.StructureEntry{Identifier: IdentifierOf(rel), Source: "file", Path: p}
+		if fi, err := d.Info(); err == nil {
+			e.Size = fi.Size()
+		}
+		out = append(out, e)
+		return nil
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].Identifier < out[j].Identifier })
+	return out
+}
+
+func ints(v any) []int {
+	switch s := v.(type) {
+	case []int32:
+		out := make([]int, len(s))
+		for i, n := range s {
+			out[i] = int(n)
+		}
+		return out
+	case []any:
+		out := make([]int, 0, len(s))
+		for _, n := range s {
+			if i, ok := n.(int32); ok {
+				out = append(out, int(i))
+			}
+		}
+		return out
+	}
+	if rv := reflect.ValueOf(v); rv.IsValid() && rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Int32 {
+		out := make([]int, rv.Len())
+		for i := range out {
+			out[i] = int(rv.Index(i).Int())
+		}
+		return out
+	}
+	return nil
+}
+
+func floats(v any) []float32 {
+	var out []float32
+	switch s := v.(type) {
+	case []float32:
+		return s
+	case []any:
+		for _, f := range s {
+			if x, ok := f.(float32); ok {
+				out = append(out, x)
+			}
+		}
+	}
+	return out
+}
+
+// Decode summarizes a structure: its size and origin, the palette with the number of blocks of each
+// entry, and the captured entities. Palette index -1 in the block layer is structure void.
+func Decode(data []byte) (types.StructureInfo, error) {
+	info := types.StructureInfo{Palette: []types.StructurePaletteEntry{}, Entities: []types.StructureEntity{}}
+	var root map[string]any
+	if err := nbt.UnmarshalEncoding(data, &root, nbt.LittleEndian); err != nil {
+		return info, err
+	}
+	info.FormatVersion, _ = root["format_version"].(int32)
+	size := ints(root["size"])
+	if len(size) != 3 || size[0] < 0 || size[1] < 0 || size[2] < 0 {
+		return info, fmt.Errorf("%w: bad size", ErrInvalidStructure)
+	}
+	info.Size = [3]int32{int32(size[0]), int32(size[1]), int32(size[2])}
+	info.Volume = size[0] * size[1] * size[2]
+	if origin := ints(root["structure_world_origin"]); len(origin) == 3 {
+		info.Origin = [3]int32{int32(origin[0]), int32(origin[1]), int32(origin[2])}
+	}
+	st, ok := root["structure"].(map[string]any)
+	if !ok {
+		return info, fmt.Errorf("%w: missing structure compound", ErrInvalidStructure)
+	}
+
+	if pal, ok := st["palette"].(map[string]any); ok {
+		if def, ok := pal["default"].(map[string]any); ok {
+			entries, _ := def["block_palette"].([]any)
+			for _, e := range entries {
+				m, _ := e.(map[string]any)
+				name, _ := m["name"].(string)
+				states, _ := m["states"].(map[string]any)
+				if states == nil {
+					states = map[string]any{}
+				}
+				info.Palette = append(info.Palette, types.StructurePaletteEntry{Name: name, States: states})
+			}
+			if pos, ok := def["block_position_data"].(map[string]any); ok {
+				for _, v := range pos {
+					if m, ok := v.(map[string]any); ok {
+						if _, ok := m["block_entity_data"]; ok {
+							info.BlockEntities++
+						}
+					}
+				}
+			}
+		}
+	}
+
+	layers, _ := st["block_indices"].([]any)
+	if len(layers) > 0 {
+		primary := ints(layers[0])
+		if len(primary) != info.Volume {
+			return info, fmt.Errorf("%w: %d block indices for volume %d", ErrInvalidStructure, len(primary), info.Volume)
+		}
+		for _, idx := range primary {
+			switch {
+			case idx < 0:
+				info.VoidCount++
+			case idx < len(info.Palette):
+				info.Palette[idx].Count++
+				if n := info.Palette[idx].Name; n != "minecraft:air" && n != "air" {
+					info.BlockCount++
+				}
+			default:
+				return info, fmt.Errorf("%w: palette index %d out of range", ErrInvalidStructure, idx)
+			}
+		}
+	}
+
+	entities, _ := st["entities"].([]any)
+	for _, e := range entities {
+		m, _ := e.(map[string]any)
+		ent := types.StructureEntity{}
+		ent.Identifier, _ = m["identifier"].(string)
+		if pos := floats(m["Pos"]); len(pos) == 3 {
+			ent.Pos = [3]float32{pos[0], pos[1], pos[2]}
+		}
+		info.Entities = append(info.Entities, ent)
+	}
+	return info, nil
+}
+
+// ReadFile decodes the structure file at path.
+func ReadFile(path string) (types.StructureInfo, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return types.StructureInfo{}, err
+	}
+	return Decode(b)
+}
@@ -0,0 +1,93 @@
+package structure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/nbt"
+)
+
+func testStructure(t *testing.T) []byte {
+	t.Helper()
+	root := map[string]any{
+		"format_version": int32(1),
+		"size":           []int32{2, 1, 2},
+		"structure": map[string]any{
+			"block_indices": []any{[]int32{0, 1, 1, -1}, []int32{-1, -1, -1, -1}},
+			"entities": []any{
+				map[string]any{"identifier": "minecraft:pig", "Pos": []float32{1.5, 64, 2.5}},
+			},
+			"palette": map[string]any{
+				"default": map[string]any{
+					"block_palette": []any{
+						map[string]any{"name": "minecraft:air", "states": map[string]any{}, "version": int32(1)},
+						map[string]any{"name": "minecraft:chest", "states": map[string]any{"facing_direction": int32(2)}, "version": int32(1)},
+					},
+					"block_position_data": map[string]any{
+						"1": map[string]any{"block_entity_data": map[string]any{"id": "Chest"}},
+					},
+				},
+			},
+		},
+		"structure_world_origin": []int32{10, 64, -5},
+	}
+	b, err := nbt.MarshalEncoding(root, nbt.LittleEndian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestDecodeSummarizesStructure(t *testing.T) {
+	info, err := Decode(testStructure(t))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if info.Size != [3]int32{2, 1, 2} || info.Origin != [3]int32{10, 64, -5} || info.Volume != 4 {
+		t.Fatalf("geometry = %+v", info)
+	}
+	if info.BlockCount != 2 || info.VoidCount != 1 || info.BlockEntities != 1 {
+		t.Fatalf("counts = blocks %d, void %d, block entities %d", info.BlockCount, info.VoidCount, info.BlockEntities)
+	}
+	if len(info.Palette) != 2 || info.Palette[1].Name != "minecraft:chest" || info.Palette[1].Count != 2 || info.Palette[0].Count != 1 {
+		t.Fatalf("palette = %+v", info.Palette)
+	}
+	if len(info.Entities) != 1 || info.Entities[0].Identifier != "minecraft:pig" || info.Entities[0].Pos[1] != 64 {
+		t.Fatalf("entities = %+v", info.Entities)
+	}
+	if _, err := Decode([]byte{1, 2, 3}); err == nil {
+		t.Fatal("expected an error for junk data")
+	}
+}
+
+func TestIdentifiersAndList(t *testing.T) {
+	for id, want := range map[string]string{
+		"house":            "house.mcstructure",
+		"mystructure:barn": "barn.mcstructure",
+		"village:homes/a":  filepath.Join("village", "homes", "a.mcstructure"),
+	} {
+		got, ok := RelPath(id)
+		if !ok || got != want {
+			t.Errorf("RelPath(%q) = %q, %v; want %q", id, got, ok, want)
+		}
+	}
+	for _, bad := range []string{"", "a:", "../x", "ns:a/../b", "bad ns:x", "a:b:c"} {
+		if _, ok := RelPath(bad); ok {
+			t.Errorf("RelPath(%q) accepted", bad)
+		}
+	}
+
+	dir := t.TempDir()
+	for _, rel := range []string{"barn.mcstructure", filepath.Join("village", "homes", "a.mcstructure"), "notes.txt"} {
+		p := filepath.Join(dir, rel)
+		os.MkdirAll(filepath.Dir(p), 0o755)
+		if err := os.WriteFile(p, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list := List(dir)
+	if len(list) != 2 || list[0].Identifier != "mystructure:barn" || list[1].Identifier != "village:homes/a" {
+		t.Fatalf("List = %+v", list)
+	}
+}
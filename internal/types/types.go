@@ -235,6 +235,36 @@ type PackInfo struct {
 	Path             string `json:"path"`
 }
 
+type SkinInfo struct {
+	LocalizationName string `json:"localizationName"`
+	DisplayName      string `json:"displayName"`
+	Geometry         string `json:"geometry"`
+	Texture          string `json:"texture"`
+	TexturePath      string `json:"texturePath"`
+	Type             string `json:"type"`
+}
+
+type SkinPackInfo struct {
+	Path             string     `json:"path"`
+	Name             string     `json:"name"`
+	UUID             string     `json:"uuid"`
+	Version          string     `json:"version"`
+	SerializeName    string     `json:"serializeName"`
+	LocalizationName string     `json:"localizationName"`
+	Skins            []SkinInfo `json:"skins"`
+}
+
+type SkinPackBuildSkin struct {
+	Name        string `json:"name"`
+	TexturePath string `json:"texturePath"`
+	Slim        bool   `json:"slim"`
+}
+
+type SkinPackBuildRequest struct {
+	Name  string              `json:"name"`
+	Skins []SkinPackBuildSkin `json:"skins"`
+}
+
 type LevelDatField struct {
 	Name        string   `json:"name"`
 	Tag         string   `json:"tag"`
@@ -289,3 +319,756 @@ type LIPPackageInstallStateEntry struct {
 	IdentifierKey string                 `json:"identifierKey"`
 	State         LIPPackageInstallState `json:"state"`
 }
+
+type PackDuplicateEntry struct {
+	Path        string `json:"path"`
+	Location    string `json:"location"`
+	VersionName string `json:"versionName"`
+	RootName    string `json:"rootName"`
+	FolderName  string `json:"folderName"`
+	Name        string `json:"name"`
+	UUID        string `json:"uuid"`
+	Version     string `json:"version"`
+	ContentHash string `json:"contentHash"`
+	Size        int64  `json:"size"`
+	IsLink      bool   `json:"isLink"`
+	LinkTarget  string `json:"linkTarget"`
+}
+
+type PackDuplicateGroup struct {
+	ID          string               `json:"id"`
+	Kind        string               `json:"kind"`
+	UUID        string               `json:"uuid"`
+	Version     string               `json:"version"`
+	Name        string               `json:"name"`
+	SameContent bool                 `json:"sameContent"`
+	WastedBytes int64                `json:"wastedBytes"`
+	Entries     []PackDuplicateEntry `json:"entries"`
+}
+
+type PackDuplicateReport struct {
+	ScannedLocations int                  `json:"scannedLocations"`
+	ScannedPacks     int                  `json:"scannedPacks"`
+	WastedBytes      int64                `json:"wastedBytes"`
+	Groups           []PackDuplicateGroup `json:"groups"`
+	ErrorCode        string               `json:"errorCode"`
+}
+
+type PackDuplicateConsolidateRequest struct {
+	KeepPath string   `json:"keepPath"`
+	Paths    []string `json:"paths"`
+	Mode     string   `json:"mode"`
+}
+
+type PackDuplicateConsolidateItem struct {
+	Path       string `json:"path"`
+	Status     string `json:"status"`
+	FreedBytes int64  `json:"freedBytes"`
+	ErrorCode  string `json:"errorCode"`
+}
+
+type PackDuplicateConsolidateResult struct {
+	Items      []PackDuplicateConsolidateItem `json:"items"`
+	FreedBytes int64                          `json:"freedBytes"`
+	ErrorCode  string                         `json:"errorCode"`
+}
+
+type LibraryPack struct {
+	Path       string   `json:"path"`
+	RootName   string   `json:"rootName"`
+	FolderName string   `json:"folderName"`
+	Name       string   `json:"name"`
+	UUID       string   `json:"uuid"`
+	Version    string   `json:"version"`
+	Size       int64    `json:"size"`
+	AttachedTo []string `json:"attachedTo"`
+}
+
+type LibraryLink struct {
+	Location    string `json:"location"`
+	VersionName string `json:"versionName"`
+	RootName    string `json:"rootName"`
+	Path        string `json:"path"`
+	Target      string `json:"target"`
+	InLibrary   bool   `json:"inLibrary"`
+	Broken      bool   `json:"broken"`
+}
+
+type WorldDBRecord struct {
+	Key   string `json:"key"`
+	Kind  string `json:"kind"`
+	Label string `json:"label"`
+	Size  int    `json:"size"`
+}
+
+type WorldDBRecordPage struct {
+	Records   []WorldDBRecord `json:"records"`
+	Total     int             `json:"total"`
+	Counts    map[string]int  `json:"counts"`
+	ErrorCode string          `json:"errorCode"`
+}
+
+type WorldDBRecordValue struct {
+	Record    WorldDBRecord    `json:"record"`
+	NBT       []map[string]any `json:"nbt"`
+	Raw       string           `json:"raw"`
+	Truncated bool             `json:"truncated"`
+	ErrorCode string           `json:"errorCode"`
+}
+
+type WorldDimensionStats struct {
+	Dimension int32 `json:"dimension"`
+	Chunks    int   `json:"chunks"`
+	MinX      int32 `json:"minX"`
+	MaxX      int32 `json:"maxX"`
+	MinZ      int32 `json:"minZ"`
+	MaxZ      int32 `json:"maxZ"`
+	Bytes     int64 `json:"bytes"`
+}
+
+type WorldStats struct {
+	LevelName         string                `json:"levelName"`
+	LastPlayed        int64                 `json:"lastPlayed"`
+	GameVersion       string                `json:"gameVersion"`
+	Dimensions        []WorldDimensionStats `json:"dimensions"`
+	Chunks            int                   `json:"chunks"`
+	Actors            int                   `json:"actors"`
+	BlockEntities     int                   `json:"blockEntities"`
+	Records           map[string]int        `json:"records"`
+	RecordBytes       map[string]int64      `json:"recordBytes"`
+	TotalSize         int64                 `json:"totalSize"`
+	DBSize            int64                 `json:"dbSize"`
+	ResourcePacksSize int64                 `json:"resourcePacksSize"`
+	BehaviorPacksSize int64                 `json:"behaviorPacksSize"`
+	ErrorCode         string                `json:"errorCode"`
+}
+
+type WorldPruneArea struct {
+	Dimension int32 `json:"dimension"`
+	CenterX   int32 `json:"centerX"`
+	CenterZ   int32 `json:"centerZ"`
+	Radius    int32 `json:"radius"`
+	MinX      int32 `json:"minX"`
+	MinZ      int32 `json:"minZ"`
+	MaxX      int32 `json:"maxX"`
+	MaxZ      int32 `json:"maxZ"`
+}
+
+type WorldPruneRequest struct {
+	WorldDir string           `json:"worldDir"`
+	Areas    []WorldPruneArea `json:"areas"`
+	DryRun   bool             `json:"dryRun"`
+}
+
+type WorldPruneResult struct {
+	DryRun     bool   `json:"dryRun"`
+	Chunks     int    `json:"chunks"`
+	KeptChunks int    `json:"keptChunks"`
+	Records    int    `json:"records"`
+	Actors     int    `json:"actors"`
+	Bytes      int64  `json:"bytes"`
+	SizeBefore int64  `json:"sizeBefore"`
+	SizeAfter  int64  `json:"sizeAfter"`
+	BackupPath string `json:"backupPath"`
+	ErrorCode  string `json:"errorCode"`
+}
+
+type WorldItemStack struct {
+	Slot   int            `json:"slot"`
+	Name   string         `json:"name"`
+	Count  int            `json:"count"`
+	Damage int            `json:"damage"`
+	Tag    map[string]any `json:"tag,omitempty"`
+}
+
+type WorldPlayerAbilities struct {
+	Flying           bool    `json:"flying"`
+	MayFly           bool    `json:"mayFly"`
+	Instabuild       bool    `json:"instabuild"`
+	Invulnerable     bool    `json:"invulnerable"`
+	Lightning        bool    `json:"lightning"`
+	Build            bool    `json:"build"`
+	Mine             bool    `json:"mine"`
+	DoorsAndSwitches bool    `json:"doorsAndSwitches"`
+	OpenContainers   bool    `json:"openContainers"`
+	AttackPlayers    bool    `json:"attackPlayers"`
+	AttackMobs       bool    `json:"attackMobs"`
+	Op               bool    `json:"op"`
+	Teleport         bool    `json:"teleport"`
+	FlySpeed         float32 `json:"flySpeed"`
+	WalkSpeed        float32 `json:"walkSpeed"`
+}
+
+type WorldPlayerAttribute struct {
+	Name    string  `json:"name"`
+	Base    float32 `json:"base"`
+	Current float32 `json:"current"`
+	Min     float32 `json:"min"`
+	Max     float32 `json:"max"`
+}
+
+type WorldPlayer struct {
+	Key           string                 `json:"key"`
+	Local         bool                   `json:"local"`
+	Pos           [3]float32             `json:"pos"`
+	Rotation      [2]float32             `json:"rotation"`
+	Dimension     int32                  `json:"dimension"`
+	GameMode      int32                  `json:"gameMode"`
+	Level         int32                  `json:"level"`
+	LevelProgress float32                `json:"levelProgress"`
+	Inventory     []WorldItemStack       `json:"inventory"`
+	Armor         []WorldItemStack       `json:"armor"`
+	Offhand       []WorldItemStack       `json:"offhand"`
+	EnderChest    []WorldItemStack       `json:"enderChest"`
+	Abilities     WorldPlayerAbilities   `json:"abilities"`
+	Attributes    []WorldPlayerAttribute `json:"attributes"`
+}
+
+type WorldPlayerWriteResult struct {
+	BackupPath string `json:"backupPath"`
+	ErrorCode  string `json:"errorCode"`
+}
+
+type GameRule struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Since       string `json:"since"`
+	BoolValue   bool   `json:"boolValue"`
+	IntValue    int32  `json:"intValue"`
+	DefaultBool bool   `json:"defaultBool"`
+	DefaultInt  int32  `json:"defaultInt"`
+	Min         int32  `json:"min"`
+	Max         int32  `json:"max"`
+	Present     bool   `json:"present"`
+}
+
+type WorldExperiment struct {
+	Name    string `json:"name"`
+	Title   string `json:"title"`
+	Since   string `json:"since"`
+	Enabled bool   `json:"enabled"`
+	Present bool   `json:"present"`
+}
+
+type WorldGameRules struct {
+	Rules       []GameRule        `json:"rules"`
+	Experiments []WorldExperiment `json:"experiments"`
+	ErrorCode   string            `json:"errorCode"`
+}
+
+type WorldCloneRequest struct {
+	SourceVersionName string `json:"sourceVersionName"`
+	SourcePlayer      string `json:"sourcePlayer"`
+	SourceWorldPath   string `json:"sourceWorldPath"`
+	TargetVersionName string `json:"targetVersionName"`
+	TargetPlayer      string `json:"targetPlayer"`
+	FolderName        string `json:"folderName"`
+	LevelName         string `json:"levelName"`
+	NewSeed           bool   `json:"newSeed"`
+	ResetPlayers      bool   `json:"resetPlayers"`
+	ResetTime         bool   `json:"resetTime"`
+}
+
+type WorldCloneResult struct {
+	Path       string `json:"path"`
+	FolderName string `json:"folderName"`
+	LevelName  string `json:"levelName"`
+	Seed       int64  `json:"seed"`
+	ErrorCode  string `json:"errorCode"`
+}
+
+type WorldSnapshot struct {
+	ID         string `json:"id"`
+	WorldID    string `json:"worldId"`
+	WorldName  string `json:"worldName"`
+	SourcePath string `json:"sourcePath"`
+	Label      string `json:"label"`
+	CreatedAt  int64  `json:"createdAt"`
+	Files      int    `json:"files"`
+	Size       int64  `json:"size"`
+	NewBytes   int64  `json:"newBytes"`
+}
+
+type WorldSnapshotResult struct {
+	Snapshot   WorldSnapshot `json:"snapshot"`
+	Unchanged  bool          `json:"unchanged"`
+	Pruned     []string      `json:"pruned"`
+	FreedBytes int64         `json:"freedBytes"`
+	ErrorCode  string        `json:"errorCode"`
+}
+
+type WorldBackupRetention struct {
+	KeepLast   int `json:"keepLast"`
+	KeepDaily  int `json:"keepDaily"`
+	KeepWeekly int `json:"keepWeekly"`
+}
+
+type WorldSnapshotPathResult struct {
+	Path      string `json:"path"`
+	ErrorCode string `json:"errorCode"`
+}
+
+type WorldBackupFile struct {
+	Path        string `json:"path"`
+	FileName    string `json:"fileName"`
+	VersionName string `json:"versionName"`
+	WorldKey    string `json:"worldKey"`
+	FolderName  string `json:"folderName"`
+	LevelName   string `json:"levelName"`
+	CreatedAt   int64  `json:"createdAt"`
+	LastPlayed  int64  `json:"lastPlayed"`
+	Size        int64  `json:"size"`
+	Readable    bool   `json:"readable"`
+}
+
+type WorldBackupGroup struct {
+	VersionName string            `json:"versionName"`
+	WorldKey    string            `json:"worldKey"`
+	LevelName   string            `json:"levelName"`
+	TotalSize   int64             `json:"totalSize"`
+	Backups     []WorldBackupFile `json:"backups"`
+}
+
+type WorldBackupLevelDat struct {
+	Path      string          `json:"path"`
+	LevelName string          `json:"levelName"`
+	Version   int32           `json:"version"`
+	Fields    []LevelDatField `json:"fields"`
+	ErrorCode string          `json:"errorCode"`
+}
+
+type WorldBackupRestoreRequest struct {
+	Path        string `json:"path"`
+	VersionName string `json:"versionName"`
+	Player      string `json:"player"`
+	FolderName  string `json:"folderName"`
+}
+
+type WorldBackupRestoreResult struct {
+	WorldDir   string `json:"worldDir"`
+	FolderName string `json:"folderName"`
+	Renamed    bool   `json:"renamed"`
+	ErrorCode  string `json:"errorCode"`
+}
+
+type WorldBackupPruneRequest struct {
+	VersionName string `json:"versionName"`
+	WorldKey    string `json:"worldKey"`
+	MaxAgeDays  int    `json:"maxAgeDays"`
+	KeepLast    int    `json:"keepLast"`
+	DryRun      bool   `json:"dryRun"`
+}
+
+type WorldBackupPruneResult struct {
+	Removed    []string `json:"removed"`
+	FreedBytes int64    `json:"freedBytes"`
+	ErrorCode  string   `json:"errorCode"`
+}
+
+type WorldMapRequest struct {
+	WorldDir  string `json:"worldDir"`
+	Dimension int32  `json:"dimension"`
+	MinX      int32  `json:"minX"`
+	MinZ      int32  `json:"minZ"`
+	MaxX      int32  `json:"maxX"`
+	MaxZ      int32  `json:"maxZ"`
+	TileSize  int    `json:"tileSize"`
+}
+
+type WorldMapTile struct {
+	MinX int32  `json:"minX"`
+	MinZ int32  `json:"minZ"`
+	MaxX int32  `json:"maxX"`
+	MaxZ int32  `json:"maxZ"`
+	Path string `json:"path"`
+}
+
+type WorldMapResult struct {
+	Tiles                []WorldMapTile `json:"tiles"`
+	Chunks               int            `json:"chunks"`
+	MissingChunks        int            `json:"missingChunks"`
+	UnsupportedSubChunks int            `json:"unsupportedSubChunks"`
+	ErrorCode            string         `json:"errorCode"`
+}
+
+type StructurePaletteEntry struct {
+	Name   string         `json:"name"`
+	States map[string]any `json:"states"`
+	Count  int            `json:"count"`
+}
+
+type StructureEntity struct {
+	Identifier string     `json:"identifier"`
+	Pos        [3]float32 `json:"pos"`
+}
+
+type StructureInfo struct {
+	Identifier    string                  `json:"identifier"`
+	FormatVersion int32                   `json:"formatVersion"`
+	Size          [3]int32                `json:"size"`
+	Origin        [3]int32                `json:"origin"`
+	Volume        int                     `json:"volume"`
+	BlockCount    int                     `json:"blockCount"`
+	VoidCount     int                     `json:"voidCount"`
+	BlockEntities int                     `json:"blockEntities"`
+	Palette       []StructurePaletteEntry `json:"palette"`
+	Entities      []StructureEntity       `json:"entities"`
+	ErrorCode     string                  `json:"errorCode"`
+}
+
+type StructureEntry struct {
+	Identifier string `json:"identifier"`
+	Source     string `json:"source"`
+	Path       string `json:"path"`
+	Size       int64  `json:"size"`
+}
+
+type StructureImportResult struct {
+	Identifier string `json:"identifier"`
+	Path       string `json:"path"`
+	ErrorCode  string `json:"errorCode"`
+}
+
+type StructureExportResult struct {
+	Paths     []string `json:"paths"`
+	ErrorCode string   `json:"errorCode"`
+}
+
+type WorldIndexEntry struct {
+	Path        string `json:"path"`
+	FolderName  string `json:"folderName"`
+	LevelName   string `json:"levelName"`
+	Seed        int64  `json:"seed"`
+	LastPlayed  int64  `json:"lastPlayed"`
+	GameVersion string `json:"gameVersion"`
+	Size        int64  `json:"size"`
+	Location    string `json:"location"`
+	VersionName string `json:"versionName"`
+	Player      string `json:"player"`
+	Stamp       int64  `json:"stamp"`
+}
+
+type WorldSearchQuery struct {
+	Query       string `json:"query"`
+	VersionName string `json:"versionName"`
+	Location    string `json:"location"`
+	SortBy      string `json:"sortBy"`
+	Desc        bool   `json:"desc"`
+	Offset      int    `json:"offset"`
+	Limit       int    `json:"limit"`
+}
+
+type WorldSearchResult struct {
+	Entries   []WorldIndexEntry `json:"entries"`
+	Total     int               `json:"total"`
+	UpdatedAt int64             `json:"updatedAt"`
+	ErrorCode string            `json:"errorCode"`
+}
+
+type WorldIndexRefreshResult struct {
+	Worlds    int    `json:"worlds"`
+	Updated   int    `json:"updated"`
+	Removed   int    `json:"removed"`
+	UpdatedAt int64  `json:"updatedAt"`
+	ErrorCode string `json:"errorCode"`
+}
+
+type WorldCompatResult struct {
+	WorldDir         string `json:"worldDir"`
+	VersionName      string `json:"versionName"`
+	WorldVersion     string `json:"worldVersion"`
+	MinClientVersion string `json:"minClientVersion"`
+	TargetVersion    string `json:"targetVersion"`
+	Status           string `json:"status"`
+	BackupPath       string `json:"backupPath"`
+	ErrorCode        string `json:"errorCode"`
+}
+
+type WorldCompatLaunchPrompt struct {
+	VersionName string            `json:"versionName"`
+	Force       bool              `json:"force"`
+	Result      WorldCompatResult `json:"result"`
+}
+
+type ScriptModuleIssue struct {
+	Severity     string `json:"severity"`
+	Code         string `json:"code"`
+	Module       string `json:"module"`
+	Version      string `json:"version"`
+	RequiredGame string `json:"requiredGame,omitempty"`
+	Message      string `json:"message"`
+}
+
+type ScriptPackCompat struct {
+	Path       string              `json:"path"`
+	Name       string              `json:"name"`
+	UUID       string              `json:"uuid"`
+	Version    string              `json:"version"`
+	Compatible bool                `json:"compatible"`
+	Issues     []ScriptModuleIssue `json:"issues"`
+}
+
+type ScriptCompatReport struct {
+	GameVersion     string             `json:"gameVersion"`
+	TableRevision   int                `json:"tableRevision"`
+	TableGame       string             `json:"tableGame"`
+	WorldDir        string             `json:"worldDir"`
+	BetaAPIsEnabled bool               `json:"betaApisEnabled"`
+	Packs           []ScriptPackCompat `json:"packs"`
+	Incompatible    int                `json:"incompatible"`
+	ErrorCode       string             `json:"errorCode"`
+}
+
+type ContentTransferItem struct {
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+}
+
+type ContentTransferResolution struct {
+	ConflictID string `json:"conflictId"`
+	Choice     string `json:"choice"`
+}
+
+type ContentTransferRequest struct {
+	SourceVersionName string                      `json:"sourceVersionName"`
+	SourcePlayer      string                      `json:"sourcePlayer"`
+	TargetVersionName string                      `json:"targetVersionName"`
+	TargetPlayer      string                      `json:"targetPlayer"`
+	Items             []ContentTransferItem       `json:"items"`
+	Resolutions       []ContentTransferResolution `json:"resolutions,omitempty"`
+}
+
+type ContentTransferConflict struct {
+	ID            string   `json:"id"`
+	Kind          string   `json:"kind"`
+	SourcePath    string   `json:"sourcePath"`
+	TargetPath    string   `json:"targetPath"`
+	IdentityKind  string   `json:"identityKind"`
+	IdentityKey   string   `json:"identityKey"`
+	SourceSummary string   `json:"sourceSummary"`
+	TargetSummary string   `json:"targetSummary"`
+	Choices       []string `json:"choices"`
+}
+
+type ContentTransferPreview struct {
+	Conflicts []ContentTransferConflict `json:"conflicts"`
+	ErrorCode string                    `json:"errorCode"`
+}
+
+type ContentTransferItemResult struct {
+	Kind       string `json:"kind"`
+	SourcePath string `json:"sourcePath"`
+	TargetPath string `json:"targetPath"`
+	Status     string `json:"status"`
+	ErrorCode  string `json:"errorCode"`
+}
+
+type ContentTransferResult struct {
+	Status    string                      `json:"status"`
+	Items     []ContentTransferItemResult `json:"items"`
+	ErrorCode string                      `json:"errorCode"`
+}
+
+type ContentTransferProgress struct {
+	Phase   string `json:"phase"`
+	Current int    `json:"current"`
+	Total   int    `json:"total"`
+	Path    string `json:"path,omitempty"`
+	Ts      int64  `json:"ts"`
+}
+
+type IsolationMigrationItem struct {
+	ID            string   `json:"id"`
+	Kind          string   `json:"kind"`
+	Path          string   `json:"path"`
+	Label         string   `json:"label"`
+	Size          int64    `json:"size"`
+	Conflict      bool     `json:"conflict"`
+	TargetPath    string   `json:"targetPath"`
+	TargetSummary string   `json:"targetSummary"`
+	Choices       []string `json:"choices"`
+}
+
+type IsolationMigrationPreview struct {
+	VersionName string                   `json:"versionName"`
+	Enable      bool                     `json:"enable"`
+	SourceRoot  string                   `json:"sourceRoot"`
+	TargetRoot  string                   `json:"targetRoot"`
+	Items       []IsolationMigrationItem `json:"items"`
+	ErrorCode   string                   `json:"errorCode"`
+}
+
+type IsolationMigrationResolution struct {
+	ItemID string `json:"itemId"`
+	Choice string `json:"choice"`
+}
+
+type IsolationMigrationRequest struct {
+	Enable      bool                           `json:"enable"`
+	Move        bool                           `json:"move"`
+	All         bool                           `json:"all"`
+	ItemIDs     []string                       `json:"itemIds"`
+	Resolutions []IsolationMigrationResolution `json:"resolutions,omitempty"`
+}
+
+type IsolationMigrationResult struct {
+	Status      string   `json:"status"`
+	Migrated    int      `json:"migrated"`
+	Skipped     int      `json:"skipped"`
+	SourcesLeft []string `json:"sourcesLeft"`
+	ErrorCode   string   `json:"errorCode"`
+}
+
+type IsolationMigrationProgress struct {
+	Phase   string `json:"phase"`
+	Current int    `json:"current"`
+	Total   int    `json:"total"`
+	Path    string `json:"path,omitempty"`
+	Ts      int64  `json:"ts"`
+}
+
+type GameOptionChoice struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+type GameOptionDef struct {
+	Key     string             `json:"key"`
+	Group   string             `json:"group"`
+	Type    string             `json:"type"`
+	Min     float64            `json:"min"`
+	Max     float64            `json:"max"`
+	Choices []GameOptionChoice `json:"choices,omitempty"`
+}
+
+type GameOption struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type"`
+	Group string `json:"group"`
+	Known bool   `json:"known"`
+}
+
+type GameOptions struct {
+	VersionName string       `json:"versionName"`
+	Player      string       `json:"player"`
+	Path        string       `json:"path"`
+	Exists      bool         `json:"exists"`
+	Options     []GameOption `json:"options"`
+	ErrorCode   string       `json:"errorCode"`
+}
+
+type GameOptionValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type OptionProfile struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Patterns  []string          `json:"patterns"`
+	Options   []GameOptionValue `json:"options"`
+	CreatedAt int64             `json:"createdAt"`
+	UpdatedAt int64             `json:"updatedAt"`
+}
+
+type OptionProfileList struct {
+	DefaultID string          `json:"defaultId"`
+	Profiles  []OptionProfile `json:"profiles"`
+}
+
+type OptionProfileRequest struct {
+	Name   string   `json:"name"`
+	Groups []string `json:"groups"`
+	Keys   []string `json:"keys"`
+}
+
+type OptionProfileResult struct {
+	Profile   OptionProfile `json:"profile"`
+	ErrorCode string        `json:"errorCode"`
+}
+
+type OptionProfilePlayerError struct {
+	Player    string `json:"player"`
+	ErrorCode string `json:"errorCode"`
+}
+
+type PendingOptionProfileResult struct {
+	VersionName string                     `json:"versionName"`
+	ProfileID   string                     `json:"profileId"`
+	Applied     []string                   `json:"applied"`
+	Failed      []OptionProfilePlayerError `json:"failed"`
+	ErrorCode   string                     `json:"errorCode"`
+}
+
+type JavaPackAsset struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+type JavaPackConversion struct {
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	PackFormat    int             `json:"packFormat"`
+	UUID          string          `json:"uuid"`
+	Converted     int             `json:"converted"`
+	Renamed       int             `json:"renamed"`
+	IconConverted bool            `json:"iconConverted"`
+	Untranslated  []JavaPackAsset `json:"untranslated"`
+	ErrorCode     string          `json:"errorCode"`
+}
+
+type PackLintIssue struct {
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Message  string `json:"message"`
+}
+
+type PackLintReport struct {
+	Path          string          `json:"path"`
+	Root          string          `json:"root"`
+	PackType      string          `json:"packType"`
+	Name          string          `json:"name"`
+	UUID          string          `json:"uuid"`
+	Version       string          `json:"version"`
+	FormatVersion int             `json:"formatVersion"`
+	FilesChecked  int             `json:"filesChecked"`
+	ErrorCount    int             `json:"errorCount"`
+	WarningCount  int             `json:"warningCount"`
+	Valid         bool            `json:"valid"`
+	Issues        []PackLintIssue `json:"issues"`
+}
+
+type DevPack struct {
+	ID          string `json:"id"`
+	VersionName string `json:"versionName"`
+	SourcePath  string `json:"sourcePath"`
+	TargetPath  string `json:"targetPath"`
+	PackType    string `json:"packType"`
+	Mode        string `json:"mode"`
+	CreatedAt   int64  `json:"createdAt"`
+	AutoWatch   bool   `json:"autoWatch"`
+	Watching    bool   `json:"watching"`
+}
+
+type DevPackSyncStats struct {
+	Copied    int   `json:"copied"`
+	Removed   int   `json:"removed"`
+	Unchanged int   `json:"unchanged"`
+	Bytes     int64 `json:"bytes"`
+}
+
+type DevPackSyncEvent struct {
+	ID          string           `json:"id"`
+	VersionName string           `json:"versionName"`
+	State       string           `json:"state"`
+	Changed     []string         `json:"changed"`
+	Stats       DevPackSyncStats `json:"stats"`
+	Report      PackLintReport   `json:"report"`
+	ErrorCode   string           `json:"errorCode"`
+	Ts          int64            `json:"ts"`
+}
@@ -202,6 +202,16 @@ func CopyDir(src, dst string) error {
 }
 
 func JsonCompatBytes(data []byte) []byte {
+	return jsonCompatBytes(data, false)
+}
+
+// JsonCompatBytesKeepLines is JsonCompatBytes but keeps the line breaks inside block comments, so an
+// offset into the output lies on the same line as in data.
+func JsonCompatBytesKeepLines(data []byte) []byte {
+	return jsonCompatBytes(data, true)
+}
+
+func jsonCompatBytes(data []byte, keepLines bool) []byte {
 	src := data
 	n := len(src)
 	out := make([]byte, 0, n)
@@ -223,6 +233,8 @@ func JsonCompatBytes(data []byte) []byte {
 			if b == '*' && i+1 < n && src[i+1] == '/' {
 				inBlockComment = false
 				i++
+			} else if keepLines && b == '\n' {
+				out = append(out, b)
 			}
 			continue
 		}
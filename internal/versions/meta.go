@@ -18,18 +18,29 @@ var _ = reflect.TypeOf(VersionMeta{})
 var _ = reflect.TypeOf(metaFileName)
 
 type VersionMeta struct {
-	Name                       string    `json:"name"        `
-	GameVersion                string    `json:"gameVersion"`
-	Type                       string    `json:"type"       `
-	EnableIsolation            bool      `json:"enableIsolation"`
-	EnableConsole              bool      `json:"enableConsole"`
-	EnableEditorMode           bool      `json:"enableEditorMode"`
-	EnableRenderDragon         bool      `json:"enableRenderDragon"`
-	EnableCtrlRReloadResources bool      `json:"enableCtrlRReloadResources"`
-	LaunchArgs                 string    `json:"launchArgs"`
-	EnvVars                    string    `json:"envVars"`
-	CreatedAt                  time.Time `json:"createdAt"`
-	Registered                 bool      `json:"registered,omitempty"`
+	Name                       string           `json:"name"        `
+	GameVersion                string           `json:"gameVersion"`
+	Type                       string           `json:"type"       `
+	EnableIsolation            bool             `json:"enableIsolation"`
+	EnableConsole              bool             `json:"enableConsole"`
+	EnableEditorMode           bool             `json:"enableEditorMode"`
+	EnableRenderDragon         bool             `json:"enableRenderDragon"`
+	EnableCtrlRReloadResources bool             `json:"enableCtrlRReloadResources"`
+	LaunchArgs                 string           `json:"launchArgs"`
+	EnvVars                    string           `json:"envVars"`
+	CreatedAt                  time.Time        `json:"createdAt"`
+	Registered                 bool             `json:"registered,omitempty"`
+	AutoBackup                 AutoBackupPolicy `json:"autoBackup"`
+	PinnedWorld                string           `json:"pinnedWorld,omitempty"`
+	PendingOptionProfile       string           `json:"pendingOptionProfile,omitempty"`
+}
+
+// AutoBackupPolicy controls the world snapshots taken around game sessions of a version. When enabled,
+// worlds played during a session are snapshotted after the game exits, and every IntervalMinutes while
+// it runs if that is positive.
+type AutoBackupPolicy struct {
+	Enabled         bool `json:"enabled"`
+	IntervalMinutes int  `json:"intervalMinutes"`
 }
 
 const metaFileName = "version.json"
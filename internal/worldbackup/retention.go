@@ -0,0 +1,122 @@
+package worldbackup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	json "github.com/goccy/go-json"
+)
+
+// Retention decides which snapshots of a world survive a prune. A snapshot is kept when it is one of the
+// KeepLast newest, the newest of its day within the last KeepDaily days, or the newest of its ISO week
+// within the last KeepWeekly weeks. A policy with every field zero keeps everything.
+type Retention struct {
+	KeepLast   int `json:"keepLast"`
+	KeepDaily  int `json:"keepDaily"`
+	KeepWeekly int `json:"keepWeekly"`
+}
+
+// DefaultRetention keeps the last five snapshots, one a day for a week and one a week for a month.
+func DefaultRetention() Retention {
+	return Retention{KeepLast: 5, KeepDaily: 7, KeepWeekly: 4}
+}
+
+func (r Retention) disabled() bool {
+	return r.KeepLast <= 0 && r.KeepDaily <= 0 && r.KeepWeekly <= 0
+}
+
+// keep returns the ids to keep out of snaps, which must be sorted newest first.
+func (r Retention) keep(snaps []Snapshot, now time.Time) map[string]bool {
+	out := map[string]bool{}
+	if r.disabled() {
+		for _, s := range snaps {
+			out[s.ID] = true
+		}
+		return out
+	}
+	for i := 0; i < len(snaps) && i < r.KeepLast; i++ {
+		out[snaps[i].ID] = true
+	}
+	days := map[string]bool{}
+	weeks := map[string]bool{}
+	dayCutoff := now.AddDate(0, 0, -r.KeepDaily)
+	weekCutoff := now.AddDate(0, 0, -7*r.KeepWeekly)
+	for _, s := range snaps {
+		t := time.Unix(s.CreatedAt, 0).In(now.Location())
+		if r.KeepDaily > 0 && t.After(dayCutoff) {
+			day := t.Format("2006-01-02")
+			if !days[day] {
+				days[day] = true
+				out[s.ID] = true
+			}
+		}
+		if r.KeepWeekly > 0 && t.After(weekCutoff) {
+			y, w := t.ISOWeek()
+			week := fmt.Sprintf("%d-%d", y, w)
+			if !weeks[week] {
+				weeks[week] = true
+				out[s.ID] = true
+			}
+		}
+	}
+	return out
+}
+
+func (s *Store) policyPath(worldID string) string {
+	return filepath.Join(s.Root, "policies", worldID+".json")
+}
+
+// Retention returns the policy of a world, or DefaultRetention when none was set.
+func (s *Store) Retention(worldID string) Retention {
+	r := DefaultRetention()
+	if !validID(worldID) {
+		return r
+	}
+	if b, err := os.ReadFile(s.policyPath(worldID)); err == nil {
+		_ = json.Unmarshal(b, &r)
+	}
+	return r
+}
+
+// SetRetention stores the policy of a world.
+func (s *Store) SetRetention(worldID string, r Retention) error {
+	if !validID(worldID) {
+		return ErrInvalidID
+	}
+	if err := os.MkdirAll(filepath.Dir(s.policyPath(worldID)), 0755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.policyPath(worldID), b, 0644)
+}
+
+// Prune deletes the snapshots of a world that r does not keep and returns their ids. Run GC afterwards
+// to release their objects.
+func (s *Store) Prune(worldID string, r Retention, now time.Time) ([]string, error) {
+	if !validID(worldID) {
+		return nil, ErrInvalidID
+	}
+	unlock := s.lock()
+	defer unlock()
+	snaps, err := s.list(worldID)
+	if err != nil {
+		return nil, err
+	}
+	keep := r.keep(snaps, now)
+	removed := []string{}
+	for _, snap := range snaps {
+		if keep[snap.ID] {
+			continue
+		}
+		if err := os.Remove(s.snapshotPath(worldID, snap.ID)); err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+		removed = append(removed, snap.ID)
+	}
+	return removed, nil
+}
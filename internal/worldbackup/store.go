@@ -0,0 +1,484 @@
+// Package worldbackup keeps incremental, deduplicated snapshots of world folders.
+//
+// Files are stored once per content hash under objects/, and each snapshot is a JSON manifest under
+// snapshots/<world id>/ listing the files of the world at that time. LevelDB table files never change
+// once written, so consecutive snapshots of a world share almost all of their objects. Objects no longer
+// referenced by any snapshot are removed by GC.
+package worldbackup
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+)
+
+var (
+	ErrNotFound    = errors.New("worldbackup: snapshot not found")
+	ErrInvalidID   = errors.New("worldbackup: invalid id")
+	ErrDestExists  = errors.New("worldbackup: destination exists")
+	ErrCorruptSnap = errors.New("worldbackup: snapshot references a missing object")
+)
+
+// FileEntry is one file of a snapshot.
+type FileEntry struct {
+	Path    string `json:"path"`
+	Hash    string `json:"hash"`
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"modTime"`
+}
+
+// Snapshot is the manifest of one backup of a world.
+type Snapshot struct {
+	ID         string      `json:"id"`
+	WorldID    string      `json:"worldId"`
+	WorldName  string      `json:"worldName"`
+	SourcePath string      `json:"sourcePath"`
+	Label      string      `json:"label"`
+	CreatedAt  int64       `json:"createdAt"`
+	Size       int64       `json:"size"`
+	NewBytes   int64       `json:"newBytes"`
+	Files      []FileEntry `json:"files"`
+}
+
+// storeLocks serialises writers per store root, so a GC never races with a snapshot adding objects.
+var storeLocks sync.Map
+
+// Store is a backup store rooted at a directory.
+type Store struct {
+	Root string
+}
+
+func New(root string) *Store {
+	return &Store{Root: root}
+}
+
+func (s *Store) lock() func() {
+	v, _ := storeLocks.LoadOrStore(filepath.Clean(s.Root), &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+func validID(id string) bool {
+	return id != "" && id != "." && id != ".." && !strings.ContainsAny(id, `/\:`)
+}
+
+func (s *Store) objectPath(hash string) string {
+	return filepath.Join(s.Root, "objects", hash[:2], hash)
+}
+
+func (s *Store) worldDir(worldID string) string {
+	return filepath.Join(s.Root, "snapshots", worldID)
+}
+
+func (s *Store) snapshotPath(worldID string, id string) string {
+	return filepath.Join(s.worldDir(worldID), id+".json")
+}
+
+// putFile copies path into the object store, hashing it on the way so the stored object always
+// matches its name even if the file changes meanwhile. It returns the hash and the number of bytes
+// added, which is zero when the object was already stored.
+func (s *Store) putFile(path string) (string, int64, error) {
+	tmpDir := filepath.Join(s.Root, "objects", "tmp")
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return "", 0, err
+	}
+	src, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer src.Close()
+	out, err := os.CreateTemp(tmpDir, "obj-*")
+	if err != nil {
+		return "", 0, err
+	}
+	tmp := out.Name()
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(out, h), src)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return "", 0, err
+	}
+	hash := hex.EncodeToString(h.Sum(nil))
+	dst := s.objectPath(hash)
+	if _, err := os.Stat(dst); err == nil {
+		_ = os.Remove(tmp)
+		return hash, 0, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		_ = os.Remove(tmp)
+		return "", 0, err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return "", 0, err
+	}
+	return hash, n, nil
+}
+
+// Snapshot records the current state of worldDir under worldID. Files whose size and modification time
+// match the latest snapshot reuse its hash instead of being read again. When nothing changed since the
+// latest snapshot, that snapshot is returned with unchanged set and no new snapshot is written.
+func (s *Store) Snapshot(worldDir string, worldID string, worldName string, label string) (Snapshot, bool, error) {
+	if !validID(worldID) {
+		return Snapshot{}, false, ErrInvalidID
+	}
+	unlock := s.lock()
+	defer unlock()
+
+	var prev *Snapshot
+	if list, err := s.list(worldID); err == nil && len(list) > 0 {
+		prev = &list[0]
+	}
+	known := map[string]FileEntry{}
+	if prev != nil {
+		for _, f := range prev.Files {
+			known[f.Path] = f
+		}
+	}
+
+	snap := Snapshot{WorldID: worldID, WorldName: worldName, SourcePath: worldDir, Label: label, Files: []FileEntry{}}
+	err := filepath.WalkDir(worldDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(worldDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		// LevelDB's lock file is held open by the game and carries no data.
+		if rel == "db/LOCK" {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		e := FileEntry{Path: rel, Size: fi.Size(), ModTime: fi.ModTime().UnixNano()}
+		if old, ok := known[rel]; ok && old.Size == e.Size && old.ModTime == e.ModTime {
+			if _, err := os.Stat(s.objectPath(old.Hash)); err == nil {
+				e.Hash = old.Hash
+			}
+		}
+		if e.Hash == "" {
+			hash, n, err := s.putFile(path)
+			if err != nil {
+				return err
+			}
+			e.Hash = hash
+			snap.NewBytes += n
+		}
+		snap.Size += e.Size
+		snap.Files = append(snap.Files, e)
+		return nil
+	})
+	if err != nil {
+		return Snapshot{}, false, err
+	}
+	sort.Slice(snap.Files, func(i, j int) bool { return snap.Files[i].Path < snap.Files[j].Path })
+
+	if prev != nil && sameFiles(prev.Files, snap.Files) {
+		return *prev, true, nil
+	}
+
+	now := time.Now()
+	snap.CreatedAt = now.Unix()
+	base := now.Format("20060102-150405")
+	snap.ID = base
+	for i := 2; ; i++ {
+		if _, err := os.Stat(s.snapshotPath(worldID, snap.ID)); os.IsNotExist(err) {
+			break
+		}
+		snap.ID = fmt.Sprintf("%s-%d", base, i)
+	}
+	if err := s.writeSnapshot(snap); err != nil {
+		return Snapshot{}, false, err
+	}
+	return snap, false, nil
+}
+
+func sameFiles(a []FileEntry, b []FileEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Path != b[i].Path || a[i].Hash != b[i].Hash {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Store) writeSnapshot(snap Snapshot) error {
+	if err := os.MkdirAll(s.worldDir(snap.WorldID), 0755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	p := s.snapshotPath(snap.WorldID, snap.ID)
+	if err := os.WriteFile(p+".tmp", b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(p+".tmp", p)
+}
+
+// Get loads one snapshot.
+func (s *Store) Get(worldID string, id string) (Snapshot, error) {
+	var snap Snapshot
+	if !validID(worldID) || !validID(id) {
+		return snap, ErrInvalidID
+	}
+	b, err := os.ReadFile(s.snapshotPath(worldID, id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snap, ErrNotFound
+		}
+		return snap, err
+	}
+	err = json.Unmarshal(b, &snap)
+	return snap, err
+}
+
+// Worlds returns the ids of the worlds that have snapshots.
+func (s *Store) Worlds() ([]string, error) {
+	ents, err := os.ReadDir(filepath.Join(s.Root, "snapshots"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	out := []string{}
+	for _, e := range ents {
+		if e.IsDir() {
+			out = append(out, e.Name())
+		}
+	}
+	return out, nil
+}
+
+// List returns the snapshots of a world, newest first.
+func (s *Store) List(worldID string) ([]Snapshot, error) {
+	if !validID(worldID) {
+		return nil, ErrInvalidID
+	}
+	return s.list(worldID)
+}
+
+func (s *Store) list(worldID string) ([]Snapshot, error) {
+	ents, err := os.ReadDir(s.worldDir(worldID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Snapshot{}, nil
+		}
+		return nil, err
+	}
+	out := []Snapshot{}
+	for _, e := range ents {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if e.IsDir() || !ok {
+			continue
+		}
+		snap, err := s.Get(worldID, id)
+		if err != nil {
+			continue
+		}
+		out = append(out, snap)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CreatedAt != out[j].CreatedAt {
+			return out[i].CreatedAt > out[j].CreatedAt
+		}
+		return out[i].ID > out[j].ID
+	})
+	return out, nil
+}
+
+// Delete removes a snapshot. Its objects stay until the next GC.
+func (s *Store) Delete(worldID string, id string) error {
+	if !validID(worldID) || !validID(id) {
+		return ErrInvalidID
+	}
+	unlock := s.lock()
+	defer unlock()
+	if err := os.Remove(s.snapshotPath(worldID, id)); err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// GC removes the objects no snapshot refers to and returns how many were removed and their size.
+func (s *Store) GC() (int, int64, error) {
+	unlock := s.lock()
+	defer unlock()
+	live := map[string]struct{}{}
+	worlds, err := s.Worlds()
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, w := range worlds {
+		list, err := s.list(w)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, snap := range list {
+			for _, f := range snap.Files {
+				live[f.Hash] = struct{}{}
+			}
+		}
+	}
+	var removed int
+	var freed int64
+	err = filepath.WalkDir(filepath.Join(s.Root, "objects"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if _, ok := live[d.Name()]; ok {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		freed += fi.Size()
+		return nil
+	})
+	return removed, freed, err
+}
+
+// Restore writes the files of a snapshot into dest, which must not exist yet.
+func (s *Store) Restore(worldID string, id string, dest string) error {
+	snap, err := s.Get(worldID, id)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dest); err == nil {
+		return ErrDestExists
+	}
+	tmp := dest + ".restoring"
+	_ = os.RemoveAll(tmp)
+	for _, f := range snap.Files {
+		p := filepath.Join(tmp, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			_ = os.RemoveAll(tmp)
+			return err
+		}
+		if err := s.copyObject(f, p); err != nil {
+			_ = os.RemoveAll(tmp)
+			return err
+		}
+		mt := time.Unix(0, f.ModTime)
+		_ = os.Chtimes(p, mt, mt)
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+	return nil
+}
+
+func (s *Store) copyObject(f FileEntry, dst string) error {
+	src, err := os.Open(s.objectPath(f.Hash))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrCorruptSnap
+		}
+		return err
+	}
+	defer src.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Export writes a snapshot as a .mcworld archive at dest.
+func (s *Store) Export(worldID string, id string, dest string) error {
+	snap, err := s.Get(worldID, id)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	tmp := dest + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(out)
+	fail := func(err error) error {
+		zw.Close()
+		out.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	for _, f := range snap.Files {
+		h := &zip.FileHeader{Name: f.Path, Method: zip.Deflate, Modified: time.Unix(0, f.ModTime)}
+		w, err := zw.CreateHeader(h)
+		if err != nil {
+			return fail(err)
+		}
+		src, err := os.Open(s.objectPath(f.Hash))
+		if err != nil {
+			if os.IsNotExist(err) {
+				err = ErrCorruptSnap
+			}
+			return fail(err)
+		}
+		_, err = io.Copy(w, src)
+		src.Close()
+		if err != nil {
+			return fail(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
@@ -0,0 +1,115 @@
+package worldbackup
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestWorld(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, data := range files {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+}
+
+func TestSnapshotDedupRestoreAndExport(t *testing.T) {
+	world := filepath.Join(t.TempDir(), "world")
+	writeTestWorld(t, world, map[string]string{
+		"level.dat":     "level",
+		"db/000005.ldb": "table-five",
+		"db/CURRENT":    "MANIFEST-000002\n",
+		"db/LOCK":       "",
+		"levelname.txt": "Test",
+	})
+	s := New(filepath.Join(t.TempDir(), "store"))
+
+	first, unchanged, err := s.Snapshot(world, "w1", "Test", "")
+	if err != nil || unchanged {
+		t.Fatalf("first snapshot: %v unchanged=%v", err, unchanged)
+	}
+	if len(first.Files) != 4 || first.NewBytes != first.Size {
+		t.Fatalf("unexpected first snapshot: %+v", first)
+	}
+	if _, unchanged, err := s.Snapshot(world, "w1", "Test", ""); err != nil || !unchanged {
+		t.Fatalf("expected an unchanged snapshot, got %v unchanged=%v", err, unchanged)
+	}
+
+	writeTestWorld(t, world, map[string]string{"db/000007.ldb": "table-seven"})
+	second, unchanged, err := s.Snapshot(world, "w1", "Test", "")
+	if err != nil || unchanged {
+		t.Fatalf("second snapshot: %v unchanged=%v", err, unchanged)
+	}
+	if second.NewBytes != int64(len("table-seven")) {
+		t.Fatalf("only the new table should be stored, got %d new bytes", second.NewBytes)
+	}
+
+	dest := filepath.Join(t.TempDir(), "restored")
+	if err := s.Restore("w1", first.ID, dest); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "db", "000007.ldb")); !os.IsNotExist(err) {
+		t.Fatalf("restored first snapshot should not have the later table")
+	}
+	if b, _ := os.ReadFile(filepath.Join(dest, "db", "000005.ldb")); string(b) != "table-five" {
+		t.Fatalf("unexpected restored content %q", b)
+	}
+	if err := s.Restore("w1", first.ID, dest); err != ErrDestExists {
+		t.Fatalf("expected ErrDestExists, got %v", err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "out.mcworld")
+	if err := s.Export("w1", second.ID, archive); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		t.Fatalf("open export: %v", err)
+	}
+	defer zr.Close()
+	if len(zr.File) != 5 {
+		t.Fatalf("expected 5 files in the export, got %d", len(zr.File))
+	}
+
+	if err := s.Delete("w1", second.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if n, freed, err := s.GC(); err != nil || n != 1 || freed != int64(len("table-seven")) {
+		t.Fatalf("gc: n=%d freed=%d err=%v", n, freed, err)
+	}
+}
+
+func TestRetentionKeepsLastDailyAndWeekly(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	var snaps []Snapshot
+	add := func(id string, t time.Time) {
+		snaps = append(snaps, Snapshot{ID: id, CreatedAt: t.Unix()})
+	}
+	// Newest first, as List returns them.
+	add("today-2", now.Add(-1*time.Hour))
+	add("today-1", now.Add(-2*time.Hour))
+	add("today-0", now.Add(-3*time.Hour))
+	add("yesterday", now.AddDate(0, 0, -1))
+	add("week-ago-late", now.AddDate(0, 0, -10))
+	add("week-ago-early", now.AddDate(0, 0, -11))
+	add("ancient", now.AddDate(0, -6, 0))
+
+	keep := Retention{KeepLast: 2, KeepDaily: 7, KeepWeekly: 4}.keep(snaps, now)
+	want := map[string]bool{"today-2": true, "today-1": true, "yesterday": true, "week-ago-late": true}
+	for _, s := range snaps {
+		if keep[s.ID] != want[s.ID] {
+			t.Errorf("keep[%s] = %v, want %v", s.ID, keep[s.ID], want[s.ID])
+		}
+	}
+	if all := (Retention{}).keep(snaps, now); len(all) != len(snaps) {
+		t.Fatalf("an empty policy must keep everything")
+	}
+}
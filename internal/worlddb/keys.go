@@ -0,0 +1,252 @@
+package worlddb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// Record kinds reported for world database keys.
+const (
+	KindChunk             = "chunk"
+	KindActor             = "actor"
+	KindActorDigest       = "actor_digest"
+	KindLocalPlayer       = "local_player"
+	KindPlayer            = "player"
+	KindVillage           = "village"
+	KindMap               = "map"
+	KindStructureTemplate = "structure_template"
+	KindTickingArea       = "ticking_area"
+	KindDimension         = "dimension"
+	KindGlobal            = "global"
+	KindOther             = "other"
+)
+
+// Dimension ids as stored in chunk keys.
+const (
+	DimensionOverworld = 0
+	DimensionNether    = 1
+	DimensionEnd       = 2
+)
+
+// Chunk record tags, the byte that follows the chunk coordinates in a key.
+const (
+	TagData3D             byte = 0x2B
+	TagVersion            byte = 0x2C
+	TagData2D             byte = 0x2D
+	TagData2DLegacy       byte = 0x2E
+	TagSubChunkPrefix     byte = 0x2F
+	TagLegacyTerrain      byte = 0x30
+	TagBlockEntity        byte = 0x31
+	TagEntity             byte = 0x32
+	TagPendingTicks       byte = 0x33
+	TagLegacyBlockExtra   byte = 0x34
+	TagBiomeState         byte = 0x35
+	TagFinalizedState     byte = 0x36
+	TagConversionData     byte = 0x37
+	TagBorderBlocks       byte = 0x38
+	TagHardcodedSpawners  byte = 0x39
+	TagRandomTicks        byte = 0x3A
+	TagChecksums          byte = 0x3B
+	TagGenerationSeed     byte = 0x3C
+	TagGeneratedPreCaves  byte = 0x3D
+	TagBlendingBiomeHt    byte = 0x3E
+	TagMetaDataHash       byte = 0x3F
+	TagBlendingData       byte = 0x40
+	TagActorDigestVersion byte = 0x41
+	TagLegacyVersion      byte = 0x76
+)
+
+var chunkTagNames = map[byte]string{
+	TagData3D:             "Data3D",
+	TagVersion:            "Version",
+	TagData2D:             "Data2D",
+	TagData2DLegacy:       "Data2DLegacy",
+	TagSubChunkPrefix:     "SubChunkPrefix",
+	TagLegacyTerrain:      "LegacyTerrain",
+	TagBlockEntity:        "BlockEntity",
+	TagEntity:             "Entity",
+	TagPendingTicks:       "PendingTicks",
+	TagLegacyBlockExtra:   "LegacyBlockExtraData",
+	TagBiomeState:         "BiomeState",
+	TagFinalizedState:     "FinalizedState",
+	TagConversionData:     "ConversionData",
+	TagBorderBlocks:       "BorderBlocks",
+	TagHardcodedSpawners:  "HardcodedSpawners",
+	TagRandomTicks:        "RandomTicks",
+	TagChecksums:          "Checksums",
+	TagGenerationSeed:     "GenerationSeed",
+	TagGeneratedPreCaves:  "GeneratedPreCavesAndCliffsBlending",
+	TagBlendingBiomeHt:    "BlendingBiomeHeight",
+	TagMetaDataHash:       "MetaDataHash",
+	TagBlendingData:       "BlendingData",
+	TagActorDigestVersion: "ActorDigestVersion",
+	TagLegacyVersion:      "LegacyVersion",
+}
+
+// ChunkTagName returns the name of a chunk record tag.
+func ChunkTagName(tag byte) string {
+	if n, ok := chunkTagNames[tag]; ok {
+		return n
+	}
+	return fmt.Sprintf("Unknown(0x%02X)", tag)
+}
+
+// Fixed keys of world-wide records.
+var globalKeys = map[string]struct{}{
+	"portals":                      {},
+	"scoreboard":                   {},
+	"AutonomousEntities":           {},
+	"BiomeData":                    {},
+	"mobevents":                    {},
+	"schedulerWT":                  {},
+	"LevelChunkMetaDataDictionary": {},
+	"game_flatworldlayers":         {},
+	"PositionTrackDB-LastId":       {},
+	"realmsStoriesData":            {},
+	"dynamic_properties":           {},
+}
+
+var dimensionKeys = map[string]struct{}{
+	"Overworld": {},
+	"Nether":    {},
+	"TheEnd":    {},
+}
+
+// ChunkKey identifies one record of a chunk.
+type ChunkKey struct {
+	X           int32 `json:"x"`
+	Z           int32 `json:"z"`
+	Dimension   int32 `json:"dimension"`
+	Tag         byte  `json:"tag"`
+	SubChunk    int8  `json:"subChunk"`
+	HasSubChunk bool  `json:"hasSubChunk"`
+}
+
+// parseChunkPos decodes the coordinate prefix shared by chunk and digest keys: x and z as little-endian
+// int32, followed by the dimension for the Nether and the End.
+func parseChunkPos(b []byte) (ChunkPos, bool) {
+	var p ChunkPos
+	switch len(b) {
+	case 8:
+	case 12:
+		p.Dimension = int32(binary.LittleEndian.Uint32(b[8:12]))
+		if p.Dimension != DimensionNether && p.Dimension != DimensionEnd {
+			return p, false
+		}
+	default:
+		return p, false
+	}
+	p.X = int32(binary.LittleEndian.Uint32(b[0:4]))
+	p.Z = int32(binary.LittleEndian.Uint32(b[4:8]))
+	return p, true
+}
+
+// ParseChunkKey decodes a chunk key: the chunk position, the tag, and for sub-chunk records the sub-chunk
+// index.
+func ParseChunkKey(key []byte) (ChunkKey, bool) {
+	var k ChunkKey
+	var posLen int
+	switch len(key) {
+	case 9, 10:
+		posLen = 8
+	case 13, 14:
+		posLen = 12
+	default:
+		return k, false
+	}
+	pos, ok := parseChunkPos(key[:posLen])
+	if !ok {
+		return k, false
+	}
+	k.X, k.Z, k.Dimension = pos.X, pos.Z, pos.Dimension
+	rest := key[posLen:]
+	k.Tag = rest[0]
+	if _, ok := chunkTagNames[k.Tag]; !ok {
+		return k, false
+	}
+	if len(rest) == 2 {
+		if k.Tag != TagSubChunkPrefix && k.Tag != TagLegacyTerrain {
+			return k, false
+		}
+		k.SubChunk = int8(rest[1])
+		k.HasSubChunk = true
+	}
+	return k, true
+}
+
+// Bytes encodes the key back into its database form.
+func (k ChunkKey) Bytes() []byte {
+	out := make([]byte, 0, 14)
+	out = binary.LittleEndian.AppendUint32(out, uint32(k.X))
+	out = binary.LittleEndian.AppendUint32(out, uint32(k.Z))
+	if k.Dimension != DimensionOverworld {
+		out = binary.LittleEndian.AppendUint32(out, uint32(k.Dimension))
+	}
+	out = append(out, k.Tag)
+	if k.HasSubChunk {
+		out = append(out, byte(k.SubChunk))
+	}
+	return out
+}
+
+// Pos returns the chunk position part of the key.
+func (k ChunkKey) Pos() ChunkPos {
+	return ChunkPos{X: k.X, Z: k.Z, Dimension: k.Dimension}
+}
+
+// ChunkPos identifies a chunk column.
+type ChunkPos struct {
+	X         int32 `json:"x"`
+	Z         int32 `json:"z"`
+	Dimension int32 `json:"dimension"`
+}
+
+// KeyInfo describes a database key.
+type KeyInfo struct {
+	Kind  string    `json:"kind"`
+	Label string    `json:"label"`
+	Chunk *ChunkKey `json:"chunk,omitempty"`
+}
+
+// Classify determines what a database key holds.
+func Classify(key []byte) KeyInfo {
+	s := string(key)
+	switch {
+	case s == "~local_player":
+		return KeyInfo{Kind: KindLocalPlayer, Label: s}
+	case strings.HasPrefix(s, "player_"):
+		return KeyInfo{Kind: KindPlayer, Label: s}
+	case bytes.HasPrefix(key, []byte("actorprefix")):
+		return KeyInfo{Kind: KindActor, Label: fmt.Sprintf("actorprefix:%X", key[len("actorprefix"):])}
+	case bytes.HasPrefix(key, []byte("digp")):
+		label := "digp"
+		if p, ok := parseChunkPos(key[4:]); ok {
+			label = fmt.Sprintf("digp:%d,%d@%d", p.X, p.Z, p.Dimension)
+		}
+		return KeyInfo{Kind: KindActorDigest, Label: label}
+	case strings.HasPrefix(s, "VILLAGE_"):
+		return KeyInfo{Kind: KindVillage, Label: s}
+	case strings.HasPrefix(s, "map_"):
+		return KeyInfo{Kind: KindMap, Label: s}
+	case strings.HasPrefix(s, "structuretemplate"):
+		return KeyInfo{Kind: KindStructureTemplate, Label: s}
+	case strings.HasPrefix(s, "tickingarea"):
+		return KeyInfo{Kind: KindTickingArea, Label: s}
+	}
+	if _, ok := dimensionKeys[s]; ok {
+		return KeyInfo{Kind: KindDimension, Label: s}
+	}
+	if _, ok := globalKeys[s]; ok {
+		return KeyInfo{Kind: KindGlobal, Label: s}
+	}
+	if k, ok := ParseChunkKey(key); ok {
+		label := fmt.Sprintf("%d,%d@%d %s", k.X, k.Z, k.Dimension, ChunkTagName(k.Tag))
+		if k.HasSubChunk {
+			label += fmt.Sprintf("[%d]", k.SubChunk)
+		}
+		return KeyInfo{Kind: KindChunk, Label: label, Chunk: &k}
+	}
+	return KeyInfo{Kind: KindOther, Label: fmt.Sprintf("%q", s)}
+}
@@ -0,0 +1,342 @@
+package worlddb
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/leveldb"
+	"github.com/liteldev/LeviLauncher/internal/nbt"
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+// LocalPlayerKey holds the player of a single-player world.
+const LocalPlayerKey = "~local_player"
+
+// abilityFlags maps the byte flags of the abilities compound to the typed fields.
+var abilityFlags = []struct {
+	name string
+	get  func(a *types.WorldPlayerAbilities) *bool
+}{
+	{"flying", func(a *types.WorldPlayerAbilities) *bool { return &a.Flying }},
+	{"mayfly", func(a *types.WorldPlayerAbilities) *bool { return &a.MayFly }},
+	{"instabuild", func(a *types.WorldPlayerAbilities) *bool { return &a.Instabuild }},
+	{"invulnerable", func(a *types.WorldPlayerAbilities) *bool { return &a.Invulnerable }},
+	{"lightning", func(a *types.WorldPlayerAbilities) *bool { return &a.Lightning }},
+	{"build", func(a *types.WorldPlayerAbilities) *bool { return &a.Build }},
+	{"mine", func(a *types.WorldPlayerAbilities) *bool { return &a.Mine }},
+	{"doorsandswitches", func(a *types.WorldPlayerAbilities) *bool { return &a.DoorsAndSwitches }},
+	{"opencontainers", func(a *types.WorldPlayerAbilities) *bool { return &a.OpenContainers }},
+	{"attackplayers", func(a *types.WorldPlayerAbilities) *bool { return &a.AttackPlayers }},
+	{"attackmobs", func(a *types.WorldPlayerAbilities) *bool { return &a.AttackMobs }},
+	{"op", func(a *types.WorldPlayerAbilities) *bool { return &a.Op }},
+	{"teleport", func(a *types.WorldPlayerAbilities) *bool { return &a.Teleport }},
+}
+
+// IsPlayerKey reports whether key holds the data of a player: the local player or a server player
+// record. The player_<id> records only point at the server record and are not player data themselves.
+func IsPlayerKey(key string) bool {
+	return key == LocalPlayerKey || strings.HasPrefix(key, "player_server_")
+}
+
+// Players returns the keys of the player records in the world.
+func (w *World) Players() ([]string, error) {
+	var out []string
+	err := w.ForEach(func(key []byte, info KeyInfo, _ []byte) error {
+		if (info.Kind == KindLocalPlayer || info.Kind == KindPlayer) && IsPlayerKey(string(key)) {
+			out = append(out, string(key))
+		}
+		return nil
+	})
+	return out, err
+}
+
+// PutNBT encodes compound and stores it under key in the database of the world in worldDir. The world
+// must not be open, neither through Open nor in the game.
+func PutNBT(worldDir string, key []byte, compound map[string]any) error {
+	data, err := nbt.MarshalEncoding(compound, nbt.LittleEndian)
+	if err != nil {
+		return err
+	}
+	var b leveldb.Batch
+	b.Put(key, data)
+	return leveldb.Write(dbDirOf(worldDir), &b)
+}
+
+func toFloat32(v any) float32 {
+	switch n := v.(type) {
+	case float32:
+		return n
+	case float64:
+		return float32(n)
+	case int32:
+		return float32(n)
+	case int64:
+		return float32(n)
+	case int16:
+		return float32(n)
+	case byte:
+		return float32(n)
+	}
+	return 0
+}
+
+func toInt(v any) int {
+	switch n := v.(type) {
+	case byte:
+		return int(n)
+	case int16:
+		return int(n)
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case float32:
+		return int(n)
+	case float64:
+		return int(n)
+	case bool:
+		if n {
+			return 1
+		}
+	}
+	return 0
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func decodeItems(v any, slotted bool) []types.WorldItemStack {
+	list, _ := v.([]any)
+	out := []types.WorldItemStack{}
+	for i, it := range list {
+		m, ok := it.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := m["Name"].(string)
+		count := toInt(m["Count"])
+		if name == "" || count == 0 {
+			continue
+		}
+		item := types.WorldItemStack{Slot: i, Name: name, Count: count, Damage: toInt(m["Damage"])}
+		if slotted {
+			item.Slot = toInt(m["Slot"])
+		}
+		if tag, ok := m["tag"].(map[string]any); ok {
+			item.Tag = tag
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+func emptyItem() map[string]any {
+	return map[string]any{"Count": byte(0), "Damage": int16(0), "Name": "", "WasPickedUp": byte(0)}
+}
+
+// encodeItems writes items back over the list orig. Slots keep their stored compound, and with it the
+// item's tag, enchantments and block state, as long as they still hold the same item; slots that are no
+// longer listed are emptied. Tags are never taken from items because the JSON round trip loses NBT types.
+func encodeItems(orig any, items []types.WorldItemStack, slotted bool, minLen int) []any {
+	list, _ := orig.([]any)
+	slotOf := func(i int, m map[string]any) int {
+		if slotted {
+			return toInt(m["Slot"])
+		}
+		return i
+	}
+	existing := map[int]map[string]any{}
+	var order []int
+	for i, it := range list {
+		m, ok := it.(map[string]any)
+		if !ok {
+			continue
+		}
+		s := slotOf(i, m)
+		if _, dup := existing[s]; !dup {
+			order = append(order, s)
+		}
+		existing[s] = m
+	}
+	if !slotted {
+		for i := len(order); i < minLen; i++ {
+			order = append(order, i)
+		}
+	}
+	wanted := map[int]types.WorldItemStack{}
+	for _, it := range items {
+		if it.Name == "" || it.Count <= 0 {
+			continue
+		}
+		if _, ok := existing[it.Slot]; !ok {
+			if _, seen := wanted[it.Slot]; !seen {
+				order = append(order, it.Slot)
+			}
+		}
+		wanted[it.Slot] = it
+	}
+
+	out := make([]any, 0, len(order))
+	for _, s := range order {
+		old := existing[s]
+		var m map[string]any
+		if it, ok := wanted[s]; ok {
+			if old != nil && old["Name"] == it.Name {
+				m = old
+			} else {
+				m = emptyItem()
+			}
+			m["Name"] = it.Name
+			m["Count"] = byte(it.Count)
+			m["Damage"] = int16(it.Damage)
+		} else {
+			m = emptyItem()
+		}
+		if slotted {
+			m["Slot"] = byte(s)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func floatList(v any, n int) []float32 {
+	list, _ := v.([]any)
+	out := make([]float32, n)
+	for i := 0; i < n && i < len(list); i++ {
+		out[i] = toFloat32(list[i])
+	}
+	return out
+}
+
+// DecodePlayer reads the typed view of a player compound.
+func DecodePlayer(key string, root map[string]any) types.WorldPlayer {
+	p := types.WorldPlayer{
+		Key:           key,
+		Local:         key == LocalPlayerKey,
+		Dimension:     int32(toInt(root["DimensionId"])),
+		GameMode:      int32(toInt(root["PlayerGameMode"])),
+		Level:         int32(toInt(root["PlayerLevel"])),
+		LevelProgress: toFloat32(root["PlayerLevelProgress"]),
+		Inventory:     decodeItems(root["Inventory"], true),
+		Armor:         decodeItems(root["Armor"], false),
+		Offhand:       decodeItems(root["Offhand"], false),
+		EnderChest:    decodeItems(root["EnderChestInventory"], true),
+		Attributes:    []types.WorldPlayerAttribute{},
+	}
+	copy(p.Pos[:], floatList(root["Pos"], 3))
+	copy(p.Rotation[:], floatList(root["Rotation"], 2))
+	if ab, ok := root["abilities"].(map[string]any); ok {
+		for _, f := range abilityFlags {
+			*f.get(&p.Abilities) = toInt(ab[f.name]) != 0
+		}
+		p.Abilities.FlySpeed = toFloat32(ab["flySpeed"])
+		p.Abilities.WalkSpeed = toFloat32(ab["walkSpeed"])
+	}
+	if attrs, ok := root["Attributes"].([]any); ok {
+		for _, a := range attrs {
+			m, ok := a.(map[string]any)
+			if !ok {
+				continue
+			}
+			name, _ := m["Name"].(string)
+			p.Attributes = append(p.Attributes, types.WorldPlayerAttribute{
+				Name:    name,
+				Base:    toFloat32(m["Base"]),
+				Current: toFloat32(m["Current"]),
+				Min:     toFloat32(m["Min"]),
+				Max:     toFloat32(m["Max"]),
+			})
+		}
+	}
+	return p
+}
+
+// ErrInvalidItemStack reports an item stack whose count or slot does not fit the byte the game stores
+// it in.
+var ErrInvalidItemStack = errors.New("worlddb: item count or slot out of range")
+
+func checkItems(items []types.WorldItemStack) error {
+	for _, it := range items {
+		if it.Count < 0 || it.Count > 255 || it.Slot < 0 || it.Slot > 255 {
+			return ErrInvalidItemStack
+		}
+	}
+	return nil
+}
+
+// ApplyPlayer writes the typed fields of p into the player compound root, leaving every other tag as
+// it was. Attributes are only updated, never added, since the game owns the attribute list. root is left
+// untouched when an item stack is out of range.
+func ApplyPlayer(root map[string]any, p types.WorldPlayer) error {
+	for _, items := range [][]types.WorldItemStack{p.Inventory, p.Armor, p.Offhand, p.EnderChest} {
+		if err := checkItems(items); err != nil {
+			return err
+		}
+	}
+	root["Pos"] = []any{p.Pos[0], p.Pos[1], p.Pos[2]}
+	root["Rotation"] = []any{p.Rotation[0], p.Rotation[1]}
+	root["DimensionId"] = p.Dimension
+	root["PlayerGameMode"] = p.GameMode
+	root["PlayerLevel"] = p.Level
+	root["PlayerLevelProgress"] = p.LevelProgress
+	root["Inventory"] = encodeItems(root["Inventory"], p.Inventory, true, 0)
+	root["Armor"] = encodeItems(root["Armor"], p.Armor, false, 4)
+	root["Offhand"] = encodeItems(root["Offhand"], p.Offhand, false, 1)
+	root["EnderChestInventory"] = encodeItems(root["EnderChestInventory"], p.EnderChest, true, 0)
+
+	ab, ok := root["abilities"].(map[string]any)
+	if !ok {
+		ab = map[string]any{}
+		root["abilities"] = ab
+	}
+	for _, f := range abilityFlags {
+		ab[f.name] = boolByte(*f.get(&p.Abilities))
+	}
+	ab["flySpeed"] = p.Abilities.FlySpeed
+	ab["walkSpeed"] = p.Abilities.WalkSpeed
+
+	if attrs, ok := root["Attributes"].([]any); ok {
+		byName := map[string]types.WorldPlayerAttribute{}
+		for _, a := range p.Attributes {
+			byName[a.Name] = a
+		}
+		for _, a := range attrs {
+			m, ok := a.(map[string]any)
+			if !ok {
+				continue
+			}
+			name, _ := m["Name"].(string)
+			if v, ok := byName[name]; ok {
+				m["Base"], m["Current"], m["Min"], m["Max"] = v.Base, v.Current, v.Min, v.Max
+			}
+		}
+	}
+	return nil
+}
+
+// DeletePlayers removes every player record of the world in worldDir, including the player_<id> records
+// that point at server player data, so each player starts over at the world spawn. It returns the number
+// of records removed.
+func DeletePlayers(worldDir string) (int, error) {
+	w, err := Open(worldDir)
+	if err != nil {
+		return 0, err
+	}
+	var b leveldb.Batch
+	err = w.ForEach(func(key []byte, info KeyInfo, _ []byte) error {
+		if info.Kind == KindLocalPlayer || info.Kind == KindPlayer {
+			b.Delete(key)
+		}
+		return nil
+	})
+	w.Close()
+	if err != nil {
+		return 0, err
+	}
+	return b.Len(), leveldb.Write(dbDirOf(worldDir), &b)
+}
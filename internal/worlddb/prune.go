@@ -0,0 +1,158 @@
+package worlddb
+
+import (
+	"bytes"
+
+	"github.com/liteldev/LeviLauncher/internal/leveldb"
+)
+
+// KeepArea is a region of one dimension whose chunks survive pruning, in chunk coordinates. With a
+// positive Radius it is the circle of that many chunks around CenterX/CenterZ; otherwise it is the
+// rectangle MinX..MaxX by MinZ..MaxZ.
+type KeepArea struct {
+	Dimension int32 `json:"dimension"`
+	CenterX   int32 `json:"centerX"`
+	CenterZ   int32 `json:"centerZ"`
+	Radius    int32 `json:"radius"`
+	MinX      int32 `json:"minX"`
+	MinZ      int32 `json:"minZ"`
+	MaxX      int32 `json:"maxX"`
+	MaxZ      int32 `json:"maxZ"`
+}
+
+func (a KeepArea) contains(p ChunkPos) bool {
+	if a.Dimension != p.Dimension {
+		return false
+	}
+	if a.Radius > 0 {
+		dx, dz := int64(p.X)-int64(a.CenterX), int64(p.Z)-int64(a.CenterZ)
+		return dx*dx+dz*dz <= int64(a.Radius)*int64(a.Radius)
+	}
+	return p.X >= a.MinX && p.X <= a.MaxX && p.Z >= a.MinZ && p.Z <= a.MaxZ
+}
+
+// PruneResult reports what a prune removed, or would remove on a dry run.
+type PruneResult struct {
+	Chunks     int   `json:"chunks"`
+	Records    int   `json:"records"`
+	Actors     int   `json:"actors"`
+	Bytes      int64 `json:"bytes"`
+	SizeBefore int64 `json:"sizeBefore"`
+	SizeAfter  int64 `json:"sizeAfter"`
+	KeptChunks int   `json:"keptChunks"`
+	DryRun     bool  `json:"dryRun"`
+}
+
+// pruner decides which records go. Dimensions without any keep area are left alone.
+type pruner struct {
+	areas  []KeepArea
+	dims   map[int32]bool
+	actors map[string]struct{}
+}
+
+func newPruner(areas []KeepArea) *pruner {
+	p := &pruner{areas: areas, dims: map[int32]bool{}, actors: map[string]struct{}{}}
+	for _, a := range areas {
+		p.dims[a.Dimension] = true
+	}
+	return p
+}
+
+func (p *pruner) keepPos(pos ChunkPos) bool {
+	if !p.dims[pos.Dimension] {
+		return true
+	}
+	for _, a := range p.areas {
+		if a.contains(pos) {
+			return true
+		}
+	}
+	return false
+}
+
+// digestPos returns the chunk an actor digest record belongs to.
+func digestPos(key []byte) (ChunkPos, bool) {
+	if !bytes.HasPrefix(key, []byte("digp")) {
+		return ChunkPos{}, false
+	}
+	return parseChunkPos(key[4:])
+}
+
+// collect notes the actors listed by digests of pruned chunks. Actor records sort before the digests
+// that reference them, so this has to run as a separate pass before the records are filtered.
+func (p *pruner) collect(key []byte, info KeyInfo, value []byte) {
+	if info.Kind != KindActorDigest {
+		return
+	}
+	if pos, ok := digestPos(key); ok && !p.keepPos(pos) {
+		for i := 0; i+8 <= len(value); i += 8 {
+			p.actors[string(value[i:i+8])] = struct{}{}
+		}
+	}
+}
+
+func (p *pruner) keep(key []byte, info KeyInfo) bool {
+	switch info.Kind {
+	case KindChunk:
+		return p.keepPos(info.Chunk.Pos())
+	case KindActorDigest:
+		pos, ok := digestPos(key)
+		return !ok || p.keepPos(pos)
+	case KindActor:
+		_, drop := p.actors[string(key[len("actorprefix"):])]
+		return !drop
+	}
+	return true
+}
+
+// Prune deletes the chunks of the world in worldDir that lie outside every keep area of their dimension,
+// together with their actor digests and the actors those list, and compacts the database. With dryRun
+// set it only reports what would be removed.
+func Prune(worldDir string, areas []KeepArea, dryRun bool) (PruneResult, error) {
+	res := PruneResult{DryRun: dryRun}
+	w, err := Open(worldDir)
+	if err != nil {
+		return res, err
+	}
+	p := newPruner(areas)
+	err = w.ForEach(func(key []byte, info KeyInfo, value []byte) error {
+		p.collect(key, info, value)
+		return nil
+	})
+	if err == nil {
+		err = w.ForEach(func(key []byte, info KeyInfo, value []byte) error {
+			p.tally(&res, key, info, value)
+			return nil
+		})
+	}
+	dbDir := w.DB().Dir()
+	// The tables must be closed before a rewrite can delete them.
+	w.Close()
+	if err != nil || dryRun {
+		return res, err
+	}
+	st, err := leveldb.Rewrite(dbDir, func(key []byte, _ []byte) bool {
+		return p.keep(key, Classify(key))
+	})
+	res.SizeBefore, res.SizeAfter = st.SizeBefore, st.SizeAfter
+	return res, err
+}
+
+func (p *pruner) tally(res *PruneResult, key []byte, info KeyInfo, value []byte) {
+	kept := p.keep(key, info)
+	if info.Kind == KindChunk && (info.Chunk.Tag == TagVersion || info.Chunk.Tag == TagLegacyVersion) {
+		if kept {
+			res.KeptChunks++
+		} else {
+			res.Chunks++
+		}
+	}
+	if kept {
+		return
+	}
+	res.Records++
+	res.Bytes += int64(len(key) + len(value))
+	if info.Kind == KindActor {
+		res.Actors++
+	}
+}
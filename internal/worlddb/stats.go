@@ -0,0 +1,100 @@
+package worlddb
+
+import "sort"
+
+// DimensionStats summarises the generated terrain of one dimension.
+type DimensionStats struct {
+	Dimension int32 `json:"dimension"`
+	Chunks    int   `json:"chunks"`
+	MinX      int32 `json:"minX"`
+	MaxX      int32 `json:"maxX"`
+	MinZ      int32 `json:"minZ"`
+	MaxZ      int32 `json:"maxZ"`
+	Bytes     int64 `json:"bytes"`
+}
+
+// Stats is a summary of a world database.
+type Stats struct {
+	Dimensions    []DimensionStats `json:"dimensions"`
+	Actors        int              `json:"actors"`
+	BlockEntities int              `json:"blockEntities"`
+	Records       map[string]int   `json:"records"`
+	RecordBytes   map[string]int64 `json:"recordBytes"`
+}
+
+type statsCollector struct {
+	dims  map[int32]*DimensionStats
+	order []int32
+	out   Stats
+}
+
+func newStatsCollector() *statsCollector {
+	return &statsCollector{
+		dims: map[int32]*DimensionStats{},
+		out:  Stats{Records: map[string]int{}, RecordBytes: map[string]int64{}},
+	}
+}
+
+func (c *statsCollector) dimension(id int32) *DimensionStats {
+	d, ok := c.dims[id]
+	if !ok {
+		d = &DimensionStats{Dimension: id}
+		c.dims[id] = d
+		c.order = append(c.order, id)
+	}
+	return d
+}
+
+func (c *statsCollector) add(info KeyInfo, value []byte) {
+	c.out.Records[info.Kind]++
+	c.out.RecordBytes[info.Kind] += int64(len(value))
+	switch info.Kind {
+	case KindActor:
+		c.out.Actors++
+	case KindChunk:
+		k := info.Chunk
+		d := c.dimension(k.Dimension)
+		d.Bytes += int64(len(value))
+		switch k.Tag {
+		case TagVersion, TagLegacyVersion:
+			// Every chunk has exactly one version record, so it stands in for the chunk itself.
+			if d.Chunks == 0 {
+				d.MinX, d.MaxX, d.MinZ, d.MaxZ = k.X, k.X, k.Z, k.Z
+			} else {
+				d.MinX, d.MaxX = min(d.MinX, k.X), max(d.MaxX, k.X)
+				d.MinZ, d.MaxZ = min(d.MinZ, k.Z), max(d.MaxZ, k.Z)
+			}
+			d.Chunks++
+		case TagBlockEntity:
+			if tags, err := DecodeNBT(value); err == nil {
+				c.out.BlockEntities += len(tags)
+			}
+		case TagEntity:
+			// Worlds from before the actor storage change keep their actors inside the chunk.
+			if tags, err := DecodeNBT(value); err == nil {
+				c.out.Actors += len(tags)
+			}
+		}
+	}
+}
+
+func (c *statsCollector) result() Stats {
+	out := c.out
+	sort.Slice(c.order, func(i, j int) bool { return c.order[i] < c.order[j] })
+	out.Dimensions = make([]DimensionStats, 0, len(c.order))
+	for _, id := range c.order {
+		out.Dimensions = append(out.Dimensions, *c.dims[id])
+	}
+	return out
+}
+
+// Stats walks the whole database and summarises chunks per dimension, actors, block entities and the
+// number and size of records of each kind.
+func (w *World) Stats() (Stats, error) {
+	c := newStatsCollector()
+	err := w.ForEach(func(_ []byte, info KeyInfo, value []byte) error {
+		c.add(info, value)
+		return nil
+	})
+	return c.result(), err
+}
@@ -0,0 +1,124 @@
+// Package worlddb gives typed access to the records of a Bedrock world database.
+//
+// Keys are classified into chunk records, actors, player data, villages, maps and world-wide records,
+// and the little-endian NBT values most of them hold are decoded through internal/nbt. The package reads
+// the database through internal/leveldb; only Prune and PutNBT write to it.
+package worlddb
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+
+	"github.com/liteldev/LeviLauncher/internal/leveldb"
+	"github.com/liteldev/LeviLauncher/internal/nbt"
+)
+
+var ErrNotFound = leveldb.ErrNotFound
+
+// World is an open world database.
+type World struct {
+	Dir string
+	db  *leveldb.DB
+}
+
+// dbDirOf returns the database directory of a world folder.
+func dbDirOf(worldDir string) string {
+	return filepath.Join(worldDir, "db")
+}
+
+// Open opens the database of the world folder worldDir.
+func Open(worldDir string) (*World, error) {
+	db, err := leveldb.Open(dbDirOf(worldDir))
+	if err != nil {
+		return nil, err
+	}
+	return &World{Dir: worldDir, db: db}, nil
+}
+
+func (w *World) Close() error {
+	return w.db.Close()
+}
+
+// DB exposes the underlying database.
+func (w *World) DB() *leveldb.DB {
+	return w.db
+}
+
+// Get returns the raw value of key.
+func (w *World) Get(key []byte) ([]byte, error) {
+	return w.db.Get(key)
+}
+
+// ForEach calls fn for every record with its classified key. Returning an error from fn stops the walk.
+func (w *World) ForEach(fn func(key []byte, info KeyInfo, value []byte) error) error {
+	return w.db.ForEach(func(key []byte, value []byte) error {
+		return fn(key, Classify(key), value)
+	})
+}
+
+// ForEachKind is ForEach restricted to records of one kind.
+func (w *World) ForEachKind(kind string, fn func(key []byte, info KeyInfo, value []byte) error) error {
+	return w.ForEach(func(key []byte, info KeyInfo, value []byte) error {
+		if info.Kind != kind {
+			return nil
+		}
+		return fn(key, info, value)
+	})
+}
+
+// CountKinds returns the number of records of each kind.
+func (w *World) CountKinds() (map[string]int, error) {
+	out := map[string]int{}
+	err := w.ForEach(func(_ []byte, info KeyInfo, _ []byte) error {
+		out[info.Kind]++
+		return nil
+	})
+	return out, err
+}
+
+// ReadNBT decodes the value of key as a sequence of little-endian NBT compounds.
+func (w *World) ReadNBT(key []byte) ([]map[string]any, error) {
+	v, err := w.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeNBT(v)
+}
+
+// DecodeNBT decodes one or more concatenated little-endian NBT compounds. Entity and block entity
+// records of a chunk store one compound per actor back to back.
+func DecodeNBT(data []byte) ([]map[string]any, error) {
+	r := bytes.NewBuffer(data)
+	dec := nbt.NewDecoderWithEncoding(r, nbt.LittleEndian)
+	var out []map[string]any
+	for r.Len() > 0 {
+		var m map[string]any
+		if err := dec.Decode(&m); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return out, err
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// IsNBTKind reports whether records of kind hold NBT values.
+func IsNBTKind(info KeyInfo) bool {
+	switch info.Kind {
+	case KindActor, KindLocalPlayer, KindPlayer, KindVillage, KindMap, KindStructureTemplate, KindTickingArea, KindDimension:
+		return true
+	case KindGlobal:
+		// The chunk metadata dictionary is a binary table and the flat world layers are JSON text.
+		return info.Label != "LevelChunkMetaDataDictionary" && info.Label != "game_flatworldlayers"
+	case KindChunk:
+		switch info.Chunk.Tag {
+		case TagBlockEntity, TagEntity, TagPendingTicks, TagRandomTicks:
+			return true
+		}
+	}
+	return false
+}
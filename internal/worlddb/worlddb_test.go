@@ -0,0 +1,205 @@
+package worlddb
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/nbt"
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+func TestParseChunkKeyRoundTrip(t *testing.T) {
+	for _, k := range []ChunkKey{
+		{X: 3, Z: -7, Tag: TagVersion},
+		{X: -1, Z: 100, Dimension: DimensionNether, Tag: TagSubChunkPrefix, SubChunk: -4, HasSubChunk: true},
+		{X: 0, Z: 0, Dimension: DimensionEnd, Tag: TagEntity},
+	} {
+		got, ok := ParseChunkKey(k.Bytes())
+		if !ok || got != k {
+			t.Fatalf("round trip of %+v gave %+v (ok=%v)", k, got, ok)
+		}
+		if info := Classify(k.Bytes()); info.Kind != KindChunk {
+			t.Fatalf("expected chunk kind for %+v, got %+v", k, info)
+		}
+	}
+	if _, ok := ParseChunkKey(append(ChunkKey{X: 1, Z: 1, Tag: TagVersion}.Bytes(), 5)); ok {
+		t.Fatalf("only sub-chunk records may carry a sub-chunk index")
+	}
+}
+
+func TestClassifyNamedKeys(t *testing.T) {
+	for key, kind := range map[string]string{
+		"~local_player":               KindLocalPlayer,
+		"player_server_1234-abcd":     KindPlayer,
+		"actorprefix\x00\x00\x00\x01": KindActor,
+		"VILLAGE_abc_INFO":            KindVillage,
+		"map_-4294967295":             KindMap,
+		"Overworld":                   KindDimension,
+		"scoreboard":                  KindGlobal,
+		"mystery":                     KindOther,
+	} {
+		if got := Classify([]byte(key)).Kind; got != kind {
+			t.Errorf("Classify(%q) = %s, want %s", key, got, kind)
+		}
+	}
+}
+
+func TestDecodeNBTReadsConcatenatedCompounds(t *testing.T) {
+	var buf bytes.Buffer
+	for _, id := range []string{"minecraft:chest", "minecraft:sign"} {
+		b, err := nbt.MarshalEncoding(map[string]any{"id": id, "x": int32(1)}, nbt.LittleEndian)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		buf.Write(b)
+	}
+	got, err := DecodeNBT(buf.Bytes())
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got) != 2 || got[1]["id"] != "minecraft:sign" {
+		t.Fatalf("unexpected compounds: %+v", got)
+	}
+}
+
+func TestStatsCollector(t *testing.T) {
+	chest, err := nbt.MarshalEncoding(map[string]any{"id": "Chest"}, nbt.LittleEndian)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	c := newStatsCollector()
+	for _, k := range []ChunkKey{
+		{X: 2, Z: -3, Tag: TagVersion},
+		{X: -5, Z: 4, Tag: TagVersion},
+		{X: -5, Z: 4, Tag: TagSubChunkPrefix, HasSubChunk: true},
+		{X: 7, Z: 7, Dimension: DimensionNether, Tag: TagVersion},
+	} {
+		c.add(Classify(k.Bytes()), []byte{1, 2})
+	}
+	c.add(Classify(ChunkKey{X: 2, Z: -3, Tag: TagBlockEntity}.Bytes()), append(append([]byte{}, chest...), chest...))
+	c.add(Classify([]byte("actorprefix\x00\x00\x00\x01")), chest)
+
+	st := c.result()
+	if len(st.Dimensions) != 2 {
+		t.Fatalf("expected two dimensions, got %+v", st.Dimensions)
+	}
+	ow := st.Dimensions[0]
+	if ow.Dimension != DimensionOverworld || ow.Chunks != 2 || ow.MinX != -5 || ow.MaxX != 2 || ow.MinZ != -3 || ow.MaxZ != 4 {
+		t.Fatalf("unexpected overworld stats: %+v", ow)
+	}
+	if st.Dimensions[1].Chunks != 1 {
+		t.Fatalf("unexpected nether stats: %+v", st.Dimensions[1])
+	}
+	if st.BlockEntities != 2 || st.Actors != 1 || st.Records[KindChunk] != 5 {
+		t.Fatalf("unexpected totals: %+v", st)
+	}
+}
+
+func TestPrunerKeepsAreasAndDropsDigestActors(t *testing.T) {
+	p := newPruner([]KeepArea{
+		{Dimension: DimensionOverworld, Radius: 4},
+		{Dimension: DimensionOverworld, MinX: 100, MaxX: 101, MinZ: 100, MaxZ: 101},
+	})
+	near := ChunkKey{X: 2, Z: -3, Tag: TagVersion}.Bytes()
+	far := ChunkKey{X: 50, Z: 0, Tag: TagSubChunkPrefix, HasSubChunk: true}.Bytes()
+	box := ChunkKey{X: 101, Z: 100, Tag: TagVersion}.Bytes()
+	nether := ChunkKey{X: 500, Z: 500, Dimension: DimensionNether, Tag: TagVersion}.Bytes()
+
+	digest := append([]byte("digp"), ChunkKey{X: 50, Z: 0}.Bytes()[:8]...)
+	actorID := []byte{0, 0, 0, 1, 0, 0, 0, 9}
+	p.collect(digest, Classify(digest), actorID)
+	actor := append([]byte("actorprefix"), actorID...)
+	other := append([]byte("actorprefix"), 0, 0, 0, 1, 0, 0, 0, 10)
+
+	for key, want := range map[string]bool{
+		string(near):    true,
+		string(far):     false,
+		string(box):     true,
+		string(nether):  true,
+		string(digest):  false,
+		string(actor):   false,
+		string(other):   true,
+		"~local_player": true,
+	} {
+		k := []byte(key)
+		if got := p.keep(k, Classify(k)); got != want {
+			t.Errorf("keep(%s) = %v, want %v", Classify(k).Label, got, want)
+		}
+	}
+}
+
+func TestApplyPlayerKeepsUnknownTagsAndItemData(t *testing.T) {
+	sword := map[string]any{"Name": "minecraft:diamond_sword", "Count": byte(1), "Damage": int16(3), "Slot": byte(0), "WasPickedUp": byte(0),
+		"tag": map[string]any{"ench": []any{map[string]any{"id": int16(9), "lvl": int16(5)}}}}
+	root := map[string]any{
+		"Pos":            []any{float32(1), float32(64), float32(2)},
+		"DimensionId":    int32(0),
+		"UniqueID":       int64(-12),
+		"Inventory":      []any{sword, map[string]any{"Name": "minecraft:dirt", "Count": byte(64), "Damage": int16(0), "Slot": byte(1)}},
+		"Armor":          []any{emptyItem(), emptyItem(), emptyItem(), emptyItem()},
+		"abilities":      map[string]any{"mayfly": byte(0), "flySpeed": float32(0.05), "permissionsLevel": int32(1)},
+		"Attributes":     []any{map[string]any{"Name": "minecraft:health", "Base": float32(20), "Current": float32(5), "Min": float32(0), "Max": float32(20)}},
+		"PlayerGameMode": int32(0),
+	}
+	p := DecodePlayer(LocalPlayerKey, root)
+	if len(p.Inventory) != 2 || p.Inventory[0].Tag == nil || p.Pos[1] != 64 {
+		t.Fatalf("unexpected decode: %+v", p)
+	}
+
+	p.Pos[1] = 100
+	p.Abilities.MayFly = true
+	p.Inventory = []types.WorldItemStack{
+		{Slot: 0, Name: "minecraft:diamond_sword", Count: 1},
+		{Slot: 5, Name: "minecraft:apple", Count: 3},
+	}
+	p.Armor = []types.WorldItemStack{{Slot: 0, Name: "minecraft:iron_helmet", Count: 1}}
+	p.Attributes[0].Current = 20
+	if err := ApplyPlayer(root, p); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	b, err := nbt.MarshalEncoding(root, nbt.LittleEndian)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var back map[string]any
+	if err := nbt.UnmarshalEncoding(b, &back, nbt.LittleEndian); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if back["UniqueID"] != int64(-12) || back["abilities"].(map[string]any)["permissionsLevel"] != int32(1) {
+		t.Fatalf("unknown tags were lost: %+v", back)
+	}
+	got := DecodePlayer(LocalPlayerKey, back)
+	if got.Pos[1] != 100 || !got.Abilities.MayFly || got.Attributes[0].Current != 20 {
+		t.Fatalf("typed fields not written: %+v", got)
+	}
+	if len(got.Inventory) != 2 || got.Inventory[0].Tag == nil || got.Inventory[1].Name != "minecraft:apple" || got.Inventory[1].Slot != 5 {
+		t.Fatalf("unexpected inventory: %+v", got.Inventory)
+	}
+	if len(back["Inventory"].([]any)) != 3 {
+		t.Fatalf("emptied slot should stay in the list: %+v", back["Inventory"])
+	}
+	if len(got.Armor) != 1 || got.Armor[0].Name != "minecraft:iron_helmet" || len(back["Armor"].([]any)) != 4 {
+		t.Fatalf("unexpected armor: %+v", back["Armor"])
+	}
+}
+
+func TestApplyPlayerRejectsOutOfRangeStacks(t *testing.T) {
+	for _, it := range []types.WorldItemStack{
+		{Slot: 0, Name: "minecraft:apple", Count: 256},
+		{Slot: 0, Name: "minecraft:apple", Count: -1},
+		{Slot: 300, Name: "minecraft:apple", Count: 1},
+	} {
+		root := map[string]any{"Inventory": []any{}, "Pos": []any{float32(0), float32(64), float32(0)}}
+		p := DecodePlayer(LocalPlayerKey, root)
+		p.Pos[1] = 100
+		p.EnderChest = []types.WorldItemStack{it}
+		if err := ApplyPlayer(root, p); !errors.Is(err, ErrInvalidItemStack) {
+			t.Fatalf("%+v: got %v, want ErrInvalidItemStack", it, err)
+		}
+		if root["Pos"].([]any)[1] != float32(64) {
+			t.Fatalf("%+v: root was modified before validation", it)
+		}
+	}
+}
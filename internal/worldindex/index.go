@@ -0,0 +1,268 @@
+// Package worldindex keeps a searchable index of the worlds in every location the launcher manages.
+//
+// A refresh walks the minecraftWorlds folder of each player in each location and only reads level.dat
+// and measures the size of worlds whose folder, level.dat or db folder changed since the last refresh.
+// The index is persisted as JSON so searches do not rescan the disk.
+package worldindex
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/liteldev/LeviLauncher/internal/content"
+	"github.com/liteldev/LeviLauncher/internal/types"
+	"github.com/liteldev/LeviLauncher/internal/utils"
+)
+
+// Sort keys accepted by Search.
+const (
+	SortName       = "name"
+	SortLastPlayed = "lastPlayed"
+	SortSize       = "size"
+	SortVersion    = "version"
+	SortLocation   = "location"
+)
+
+// Location is a Users folder to index. VersionName is empty for the shared GDK data.
+type Location struct {
+	Label       string
+	VersionName string
+	UsersDir    string
+}
+
+// Index is a persisted world index.
+type Index struct {
+	path string
+
+	mu        sync.RWMutex
+	entries   map[string]types.WorldIndexEntry
+	updatedAt int64
+}
+
+type indexFile struct {
+	UpdatedAt int64                   `json:"updatedAt"`
+	Entries   []types.WorldIndexEntry `json:"entries"`
+}
+
+// Load reads the index stored at path. A missing or unreadable file gives an empty index.
+func Load(path string) *Index {
+	ix := &Index{path: path, entries: map[string]types.WorldIndexEntry{}}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return ix
+	}
+	var f indexFile
+	if json.Unmarshal(b, &f) != nil {
+		return ix
+	}
+	ix.updatedAt = f.UpdatedAt
+	for _, e := range f.Entries {
+		ix.entries[indexKey(e.Path)] = e
+	}
+	return ix
+}
+
+func indexKey(p string) string {
+	return strings.ToLower(filepath.Clean(p))
+}
+
+// UpdatedAt returns when the index was last refreshed, as Unix seconds, or 0 if it never was.
+func (ix *Index) UpdatedAt() int64 {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.updatedAt
+}
+
+// Save writes the index to its file.
+func (ix *Index) Save() error {
+	ix.mu.RLock()
+	f := indexFile{UpdatedAt: ix.updatedAt, Entries: make([]types.WorldIndexEntry, 0, len(ix.entries))}
+	for _, e := range ix.entries {
+		f.Entries = append(f.Entries, e)
+	}
+	ix.mu.RUnlock()
+	sort.Slice(f.Entries, func(i, j int) bool { return f.Entries[i].Path < f.Entries[j].Path })
+	b, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0o755); err != nil {
+		return err
+	}
+	tmp := ix.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, ix.path)
+}
+
+// stampOf returns the latest modification time of the parts of a world that change when it is played
+// or edited.
+func stampOf(worldDir string) int64 {
+	var stamp int64
+	for _, p := range []string{worldDir, filepath.Join(worldDir, "level.dat"), filepath.Join(worldDir, "db")} {
+		if fi, err := os.Stat(p); err == nil {
+			stamp = max(stamp, fi.ModTime().UnixNano())
+		}
+	}
+	return stamp
+}
+
+func readEntry(worldDir string) types.WorldIndexEntry {
+	e := types.WorldIndexEntry{Path: worldDir, FolderName: filepath.Base(worldDir), Size: utils.DirSize(worldDir)}
+	if b, err := os.ReadFile(filepath.Join(worldDir, "levelname.txt")); err == nil {
+		e.LevelName = strings.TrimSpace(strings.SplitN(string(b), "\n", 2)[0])
+	}
+	if root, _, err := content.DecodeLevelDat(worldDir); err == nil {
+		data := root
+		if v, ok := root["Data"].(map[string]any); ok {
+			data = v
+		}
+		if e.LevelName == "" {
+			e.LevelName, _ = data["LevelName"].(string)
+		}
+		e.Seed, _ = data["RandomSeed"].(int64)
+		e.LastPlayed, _ = data["LastPlayed"].(int64)
+		e.GameVersion = content.LastOpenedVersion(root)
+	}
+	return e
+}
+
+// Refresh rescans locations. Worlds whose stamp is unchanged keep their entry; worlds that no longer
+// exist are dropped.
+func (ix *Index) Refresh(locations []Location) types.WorldIndexRefreshResult {
+	var res types.WorldIndexRefreshResult
+	ix.mu.RLock()
+	old := ix.entries
+	ix.mu.RUnlock()
+
+	next := map[string]types.WorldIndexEntry{}
+	for _, loc := range locations {
+		players, err := os.ReadDir(loc.UsersDir)
+		if err != nil {
+			continue
+		}
+		for _, pl := range players {
+			if !pl.IsDir() {
+				continue
+			}
+			worldsDir := filepath.Join(loc.UsersDir, pl.Name(), "games", "com.mojang", "minecraftWorlds")
+			worlds, err := os.ReadDir(worldsDir)
+			if err != nil {
+				continue
+			}
+			for _, w := range worlds {
+				if !w.IsDir() || strings.HasPrefix(w.Name(), ".") {
+					continue
+				}
+				dir := filepath.Join(worldsDir, w.Name())
+				key := indexKey(dir)
+				if _, dup := next[key]; dup {
+					continue
+				}
+				stamp := stampOf(dir)
+				e, ok := old[key]
+				if !ok || e.Stamp != stamp {
+					e = readEntry(dir)
+					e.Stamp = stamp
+					res.Updated++
+				}
+				e.Location, e.VersionName, e.Player = loc.Label, loc.VersionName, pl.Name()
+				next[key] = e
+			}
+		}
+	}
+	for key := range old {
+		if _, ok := next[key]; !ok {
+			res.Removed++
+		}
+	}
+	ix.mu.Lock()
+	ix.entries = next
+	ix.updatedAt = time.Now().Unix()
+	res.UpdatedAt = ix.updatedAt
+	ix.mu.Unlock()
+	res.Worlds = len(next)
+	return res
+}
+
+func matches(e types.WorldIndexEntry, q string) bool {
+	if q == "" {
+		return true
+	}
+	for _, s := range []string{e.LevelName, e.FolderName, e.GameVersion, e.Location, e.VersionName, e.Player, strconv.FormatInt(e.Seed, 10)} {
+		if strings.Contains(strings.ToLower(s), q) {
+			return true
+		}
+	}
+	return false
+}
+
+func less(a, b types.WorldIndexEntry, by string) bool {
+	switch by {
+	case SortLastPlayed:
+		if a.LastPlayed != b.LastPlayed {
+			return a.LastPlayed < b.LastPlayed
+		}
+	case SortSize:
+		if a.Size != b.Size {
+			return a.Size < b.Size
+		}
+	case SortVersion:
+		if c := content.CompareGameVersions(a.GameVersion, b.GameVersion, 0); c != 0 {
+			return c < 0
+		}
+	case SortLocation:
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+	}
+	if an, bn := strings.ToLower(a.LevelName), strings.ToLower(b.LevelName); an != bn {
+		return an < bn
+	}
+	return a.Path < b.Path
+}
+
+// Search returns the page of entries matching the query. The text query matches case-insensitively
+// against the level and folder names, seed, game version, location and player.
+func (ix *Index) Search(q types.WorldSearchQuery) types.WorldSearchResult {
+	res := types.WorldSearchResult{Entries: []types.WorldIndexEntry{}}
+	text := strings.ToLower(strings.TrimSpace(q.Query))
+	ix.mu.RLock()
+	res.UpdatedAt = ix.updatedAt
+	var all []types.WorldIndexEntry
+	for _, e := range ix.entries {
+		if q.VersionName != "" && e.VersionName != q.VersionName {
+			continue
+		}
+		if q.Location != "" && e.Location != q.Location {
+			continue
+		}
+		if matches(e, text) {
+			all = append(all, e)
+		}
+	}
+	ix.mu.RUnlock()
+
+	sort.Slice(all, func(i, j int) bool {
+		if q.Desc {
+			return less(all[j], all[i], q.SortBy)
+		}
+		return less(all[i], all[j], q.SortBy)
+	})
+	res.Total = len(all)
+	start := min(max(q.Offset, 0), len(all))
+	end := len(all)
+	if q.Limit > 0 {
+		end = min(start+q.Limit, end)
+	}
+	res.Entries = append(res.Entries, all[start:end]...)
+	return res
+}
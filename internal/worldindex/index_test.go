@@ -0,0 +1,89 @@
+package worldindex
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/liteldev/LeviLauncher/internal/nbt"
+	"github.com/liteldev/LeviLauncher/internal/types"
+)
+
+func writeWorld(t *testing.T, usersDir, player, folder string, data map[string]any) string {
+	t.Helper()
+	dir := filepath.Join(usersDir, player, "games", "com.mojang", "minecraftWorlds", folder)
+	if err := os.MkdirAll(filepath.Join(dir, "db"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	body, err := nbt.MarshalEncoding(data, nbt.LittleEndian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dat := binary.LittleEndian.AppendUint32(nil, 10)
+	dat = binary.LittleEndian.AppendUint32(dat, uint32(len(body)))
+	if err := os.WriteFile(filepath.Join(dir, "level.dat"), append(dat, body...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRefreshIsIncrementalAndSearchSorts(t *testing.T) {
+	root := t.TempDir()
+	shared := filepath.Join(root, "shared", "Users")
+	iso := filepath.Join(root, "iso", "Users")
+	alpha := writeWorld(t, shared, "111", "aaa=", map[string]any{
+		"LevelName": "Alpha Base", "RandomSeed": int64(42), "LastPlayed": int64(200),
+		"lastOpenedWithVersion": []int32{1, 21, 50},
+	})
+	writeWorld(t, iso, "222", "bbb=", map[string]any{
+		"LevelName": "beta test", "RandomSeed": int64(-7), "LastPlayed": int64(300),
+		"lastOpenedWithVersion": []int32{1, 20, 80},
+	})
+	locs := []Location{{Label: "Minecraft Bedrock", UsersDir: shared}, {Label: "1.20.80", VersionName: "1.20.80", UsersDir: iso}}
+
+	path := filepath.Join(root, "index.json")
+	ix := Load(path)
+	if res := ix.Refresh(locs); res.Worlds != 2 || res.Updated != 2 || res.Removed != 0 {
+		t.Fatalf("first refresh = %+v", res)
+	}
+	if err := ix.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	ix = Load(path)
+	if res := ix.Refresh(locs); res.Updated != 0 {
+		t.Fatalf("unchanged refresh re-read %d worlds", res.Updated)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(alpha, "level.dat"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if res := ix.Refresh(locs); res.Updated != 1 {
+		t.Fatalf("touched world refresh updated %d worlds", res.Updated)
+	}
+
+	got := ix.Search(types.WorldSearchQuery{SortBy: SortVersion})
+	if got.Total != 2 || got.Entries[0].LevelName != "beta test" || got.Entries[1].GameVersion != "1.21.50" {
+		t.Fatalf("version sort = %+v", got.Entries)
+	}
+	got = ix.Search(types.WorldSearchQuery{SortBy: SortLastPlayed, Desc: true, Limit: 1})
+	if got.Total != 2 || len(got.Entries) != 1 || got.Entries[0].LevelName != "beta test" {
+		t.Fatalf("last played page = %+v", got)
+	}
+	got = ix.Search(types.WorldSearchQuery{Query: "ALPHA"})
+	if got.Total != 1 || got.Entries[0].Seed != 42 || got.Entries[0].Player != "111" || got.Entries[0].Location != "Minecraft Bedrock" {
+		t.Fatalf("text search = %+v", got.Entries)
+	}
+	if got = ix.Search(types.WorldSearchQuery{VersionName: "1.20.80"}); got.Total != 1 {
+		t.Fatalf("version filter total = %d", got.Total)
+	}
+
+	if err := os.RemoveAll(alpha); err != nil {
+		t.Fatal(err)
+	}
+	if res := ix.Refresh(locs); res.Worlds != 1 || res.Removed != 1 {
+		t.Fatalf("refresh after delete = %+v", res)
+	}
+}
@@ -0,0 +1,265 @@
+package worldmap
+
+import (
+	"hash/fnv"
+	"image/color"
+	"strings"
+)
+
+func rgb(v uint32) color.NRGBA {
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xFF}
+}
+
+// transparentBlocks are skipped when looking for the top block of a column.
+var transparentBlocks = map[string]struct{}{
+	"air":               {},
+	"cave_air":          {},
+	"void_air":          {},
+	"structure_void":    {},
+	"light_block":       {},
+	"barrier":           {},
+	"glass":             {},
+	"glass_pane":        {},
+	"tripwire":          {},
+	"short_grass":       {},
+	"tallgrass":         {},
+	"tall_grass":        {},
+	"fern":              {},
+	"large_fern":        {},
+	"deadbush":          {},
+	"vine":              {},
+	"torch":             {},
+	"soul_torch":        {},
+	"redstone_wire":     {},
+	"rail":              {},
+	"golden_rail":       {},
+	"detector_rail":     {},
+	"activator_rail":    {},
+	"snow_layer":        {},
+	"frame":             {},
+	"glow_frame":        {},
+	"string":            {},
+	"seagrass":          {},
+	"kelp":              {},
+	"unknown":           {},
+	"reserved6":         {},
+	"info_update":       {},
+	"info_update2":      {},
+	"moving_block":      {},
+	"invisible_bedrock": {},
+}
+
+// Palette maps block names, without the minecraft: prefix, to their map colors. Blocks missing from the
+// table are colored by BlockColor from their name.
+var Palette = map[string]color.NRGBA{
+	"stone":                rgb(0x7D7D7D),
+	"granite":              rgb(0x9A6B55),
+	"diorite":              rgb(0xBDBDBD),
+	"andesite":             rgb(0x888888),
+	"deepslate":            rgb(0x505052),
+	"cobbled_deepslate":    rgb(0x4D4D50),
+	"tuff":                 rgb(0x6C6D66),
+	"calcite":              rgb(0xDFE0DC),
+	"bedrock":              rgb(0x555555),
+	"cobblestone":          rgb(0x7A7A7A),
+	"mossy_cobblestone":    rgb(0x6E7A5E),
+	"grass_block":          rgb(0x7CBD6B),
+	"grass":                rgb(0x7CBD6B),
+	"dirt":                 rgb(0x866043),
+	"coarse_dirt":          rgb(0x77553B),
+	"rooted_dirt":          rgb(0x90674C),
+	"podzol":               rgb(0x5B3F18),
+	"mycelium":             rgb(0x6F6265),
+	"dirt_with_roots":      rgb(0x90674C),
+	"grass_path":           rgb(0x947A41),
+	"dirt_path":            rgb(0x947A41),
+	"farmland":             rgb(0x5C3A1E),
+	"mud":                  rgb(0x3C393D),
+	"clay":                 rgb(0xA0A6B3),
+	"gravel":               rgb(0x847F7E),
+	"sand":                 rgb(0xDBCFA3),
+	"red_sand":             rgb(0xBE6621),
+	"sandstone":            rgb(0xD8CB9B),
+	"red_sandstone":        rgb(0xBA6320),
+	"snow":                 rgb(0xF9FEFE),
+	"powder_snow":          rgb(0xF8FDFD),
+	"ice":                  rgb(0x91B7FD),
+	"packed_ice":           rgb(0x8DB4FA),
+	"blue_ice":             rgb(0x74A7FD),
+	"water":                rgb(0x3F76E4),
+	"flowing_water":        rgb(0x3F76E4),
+	"lava":                 rgb(0xCF5B14),
+	"flowing_lava":         rgb(0xCF5B14),
+	"obsidian":             rgb(0x0F0A18),
+	"crying_obsidian":      rgb(0x200A3C),
+	"netherrack":           rgb(0x6F3634),
+	"crimson_nylium":       rgb(0x831F1F),
+	"warped_nylium":        rgb(0x2B7265),
+	"soul_sand":            rgb(0x513E32),
+	"soul_soil":            rgb(0x4B392E),
+	"basalt":               rgb(0x515156),
+	"blackstone":           rgb(0x2A2429),
+	"magma":                rgb(0x8E3F1F),
+	"glowstone":            rgb(0xAB8654),
+	"shroomlight":          rgb(0xF09249),
+	"nether_wart_block":    rgb(0x730302),
+	"warped_wart_block":    rgb(0x167879),
+	"quartz_ore":           rgb(0x75413E),
+	"nether_gold_ore":      rgb(0x73362A),
+	"ancient_debris":       rgb(0x5F403A),
+	"end_stone":            rgb(0xDBDE9E),
+	"end_bricks":           rgb(0xDAE0A2),
+	"purpur_block":         rgb(0xA97DA9),
+	"chorus_plant":         rgb(0x5D395D),
+	"chorus_flower":        rgb(0x977397),
+	"oak_leaves":           rgb(0x48B518),
+	"spruce_leaves":        rgb(0x619961),
+	"birch_leaves":         rgb(0x80A755),
+	"jungle_leaves":        rgb(0x30BB0B),
+	"acacia_leaves":        rgb(0x4F9C17),
+	"dark_oak_leaves":      rgb(0x3D8A15),
+	"mangrove_leaves":      rgb(0x5C9F2B),
+	"cherry_leaves":        rgb(0xE7B0C7),
+	"azalea_leaves":        rgb(0x5A7225),
+	"leaves":               rgb(0x48B518),
+	"leaves2":              rgb(0x4F9C17),
+	"oak_log":              rgb(0x6D5533),
+	"spruce_log":           rgb(0x3A2610),
+	"birch_log":            rgb(0xD8D7D2),
+	"jungle_log":           rgb(0x564419),
+	"acacia_log":           rgb(0x676157),
+	"dark_oak_log":         rgb(0x3C2E1A),
+	"log":                  rgb(0x6D5533),
+	"log2":                 rgb(0x676157),
+	"oak_planks":           rgb(0xA2834F),
+	"planks":               rgb(0xA2834F),
+	"cactus":               rgb(0x5A8A2B),
+	"pumpkin":              rgb(0xC47618),
+	"melon_block":          rgb(0x76A22C),
+	"hay_block":            rgb(0xA68A0C),
+	"moss_block":           rgb(0x596D2D),
+	"brown_mushroom_block": rgb(0x95704F),
+	"red_mushroom_block":   rgb(0xC82E2D),
+	"waterlily":            rgb(0x208030),
+	"bamboo":               rgb(0x5D8524),
+	"sugar_cane":           rgb(0x94C065),
+	"reeds":                rgb(0x94C065),
+	"coal_ore":             rgb(0x737373),
+	"iron_ore":             rgb(0x887D76),
+	"copper_ore":           rgb(0x7D7D6E),
+	"bricks":               rgb(0x966153),
+	"brick_block":          rgb(0x966153),
+	"stonebrick":           rgb(0x7A797A),
+	"stone_bricks":         rgb(0x7A797A),
+	"prismarine":           rgb(0x63A38F),
+	"sea_lantern":          rgb(0xACC8BE),
+	"iron_block":           rgb(0xDCDCDC),
+	"gold_block":           rgb(0xF6D03D),
+	"diamond_block":        rgb(0x62EDE4),
+	"emerald_block":        rgb(0x2ACB57),
+	"redstone_block":       rgb(0xAF1805),
+	"lapis_block":          rgb(0x1F4396),
+	"coal_block":           rgb(0x101010),
+	"quartz_block":         rgb(0xECE6DF),
+	"bookshelf":            rgb(0x6B5839),
+	"tnt":                  rgb(0xDB441A),
+	"crafting_table":       rgb(0x825F3A),
+	"chest":                rgb(0x9C6E24),
+	"furnace":              rgb(0x6E6E6E),
+	"bone_block":           rgb(0xE1DDC9),
+	"dripstone_block":      rgb(0x866B5C),
+	"amethyst_block":       rgb(0x8562BF),
+	"sculk":                rgb(0x0D1E24),
+	"mangrove_roots":       rgb(0x4B3B25),
+	"muddy_mangrove_roots": rgb(0x433B31),
+}
+
+// dyeColors gives the colors of the sixteen dyes, used for wool, concrete, terracotta, glass and the like.
+var dyeColors = map[string]color.NRGBA{
+	"white":      rgb(0xE9ECEC),
+	"orange":     rgb(0xF07613),
+	"magenta":    rgb(0xBD44B3),
+	"light_blue": rgb(0x3AAFD9),
+	"yellow":     rgb(0xF8C527),
+	"lime":       rgb(0x70B919),
+	"pink":       rgb(0xED8DAC),
+	"gray":       rgb(0x3E4447),
+	"light_gray": rgb(0x8E8E86),
+	"silver":     rgb(0x8E8E86),
+	"cyan":       rgb(0x158991),
+	"purple":     rgb(0x792AAC),
+	"blue":       rgb(0x35399D),
+	"brown":      rgb(0x724728),
+	"green":      rgb(0x546D1B),
+	"red":        rgb(0xA12722),
+	"black":      rgb(0x141519),
+}
+
+// familyRules color blocks that are not in Palette by a part of their name, checked in order.
+var familyRules = []struct {
+	part string
+	c    color.NRGBA
+}{
+	{"water", rgb(0x3F76E4)},
+	{"lava", rgb(0xCF5B14)},
+	{"leaves", rgb(0x48B518)},
+	{"_log", rgb(0x6D5533)},
+	{"_wood", rgb(0x6D5533)},
+	{"_stem", rgb(0x5A2E3E)},
+	{"hyphae", rgb(0x5A2E3E)},
+	{"planks", rgb(0xA2834F)},
+	{"_slab", rgb(0x9A9A9A)},
+	{"_stairs", rgb(0x9A9A9A)},
+	{"_ore", rgb(0x7D7D7D)},
+	{"deepslate", rgb(0x505052)},
+	{"copper", rgb(0xC06C50)},
+	{"coral", rgb(0xC8507A)},
+	{"sandstone", rgb(0xD8CB9B)},
+	{"brick", rgb(0x7A797A)},
+	{"flower", rgb(0xC9A23A)},
+	{"mushroom", rgb(0x95704F)},
+	{"snow", rgb(0xF9FEFE)},
+	{"ice", rgb(0x91B7FD)},
+	{"nylium", rgb(0x831F1F)},
+	{"quartz", rgb(0xECE6DF)},
+	{"purpur", rgb(0xA97DA9)},
+	{"prismarine", rgb(0x63A38F)},
+}
+
+// IsTransparent reports whether the top-down view looks through the block.
+func IsTransparent(name string) bool {
+	if _, ok := transparentBlocks[name]; ok {
+		return true
+	}
+	return strings.HasSuffix(name, "_button") || strings.HasSuffix(name, "_pressure_plate") ||
+		strings.HasSuffix(name, "_sign") || strings.HasSuffix(name, "_torch") || strings.HasSuffix(name, "_carpet") ||
+		strings.HasSuffix(name, "_glass") || strings.HasSuffix(name, "_glass_pane") || strings.HasSuffix(name, "_sapling")
+}
+
+// IsWater reports whether the block is a water source or flow.
+func IsWater(name string) bool {
+	return name == "water" || name == "flowing_water"
+}
+
+// BlockColor returns the map color of a block: its Palette entry, the dye color of colored building
+// blocks, the color of its block family, or a stable grey derived from the name.
+func BlockColor(name string) color.NRGBA {
+	if c, ok := Palette[name]; ok {
+		return c
+	}
+	for dye, c := range dyeColors {
+		// No dye name is a prefix of another followed by an underscore, so the map order does not matter.
+		if strings.HasPrefix(name, dye+"_") {
+			return c
+		}
+	}
+	for _, r := range familyRules {
+		if strings.Contains(name, r.part) {
+			return r.c
+		}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	v := uint8(0x60 + h.Sum32()%0x60)
+	return color.NRGBA{R: v, G: v, B: v, A: 0xFF}
+}
@@ -0,0 +1,231 @@
+// Package worldmap renders top-down maps of Bedrock worlds.
+//
+// The renderer reads the SubChunkPrefix records of a world database through internal/worlddb, finds the
+// top block of every column and colors it from a block palette, shading it by the height difference to
+// its northern neighbor the way in-game maps do. Large areas are rendered as tiles.
+package worldmap
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/liteldev/LeviLauncher/internal/worlddb"
+)
+
+// MaxRenderSize is the largest width or height, in blocks, that Render draws in one image. Larger areas
+// are split with Tiles.
+const MaxRenderSize = 2048
+
+// noHeight marks columns without a visible block.
+const noHeight = math.MinInt32
+
+// Source reads raw records of a world database; *worlddb.World implements it.
+type Source interface {
+	Get(key []byte) ([]byte, error)
+}
+
+// Area is a rectangle of block columns, bounds included.
+type Area struct {
+	MinX int32 `json:"minX"`
+	MinZ int32 `json:"minZ"`
+	MaxX int32 `json:"maxX"`
+	MaxZ int32 `json:"maxZ"`
+}
+
+func (a Area) Width() int  { return int(a.MaxX) - int(a.MinX) + 1 }
+func (a Area) Height() int { return int(a.MaxZ) - int(a.MinZ) + 1 }
+
+// Valid reports whether the area is non-empty.
+func (a Area) Valid() bool { return a.MaxX >= a.MinX && a.MaxZ >= a.MinZ }
+
+// Stats describes what Render found in the area.
+type Stats struct {
+	Chunks               int `json:"chunks"`
+	MissingChunks        int `json:"missingChunks"`
+	UnsupportedSubChunks int `json:"unsupportedSubChunks"`
+}
+
+// Tiles splits area into tiles of at most size blocks on each side, row by row from the north-west.
+func Tiles(area Area, size int) []Area {
+	if size <= 0 || !area.Valid() {
+		return nil
+	}
+	var out []Area
+	for z := int(area.MinZ); z <= int(area.MaxZ); z += size {
+		for x := int(area.MinX); x <= int(area.MaxX); x += size {
+			out = append(out, Area{
+				MinX: int32(x),
+				MinZ: int32(z),
+				MaxX: int32(min(x+size-1, int(area.MaxX))),
+				MaxZ: int32(min(z+size-1, int(area.MaxZ))),
+			})
+		}
+	}
+	return out
+}
+
+// subChunkRange returns the sub-chunk indexes a dimension uses, from the lowest to the highest.
+func subChunkRange(dim int32) (int8, int8) {
+	switch dim {
+	case worlddb.DimensionNether:
+		return 0, 7
+	case worlddb.DimensionEnd:
+		return 0, 15
+	}
+	return -4, 19
+}
+
+// floorDiv16 returns the chunk coordinate of a block coordinate.
+func floorDiv16(v int32) int32 {
+	return v >> 4
+}
+
+// loadColumn reads the sub-chunks of a chunk column, highest first.
+func loadColumn(src Source, dim int32, cx, cz int32, st *Stats) ([]*SubChunk, error) {
+	lo, hi := subChunkRange(dim)
+	var subs []*SubChunk
+	for idx := hi; idx >= lo; idx-- {
+		key := worlddb.ChunkKey{X: cx, Z: cz, Dimension: dim, Tag: worlddb.TagSubChunkPrefix, SubChunk: idx, HasSubChunk: true}
+		v, err := src.Get(key.Bytes())
+		if errors.Is(err, worlddb.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sc, err := DecodeSubChunk(v, idx)
+		if errors.Is(err, ErrUnsupportedSubChunk) {
+			st.UnsupportedSubChunks++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sub-chunk %d,%d[%d]: %w", cx, cz, idx, err)
+		}
+		subs = append(subs, sc)
+	}
+	sort.SliceStable(subs, func(i, j int) bool { return subs[i].Index > subs[j].Index })
+	return subs, nil
+}
+
+// column is the top of one block column as seen from above.
+type column struct {
+	c      color.NRGBA
+	height int
+	depth  int
+}
+
+// topOf finds the top visible block of the column at the local position. In the Nether the scan starts
+// below the bedrock roof, at the first open space.
+func topOf(subs []*SubChunk, x, z int, underRoof bool) (column, bool) {
+	waterTop := noHeight
+	for _, sc := range subs {
+		for y := 15; y >= 0; y-- {
+			name := sc.Block(x, y, z)
+			abs := int(sc.Index)*16 + y
+			if underRoof {
+				underRoof = !IsTransparent(name)
+				continue
+			}
+			if IsTransparent(name) {
+				continue
+			}
+			if IsWater(name) {
+				if waterTop == noHeight {
+					waterTop = abs
+				}
+				continue
+			}
+			if waterTop != noHeight {
+				return column{c: BlockColor("water"), height: waterTop, depth: waterTop - abs}, true
+			}
+			return column{c: BlockColor(name), height: abs}, true
+		}
+	}
+	if waterTop != noHeight {
+		return column{c: BlockColor("water"), height: waterTop, depth: 64}, true
+	}
+	return column{}, false
+}
+
+func shade(c color.NRGBA, f float64) color.NRGBA {
+	return color.NRGBA{R: uint8(float64(c.R) * f), G: uint8(float64(c.G) * f), B: uint8(float64(c.B) * f), A: c.A}
+}
+
+// Render draws the area of a dimension, one pixel per block with north up. Columns of chunks that were
+// never generated stay transparent.
+func Render(src Source, dim int32, area Area) (*image.NRGBA, Stats, error) {
+	var st Stats
+	if !area.Valid() {
+		return nil, st, fmt.Errorf("worldmap: empty area")
+	}
+	w, h := area.Width(), area.Height()
+	if w > MaxRenderSize || h > MaxRenderSize {
+		return nil, st, fmt.Errorf("worldmap: area %dx%d exceeds %d blocks", w, h, MaxRenderSize)
+	}
+	cols := make([]column, w*h)
+	for i := range cols {
+		cols[i].height = noHeight
+	}
+	for cz := floorDiv16(area.MinZ); cz <= floorDiv16(area.MaxZ); cz++ {
+		for cx := floorDiv16(area.MinX); cx <= floorDiv16(area.MaxX); cx++ {
+			subs, err := loadColumn(src, dim, cx, cz, &st)
+			if err != nil {
+				return nil, st, err
+			}
+			if len(subs) == 0 {
+				st.MissingChunks++
+				continue
+			}
+			st.Chunks++
+			for lz := 0; lz < 16; lz++ {
+				bz := cz*16 + int32(lz)
+				if bz < area.MinZ || bz > area.MaxZ {
+					continue
+				}
+				for lx := 0; lx < 16; lx++ {
+					bx := cx*16 + int32(lx)
+					if bx < area.MinX || bx > area.MaxX {
+						continue
+					}
+					if col, ok := topOf(subs, lx, lz, dim == worlddb.DimensionNether); ok {
+						cols[int(bz-area.MinZ)*w+int(bx-area.MinX)] = col
+					}
+				}
+			}
+		}
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for z := 0; z < h; z++ {
+		for x := 0; x < w; x++ {
+			col := cols[z*w+x]
+			if col.height == noHeight {
+				continue
+			}
+			f := 0.86
+			switch {
+			case col.depth > 0:
+				// Water is shaded by its depth instead, so shallow water shows up lighter.
+				switch {
+				case col.depth <= 2:
+					f = 1.0
+				case col.depth > 6:
+					f = 0.71
+				}
+			case z > 0 && cols[(z-1)*w+x].height != noHeight:
+				north := cols[(z-1)*w+x].height
+				if col.height > north {
+					f = 1.0
+				} else if col.height < north {
+					f = 0.71
+				}
+			}
+			img.SetNRGBA(x, z, shade(col.c, f))
+		}
+	}
+	return img, st, nil
+}
@@ -0,0 +1,113 @@
+package worldmap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/liteldev/LeviLauncher/internal/nbt"
+)
+
+// ErrUnsupportedSubChunk is returned for sub-chunks in the pre-1.2.13 format, which store numeric block
+// ids instead of a block palette.
+var ErrUnsupportedSubChunk = errors.New("worldmap: unsupported sub-chunk format")
+
+// SubChunk is the first block layer of a 16x16x16 sub-chunk. The second layer, when present, only holds
+// the liquid of waterlogged blocks and is not needed for a top-down view.
+type SubChunk struct {
+	Index   int8
+	palette []string
+	blocks  [4096]uint16
+}
+
+// Block returns the name of the block at the sub-chunk local position, without the minecraft: prefix.
+func (s *SubChunk) Block(x, y, z int) string {
+	i := s.blocks[x<<8|z<<4|y]
+	if int(i) >= len(s.palette) {
+		return "air"
+	}
+	return s.palette[i]
+}
+
+// DecodeSubChunk decodes a SubChunkPrefix record. index is the sub-chunk index from the key, used for
+// the formats that do not repeat it in the value.
+func DecodeSubChunk(data []byte, index int8) (*SubChunk, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("worldmap: empty sub-chunk")
+	}
+	s := &SubChunk{Index: index}
+	r := bytes.NewBuffer(data[1:])
+	switch data[0] {
+	case 1:
+	case 8, 9:
+		count, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if data[0] == 9 {
+			y, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			s.Index = int8(y)
+		}
+		if count == 0 {
+			s.palette = []string{"air"}
+			return s, nil
+		}
+	default:
+		return nil, ErrUnsupportedSubChunk
+	}
+	if err := s.readStorage(r); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SubChunk) readStorage(r *bytes.Buffer) error {
+	header, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	bits := int(header >> 1)
+	paletteLen := 1
+	if bits != 0 {
+		switch bits {
+		case 1, 2, 3, 4, 5, 6, 8, 16:
+		default:
+			return fmt.Errorf("worldmap: invalid bits per block %d", bits)
+		}
+		perWord := 32 / bits
+		words := (4096 + perWord - 1) / perWord
+		raw := r.Next(words * 4)
+		if len(raw) != words*4 {
+			return fmt.Errorf("worldmap: truncated block storage")
+		}
+		mask := uint32(1)<<bits - 1
+		for i := range s.blocks {
+			w := binary.LittleEndian.Uint32(raw[(i/perWord)*4:])
+			s.blocks[i] = uint16(w >> (uint(i%perWord) * uint(bits)) & mask)
+		}
+		n := r.Next(4)
+		if len(n) != 4 {
+			return fmt.Errorf("worldmap: truncated palette")
+		}
+		paletteLen = int(int32(binary.LittleEndian.Uint32(n)))
+		if paletteLen < 0 || paletteLen > 4096 {
+			return fmt.Errorf("worldmap: invalid palette size %d", paletteLen)
+		}
+	}
+	dec := nbt.NewDecoderWithEncoding(r, nbt.LittleEndian)
+	s.palette = make([]string, 0, paletteLen)
+	for range paletteLen {
+		var entry map[string]any
+		if err := dec.Decode(&entry); err != nil {
+			return fmt.Errorf("worldmap: palette: %w", err)
+		}
+		name, _ := entry["name"].(string)
+		s.palette = append(s.palette, strings.TrimPrefix(name, "minecraft:"))
+	}
+	return nil
+}
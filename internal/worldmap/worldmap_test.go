@@ -0,0 +1,127 @@
+package worldmap
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/liteldev/LeviLauncher/internal/nbt"
+	"github.com/liteldev/LeviLauncher/internal/worlddb"
+)
+
+type mapSource map[string][]byte
+
+func (m mapSource) Get(key []byte) ([]byte, error) {
+	if v, ok := m[string(key)]; ok {
+		return v, nil
+	}
+	return nil, worlddb.ErrNotFound
+}
+
+// encodeSubChunk writes a version 9 sub-chunk with 4 bits per block.
+func encodeSubChunk(t *testing.T, index int8, palette []string, block func(x, y, z int) int) []byte {
+	t.Helper()
+	out := []byte{9, 1, byte(index), 4 << 1}
+	var words [512]uint32
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := 0; y < 16; y++ {
+				i := x<<8 | z<<4 | y
+				words[i/8] |= uint32(block(x, y, z)) << (uint(i%8) * 4)
+			}
+		}
+	}
+	for _, w := range words {
+		out = binary.LittleEndian.AppendUint32(out, w)
+	}
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(palette)))
+	for _, name := range palette {
+		b, err := nbt.MarshalEncoding(map[string]any{
+			"name":    "minecraft:" + name,
+			"states":  map[string]any{"dirt_type": "normal"},
+			"version": int32(18100737),
+		}, nbt.LittleEndian)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, b...)
+	}
+	return out
+}
+
+func subKey(cx, cz int32, idx int8) string {
+	return string(worlddb.ChunkKey{X: cx, Z: cz, Tag: worlddb.TagSubChunkPrefix, SubChunk: idx, HasSubChunk: true}.Bytes())
+}
+
+func TestDecodeSubChunk(t *testing.T) {
+	data := encodeSubChunk(t, 3, []string{"air", "stone", "dirt"}, func(x, y, z int) int {
+		if y == 0 {
+			return 1
+		}
+		if x == 5 && y == 1 && z == 7 {
+			return 2
+		}
+		return 0
+	})
+	sc, err := DecodeSubChunk(data, 0)
+	if err != nil {
+		t.Fatalf("DecodeSubChunk: %v", err)
+	}
+	if sc.Index != 3 || sc.Block(0, 0, 0) != "stone" || sc.Block(5, 1, 7) != "dirt" || sc.Block(5, 2, 7) != "air" {
+		t.Fatalf("decoded sub-chunk index=%d blocks=%s,%s,%s", sc.Index, sc.Block(0, 0, 0), sc.Block(5, 1, 7), sc.Block(5, 2, 7))
+	}
+	if _, err := DecodeSubChunk([]byte{0, 1, 2}, 0); err != ErrUnsupportedSubChunk {
+		t.Fatalf("legacy sub-chunk error = %v", err)
+	}
+}
+
+func TestRenderShadesHeightAndWater(t *testing.T) {
+	src := mapSource{
+		subKey(0, 0, 4): encodeSubChunk(t, 4, []string{"air", "stone", "grass_block", "water"}, func(x, y, z int) int {
+			switch {
+			case y == 0:
+				return 1
+			case x < 8 && y == 1 && z >= 8:
+				return 2
+			case x >= 8 && y <= 2:
+				return 3
+			}
+			return 0
+		}),
+		subKey(0, 0, 0): {0, 1, 2, 3},
+	}
+	img, st, err := Render(src, worlddb.DimensionOverworld, Area{MinX: 0, MinZ: 0, MaxX: 31, MaxZ: 15})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if st.Chunks != 1 || st.MissingChunks != 1 || st.UnsupportedSubChunks != 1 {
+		t.Fatalf("stats = %+v", st)
+	}
+	if got := img.NRGBAAt(0, 8); got != shade(BlockColor("grass_block"), 1.0) {
+		t.Fatalf("grass rising to the north = %v", got)
+	}
+	if got := img.NRGBAAt(0, 9); got != shade(BlockColor("grass_block"), 0.86) {
+		t.Fatalf("flat grass = %v", got)
+	}
+	if got := img.NRGBAAt(0, 3); got != shade(BlockColor("stone"), 0.86) {
+		t.Fatalf("flat stone = %v", got)
+	}
+	if got := img.NRGBAAt(9, 9); got != shade(BlockColor("water"), 1.0) {
+		t.Fatalf("shallow water = %v", got)
+	}
+	if got := img.NRGBAAt(20, 3); got.A != 0 {
+		t.Fatalf("missing chunk should stay transparent, got %v", got)
+	}
+}
+
+func TestTilesAndColors(t *testing.T) {
+	tiles := Tiles(Area{MinX: -10, MinZ: 0, MaxX: 9, MaxZ: 14}, 8)
+	if len(tiles) != 6 || tiles[2] != (Area{MinX: 6, MinZ: 0, MaxX: 9, MaxZ: 7}) || tiles[5].MaxZ != 14 {
+		t.Fatalf("tiles = %+v", tiles)
+	}
+	if BlockColor("light_blue_wool") != dyeColors["light_blue"] || BlockColor("red_concrete") != dyeColors["red"] {
+		t.Fatalf("dye colors not applied")
+	}
+	if BlockColor("mystery_block") != BlockColor("mystery_block") || BlockColor("stripped_oak_log") != rgb(0x6D5533) {
+		t.Fatalf("fallback colors not stable")
+	}
+}
@@ -326,7 +326,9 @@ func startSingleInstanceServer(versionService *VersionService) {
 							continue
 						}
 						go func(v string) {
-							_ = versionService.LaunchVersionByNameForce(v)
+							if errCode := versionService.LaunchVersionByNameForce(v); errCode != "" && !mcservice.PromptPinnedWorld(v, true) {
+								log.Printf("Single-instance launch of %q failed: %s", v, errCode)
+							}
 						}(payload)
 					}
 				}
@@ -392,6 +394,11 @@ func init() {
 	application.RegisterEvent[string](mcservice.EventExtractDone)
 	application.RegisterEvent[types.ExtractProgress](mcservice.EventExtractProgress)
 	application.RegisterEvent[types.InstanceBackupRestoreProgress](mcservice.EventInstanceBackupRestoreProgress)
+	application.RegisterEvent[types.ContentTransferProgress](mcservice.EventContentTransferProgress)
+	application.RegisterEvent[types.IsolationMigrationProgress](mcservice.EventIsolationMigrationProgress)
+	application.RegisterEvent[types.WorldCompatLaunchPrompt](mcservice.EventWorldCompatLaunchPrompt)
+	application.RegisterEvent[types.DevPackSyncEvent](mcservice.EventDevPackSync)
+	application.RegisterEvent[mcservice.AutoBackupEvent](mcservice.EventWorldAutoBackup)
 	// launch
 	application.RegisterEvent[struct{}](launch.EventMcLaunchStart)
 	application.RegisterEvent[struct{}](launch.EventMcLaunchDone)
@@ -449,6 +456,10 @@ func main() {
 	update.Init()
 	startup.Mark("config loaded")
 	mc := NewMinecraft()
+	launch.SetSessionHooks(launch.SessionHooks{
+		OnStart: mcservice.AutoBackupSessionStart,
+		OnExit:  mcservice.AutoBackupSessionExit,
+	})
 	contentService := NewContentService(mc)
 	modsService := NewModsService(mc)
 	userService := NewUserService(mc)
@@ -494,8 +505,11 @@ func main() {
 	startSingleInstanceServer(versionService)
 
 	if strings.TrimSpace(autoLaunchVersion) != "" && initialURL == "/" {
-		_ = versionService.LaunchVersionByName(autoLaunchVersion)
-		return
+		// A launch stopped by the pinned world opens the window instead, which shows the warning.
+		if errCode := versionService.LaunchVersionByName(autoLaunchVersion); errCode == "" || !mcservice.PromptPinnedWorld(autoLaunchVersion, false) {
+			return
+		}
+		autoLaunchVersion = ""
 	}
 
 	w := defaultWindowWidth
@@ -563,7 +577,9 @@ func main() {
 
 	if strings.TrimSpace(autoLaunchVersion) != "" {
 		go func() {
-			_ = versionService.LaunchVersionByName(autoLaunchVersion)
+			if errCode := versionService.LaunchVersionByName(autoLaunchVersion); errCode != "" {
+				mcservice.PromptPinnedWorld(autoLaunchVersion, false)
+			}
 		}()
 	}
 
@@ -31,6 +31,7 @@ import (
 	"github.com/liteldev/LeviLauncher/internal/update"
 	"github.com/liteldev/LeviLauncher/internal/vcruntime"
 	"github.com/liteldev/LeviLauncher/internal/versionlaunch"
+	"github.com/liteldev/LeviLauncher/internal/versions"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
@@ -180,6 +181,34 @@ type ScreenshotInfo struct {
 	Path        string `json:"path"`
 	Dir         string `json:"dir"`
 	CaptureTime int64  `json:"captureTime"`
+	ModTime     int64  `json:"modTime"`
+	Size        int64  `json:"size"`
+	World       string `json:"world"`
+}
+
+type ScreenshotQuery struct {
+	World  string `json:"world"`
+	Oldest bool   `json:"oldest"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+}
+
+type ScreenshotGroup struct {
+	World string `json:"world"`
+	Count int    `json:"count"`
+}
+
+type ScreenshotPage struct {
+	Items  []ScreenshotInfo  `json:"items"`
+	Total  int               `json:"total"`
+	Groups []ScreenshotGroup `json:"groups"`
+}
+
+type ScreenshotBulkResult struct {
+	Done      int      `json:"done"`
+	Paths     []string `json:"paths"`
+	Failed    []string `json:"failed"`
+	ErrorCode string   `json:"errorCode"`
 }
 
 func (a *Minecraft) ListServers(versionName string, player string) []types.Server {
@@ -239,17 +268,29 @@ type contentService interface {
 	ImportMcpackPathWithPlayer(name string, player string, path string, overwrite bool) string
 	IsMcpackSkinPackPath(path string) bool
 	IsMcpackSkinPack(data []byte) bool
+	ImportJavaResourcePackPath(name string, path string) types.JavaPackConversion
 	ImportMcworld(name string, player string, fileName string, data []byte, overwrite bool) string
 	ImportMcworldPath(name string, player string, path string, overwrite bool) string
 	TransferPackToVersion(sourceVersionName string, sourcePackPath string, targetVersionName string, overwrite bool) string
 	TransferWorldToVersion(sourceVersionName string, sourcePlayer string, sourceWorldPath string, targetVersionName string, targetPlayer string) string
+	CloneWorld(req types.WorldCloneRequest) types.WorldCloneResult
+	PreviewContentTransfer(req types.ContentTransferRequest) types.ContentTransferPreview
+	TransferContent(ctx context.Context, req types.ContentTransferRequest, progress func(types.ContentTransferProgress)) types.ContentTransferResult
 	GetPackInfo(dir string) types.PackInfo
+	ListSkinPacks(versionName string, player string) []types.SkinPackInfo
+	ReadSkinPack(path string) types.SkinPackInfo
+	CreateSkinPack(versionName string, player string, req types.SkinPackBuildRequest) string
+	ValidatePack(path string) types.PackLintReport
 	UpdateResourcePackMaterialBins(versionName string, packPath string) contentmgr.MaterialUpdateResult
 	CheckResourcePackMaterialCompatibility(versionName string, packPath string) contentmgr.MaterialCompatResult
 	DeletePack(name string, path string) string
 	DeleteWorld(name string, path string) string
 	ListScreenshots(versionName string, player string) []contentmgr.ScreenshotInfo
 	DeleteScreenshot(versionName string, player string, path string) string
+	ListScreenshotsPage(versionName string, player string, query contentmgr.ScreenshotQuery) contentmgr.ScreenshotPage
+	GetScreenshotThumbnail(path string, size int) string
+	ExportScreenshots(versionName string, player string, paths []string, destDir string) contentmgr.ScreenshotBulkResult
+	DeleteScreenshots(versionName string, player string, paths []string) contentmgr.ScreenshotBulkResult
 }
 
 type MinecraftDeps struct {
@@ -289,7 +330,9 @@ func NewMinecraftWithDeps(deps MinecraftDeps) *Minecraft {
 			GetVersionGameInfo: func(name string) string {
 				return mcservice.GetVersionMeta(name).GameVersion
 			},
-			ListDir: mcservice.ListDir,
+			ListDir:          mcservice.ListDir,
+			CheckWorldCompat: mcservice.CheckWorldCompatibility,
+			BackupWorld:      mcservice.BackupWorldWithVersion,
 		})
 	}
 	return &Minecraft{
@@ -395,6 +438,7 @@ func (a *Minecraft) startupEssential() {
 func (a *Minecraft) startupDeferred() {
 	launch.EnsureGamingServicesInstalled(a.ctx)
 	mcservice.ReconcileRegisteredFlags()
+	mcservice.ResumeDevPackWatches()
 }
 
 func (a *Minecraft) EnsureGameInputInteractive() { go gameinput.EnsureInteractive(a.ctx) }
@@ -483,6 +527,214 @@ func (a *Minecraft) WriteWorldLevelDatFieldsAt(worldDir string, args map[string]
 	return mcservice.WriteWorldLevelDatFieldsAt(worldDir, args)
 }
 
+func (a *Minecraft) ListWorldDBRecords(worldDir string, kind string, offset int, limit int) types.WorldDBRecordPage {
+	return mcservice.ListWorldDBRecords(worldDir, kind, offset, limit)
+}
+
+func (a *Minecraft) ReadWorldDBRecord(worldDir string, keyHex string) types.WorldDBRecordValue {
+	return mcservice.ReadWorldDBRecord(worldDir, keyHex)
+}
+
+func (a *Minecraft) GetWorldStats(worldDir string) types.WorldStats {
+	return mcservice.GetWorldStats(worldDir)
+}
+
+func (a *Minecraft) PruneWorld(req types.WorldPruneRequest) types.WorldPruneResult {
+	return mcservice.PruneWorld(req)
+}
+
+func (a *Minecraft) ListWorldPlayers(worldDir string) []types.WorldPlayer {
+	return mcservice.ListWorldPlayers(worldDir)
+}
+
+func (a *Minecraft) WriteWorldPlayer(worldDir string, player types.WorldPlayer) types.WorldPlayerWriteResult {
+	return mcservice.WriteWorldPlayer(worldDir, player)
+}
+
+func (a *Minecraft) GetWorldGameRules(worldDir string) types.WorldGameRules {
+	return mcservice.GetWorldGameRules(worldDir)
+}
+
+func (a *Minecraft) SetWorldGameRules(worldDir string, rules []types.GameRule) string {
+	return mcservice.SetWorldGameRules(worldDir, rules)
+}
+
+func (a *Minecraft) ResetWorldGameRules(worldDir string) string {
+	return mcservice.ResetWorldGameRules(worldDir)
+}
+
+func (a *Minecraft) SetWorldExperiments(worldDir string, enabled map[string]bool) string {
+	return mcservice.SetWorldExperiments(worldDir, enabled)
+}
+
+func (a *Minecraft) SnapshotWorld(worldDir string, versionName string, label string) types.WorldSnapshotResult {
+	return mcservice.SnapshotWorld(worldDir, versionName, label)
+}
+
+func (a *Minecraft) GetWorldBackupID(worldDir string, versionName string) string {
+	return mcservice.WorldBackupID(worldDir, versionName)
+}
+
+func (a *Minecraft) ListWorldSnapshots(worldID string) []types.WorldSnapshot {
+	return mcservice.ListWorldSnapshots(worldID)
+}
+
+func (a *Minecraft) RestoreWorldSnapshot(worldID string, snapshotID string, targetWorldsDir string) types.WorldSnapshotPathResult {
+	return mcservice.RestoreWorldSnapshot(worldID, snapshotID, targetWorldsDir)
+}
+
+func (a *Minecraft) ExportWorldSnapshot(worldID string, snapshotID string, destPath string) types.WorldSnapshotPathResult {
+	return mcservice.ExportWorldSnapshot(worldID, snapshotID, destPath)
+}
+
+func (a *Minecraft) DeleteWorldSnapshot(worldID string, snapshotID string) string {
+	return mcservice.DeleteWorldSnapshot(worldID, snapshotID)
+}
+
+func (a *Minecraft) GetWorldBackupRetention(worldID string) types.WorldBackupRetention {
+	return mcservice.GetWorldBackupRetention(worldID)
+}
+
+func (a *Minecraft) SetWorldBackupRetention(worldID string, retention types.WorldBackupRetention) string {
+	return mcservice.SetWorldBackupRetention(worldID, retention)
+}
+
+func (a *Minecraft) RenderWorldMap(req types.WorldMapRequest) types.WorldMapResult {
+	return mcservice.RenderWorldMap(req)
+}
+
+func (a *Minecraft) GetWorldMapDataUrl(worldDir string, dimension int32, radius int) string {
+	return mcservice.GetWorldMapDataUrl(worldDir, dimension, radius)
+}
+
+func (a *Minecraft) ListWorldStructures(worldDir string) []types.StructureEntry {
+	return mcservice.ListWorldStructures(worldDir)
+}
+
+func (a *Minecraft) ListPackStructures(packDir string) []types.StructureEntry {
+	return mcservice.ListPackStructures(packDir)
+}
+
+func (a *Minecraft) InspectStructure(path string) types.StructureInfo {
+	return mcservice.InspectStructure(path)
+}
+
+func (a *Minecraft) InspectWorldStructure(worldDir string, id string) types.StructureInfo {
+	return mcservice.InspectWorldStructure(worldDir, id)
+}
+
+func (a *Minecraft) ImportStructure(packDir string, srcPath string, id string, overwrite bool) types.StructureImportResult {
+	return mcservice.ImportStructure(packDir, srcPath, id, overwrite)
+}
+
+func (a *Minecraft) ExportWorldStructures(worldDir string, ids []string, destDir string) types.StructureExportResult {
+	return mcservice.ExportWorldStructures(worldDir, ids, destDir)
+}
+
+func (a *Minecraft) RefreshWorldIndex() types.WorldIndexRefreshResult {
+	return mcservice.RefreshWorldIndex()
+}
+
+func (a *Minecraft) SearchWorlds(query types.WorldSearchQuery) types.WorldSearchResult {
+	return mcservice.SearchWorlds(query)
+}
+
+func (a *Minecraft) CheckWorldCompatibility(worldDir string, versionName string) types.WorldCompatResult {
+	return mcservice.CheckWorldCompatibility(worldDir, versionName)
+}
+
+func (a *Minecraft) GetVersionPinnedWorld(name string) string {
+	return mcservice.GetVersionPinnedWorld(name)
+}
+
+func (a *Minecraft) SetVersionPinnedWorld(name string, worldDir string) string {
+	return mcservice.SetVersionPinnedWorld(name, worldDir)
+}
+
+func (a *Minecraft) CheckPinnedWorld(name string) types.WorldCompatResult {
+	return mcservice.CheckPinnedWorld(name)
+}
+
+func (a *Minecraft) CheckScriptCompatibility(versionName string, worldDir string) types.ScriptCompatReport {
+	return mcservice.CheckScriptCompatibility(versionName, worldDir)
+}
+
+func (a *Minecraft) InstallScriptModuleTable(path string) string {
+	return mcservice.InstallScriptModuleTable(path)
+}
+
+func (a *Minecraft) PreviewIsolationMigration(name string, enable bool) types.IsolationMigrationPreview {
+	return mcservice.PreviewIsolationMigration(name, enable)
+}
+
+func (a *Minecraft) MigrateIsolation(name string, req types.IsolationMigrationRequest) types.IsolationMigrationResult {
+	return mcservice.MigrateIsolation(name, req)
+}
+
+func (a *Minecraft) GetGameOptionSchema() []types.GameOptionDef {
+	return mcservice.GetGameOptionSchema()
+}
+
+func (a *Minecraft) ReadGameOptions(versionName string, player string) types.GameOptions {
+	return mcservice.ReadGameOptions(versionName, player)
+}
+
+func (a *Minecraft) SetGameOptions(versionName string, player string, values map[string]string) string {
+	return mcservice.SetGameOptions(versionName, player, values)
+}
+
+func (a *Minecraft) CopyGameOptions(srcVersion string, srcPlayer string, dstVersion string, dstPlayer string, keys []string) string {
+	return mcservice.CopyGameOptions(srcVersion, srcPlayer, dstVersion, dstPlayer, keys)
+}
+
+func (a *Minecraft) ListOptionProfiles() types.OptionProfileList {
+	return mcservice.ListOptionProfiles()
+}
+
+func (a *Minecraft) CreateOptionProfile(versionName string, player string, req types.OptionProfileRequest) types.OptionProfileResult {
+	return mcservice.CreateOptionProfile(versionName, player, req)
+}
+
+func (a *Minecraft) DeleteOptionProfile(id string) string {
+	return mcservice.DeleteOptionProfile(id)
+}
+
+func (a *Minecraft) SetDefaultOptionProfile(id string) string {
+	return mcservice.SetDefaultOptionProfile(id)
+}
+
+func (a *Minecraft) ApplyOptionProfile(id string, versionName string, player string) string {
+	return mcservice.ApplyOptionProfile(id, versionName, player)
+}
+
+func (a *Minecraft) ListWorldBackups() []types.WorldBackupGroup {
+	return mcservice.ListWorldBackups()
+}
+
+func (a *Minecraft) InspectWorldBackup(path string) types.WorldBackupLevelDat {
+	return mcservice.InspectWorldBackup(path)
+}
+
+func (a *Minecraft) RestoreWorldBackup(req types.WorldBackupRestoreRequest) types.WorldBackupRestoreResult {
+	return mcservice.RestoreWorldBackup(req)
+}
+
+func (a *Minecraft) DeleteWorldBackup(path string) string {
+	return mcservice.DeleteWorldBackup(path)
+}
+
+func (a *Minecraft) PruneWorldBackups(req types.WorldBackupPruneRequest) types.WorldBackupPruneResult {
+	return mcservice.PruneWorldBackups(req)
+}
+
+func (a *Minecraft) GetVersionAutoBackup(name string) versions.AutoBackupPolicy {
+	return mcservice.GetVersionAutoBackup(name)
+}
+
+func (a *Minecraft) SetVersionAutoBackup(name string, policy versions.AutoBackupPolicy) string {
+	return mcservice.SetVersionAutoBackup(name, policy)
+}
+
 func (a *Minecraft) WriteTempFile(name string, data []byte) string {
 	tempDir := filepath.Join(os.TempDir(), "LeviLauncher", "TempImports")
 	_ = os.MkdirAll(tempDir, 0755)
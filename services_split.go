@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/liteldev/LeviLauncher/internal/contentmgr"
 	"github.com/liteldev/LeviLauncher/internal/mcservice"
 	"github.com/liteldev/LeviLauncher/internal/mods"
 	"github.com/liteldev/LeviLauncher/internal/packages"
@@ -113,6 +114,13 @@ func (s *ContentService) IsMcpackSkinPack(data []byte) bool {
 	return s.manager.IsMcpackSkinPack(data)
 }
 
+func (s *ContentService) ImportJavaResourcePackPath(name string, path string) types.JavaPackConversion {
+	if s.manager == nil {
+		return types.JavaPackConversion{Untranslated: []types.JavaPackAsset{}, ErrorCode: "ERR_ACCESS_VERSIONS_DIR"}
+	}
+	return s.manager.ImportJavaResourcePackPath(name, path)
+}
+
 func (s *ContentService) ImportMcworld(name string, player string, fileName string, data []byte, overwrite bool) string {
 	if s.manager == nil {
 		return "ERR_ACCESS_VERSIONS_DIR"
@@ -138,9 +146,59 @@ func (s *ContentService) TransferWorldToVersion(sourceVersionName string, source
 	if s.manager == nil {
 		return "ERR_ACCESS_VERSIONS_DIR"
 	}
+	check := mcservice.CheckWorldCompatibility(sourceWorldPath, targetVersionName)
+	if code := mcservice.WorldCompatErrorCode(check); code != "" {
+		return code
+	}
 	return s.manager.TransferWorldToVersion(sourceVersionName, sourcePlayer, sourceWorldPath, targetVersionName, targetPlayer)
 }
 
+// TransferWorldToVersionConfirmed transfers a world after the user accepted the compatibility warning
+// of TransferWorldToVersion, backing the world up first when backup is set.
+func (s *ContentService) TransferWorldToVersionConfirmed(sourceVersionName string, sourcePlayer string, sourceWorldPath string, targetVersionName string, targetPlayer string, backup bool) types.WorldCompatResult {
+	if s.manager == nil {
+		return types.WorldCompatResult{ErrorCode: "ERR_ACCESS_VERSIONS_DIR"}
+	}
+	res := mcservice.ConfirmWorldCompatibility(sourceWorldPath, sourceVersionName, targetVersionName, backup)
+	if res.ErrorCode == "ERR_BACKUP_FAILED" {
+		return res
+	}
+	res.ErrorCode = s.manager.TransferWorldToVersion(sourceVersionName, sourcePlayer, sourceWorldPath, targetVersionName, targetPlayer)
+	return res
+}
+
+func (s *ContentService) PreviewContentTransfer(req types.ContentTransferRequest) types.ContentTransferPreview {
+	if s.manager == nil {
+		return types.ContentTransferPreview{Conflicts: []types.ContentTransferConflict{}, ErrorCode: "ERR_ACCESS_VERSIONS_DIR"}
+	}
+	return s.manager.PreviewContentTransfer(req)
+}
+
+// TransferContent runs a batch transfer, reporting progress through content.transfer.progress events.
+// Only one batch runs at a time.
+func (s *ContentService) TransferContent(req types.ContentTransferRequest) types.ContentTransferResult {
+	if s.manager == nil {
+		return types.ContentTransferResult{Status: "failed", Items: []types.ContentTransferItemResult{}, ErrorCode: "ERR_ACCESS_VERSIONS_DIR"}
+	}
+	ctx, ok := mcservice.StartContentTransfer()
+	if !ok {
+		return types.ContentTransferResult{Status: "failed", Items: []types.ContentTransferItemResult{}, ErrorCode: "ERR_TRANSFER_RUNNING"}
+	}
+	defer mcservice.FinishContentTransfer()
+	return s.manager.TransferContent(ctx, req, mcservice.EmitContentTransferProgress)
+}
+
+func (s *ContentService) CancelContentTransfer() {
+	mcservice.CancelContentTransfer()
+}
+
+func (s *ContentService) CloneWorld(req types.WorldCloneRequest) types.WorldCloneResult {
+	if s.manager == nil {
+		return types.WorldCloneResult{ErrorCode: "ERR_ACCESS_VERSIONS_DIR"}
+	}
+	return s.manager.CloneWorld(req)
+}
+
 func (s *ContentService) GetPackInfo(dir string) types.PackInfo {
 	if s.manager == nil {
 		return types.PackInfo{}
@@ -148,6 +206,34 @@ func (s *ContentService) GetPackInfo(dir string) types.PackInfo {
 	return s.manager.GetPackInfo(dir)
 }
 
+func (s *ContentService) ValidatePack(path string) types.PackLintReport {
+	if s.manager == nil {
+		return types.PackLintReport{}
+	}
+	return s.manager.ValidatePack(path)
+}
+
+func (s *ContentService) ListSkinPacks(versionName string, player string) []types.SkinPackInfo {
+	if s.manager == nil {
+		return []types.SkinPackInfo{}
+	}
+	return s.manager.ListSkinPacks(versionName, player)
+}
+
+func (s *ContentService) ReadSkinPack(path string) types.SkinPackInfo {
+	if s.manager == nil {
+		return types.SkinPackInfo{}
+	}
+	return s.manager.ReadSkinPack(path)
+}
+
+func (s *ContentService) CreateSkinPack(versionName string, player string, req types.SkinPackBuildRequest) string {
+	if s.manager == nil {
+		return "ERR_ACCESS_VERSIONS_DIR"
+	}
+	return s.manager.CreateSkinPack(versionName, player, req)
+}
+
 func (s *ContentService) UpdateResourcePackMaterialBins(versionName string, packPath string) ResourcePackMaterialUpdateResult {
 	if s.manager == nil {
 		return ResourcePackMaterialUpdateResult{Error: "ERR_ACCESS_VERSIONS_DIR"}
@@ -194,6 +280,18 @@ func (s *ContentService) DeleteWorld(name string, path string) string {
 	return s.manager.DeleteWorld(name, path)
 }
 
+func toScreenshotInfo(v contentmgr.ScreenshotInfo) ScreenshotInfo {
+	return ScreenshotInfo{
+		Name:        v.Name,
+		Path:        v.Path,
+		Dir:         v.Dir,
+		CaptureTime: v.CaptureTime,
+		ModTime:     v.ModTime,
+		Size:        v.Size,
+		World:       v.World,
+	}
+}
+
 func (s *ContentService) ListScreenshots(versionName string, player string) []ScreenshotInfo {
 	if s.manager == nil {
 		return []ScreenshotInfo{}
@@ -201,12 +299,7 @@ func (s *ContentService) ListScreenshots(versionName string, player string) []Sc
 	mgr := s.manager.ListScreenshots(versionName, player)
 	out := make([]ScreenshotInfo, len(mgr))
 	for i, v := range mgr {
-		out[i] = ScreenshotInfo{
-			Name:        v.Name,
-			Path:        v.Path,
-			Dir:         v.Dir,
-			CaptureTime: v.CaptureTime,
-		}
+		out[i] = toScreenshotInfo(v)
 	}
 	return out
 }
@@ -218,6 +311,112 @@ func (s *ContentService) DeleteScreenshot(versionName string, player string, pat
 	return s.manager.DeleteScreenshot(versionName, player, path)
 }
 
+func (s *ContentService) ListScreenshotsPage(versionName string, player string, query ScreenshotQuery) ScreenshotPage {
+	page := ScreenshotPage{Items: []ScreenshotInfo{}, Groups: []ScreenshotGroup{}}
+	if s.manager == nil {
+		return page
+	}
+	mgr := s.manager.ListScreenshotsPage(versionName, player, contentmgr.ScreenshotQuery{
+		World:  query.World,
+		Oldest: query.Oldest,
+		Offset: query.Offset,
+		Limit:  query.Limit,
+	})
+	page.Total = mgr.Total
+	for _, v := range mgr.Items {
+		page.Items = append(page.Items, toScreenshotInfo(v))
+	}
+	for _, g := range mgr.Groups {
+		page.Groups = append(page.Groups, ScreenshotGroup{World: g.World, Count: g.Count})
+	}
+	return page
+}
+
+func (s *ContentService) GetScreenshotThumbnail(path string, size int) string {
+	if s.manager == nil {
+		return ""
+	}
+	return s.manager.GetScreenshotThumbnail(path, size)
+}
+
+func toScreenshotBulkResult(r contentmgr.ScreenshotBulkResult) ScreenshotBulkResult {
+	return ScreenshotBulkResult{Done: r.Done, Paths: r.Paths, Failed: r.Failed, ErrorCode: r.ErrorCode}
+}
+
+func (s *ContentService) ExportScreenshots(versionName string, player string, paths []string, destDir string) ScreenshotBulkResult {
+	if s.manager == nil {
+		return ScreenshotBulkResult{Paths: []string{}, Failed: []string{}, ErrorCode: "ERR_INVALID_PATH"}
+	}
+	return toScreenshotBulkResult(s.manager.ExportScreenshots(versionName, player, paths, destDir))
+}
+
+func (s *ContentService) DeleteScreenshots(versionName string, player string, paths []string) ScreenshotBulkResult {
+	if s.manager == nil {
+		return ScreenshotBulkResult{Paths: []string{}, Failed: []string{}, ErrorCode: "ERR_INVALID_PATH"}
+	}
+	return toScreenshotBulkResult(s.manager.DeleteScreenshots(versionName, player, paths))
+}
+
+func (s *ContentService) FindDuplicatePacks() types.PackDuplicateReport {
+	return mcservice.FindDuplicatePacks()
+}
+
+func (s *ContentService) ConsolidateDuplicatePacks(request types.PackDuplicateConsolidateRequest) types.PackDuplicateConsolidateResult {
+	return mcservice.ConsolidateDuplicatePacks(request)
+}
+
+func (s *ContentService) ListDevPacks(versionName string) []types.DevPack {
+	return mcservice.ListDevPacks(versionName)
+}
+
+func (s *ContentService) RegisterDevPack(versionName string, sourcePath string, packType string, mode string) string {
+	return mcservice.RegisterDevPack(versionName, sourcePath, packType, mode)
+}
+
+func (s *ContentService) UnregisterDevPack(versionName string, id string) string {
+	return mcservice.UnregisterDevPack(versionName, id)
+}
+
+func (s *ContentService) StartDevPackWatch(versionName string, id string) string {
+	return mcservice.StartDevPackWatch(versionName, id)
+}
+
+func (s *ContentService) StopDevPackWatch(versionName string, id string) string {
+	return mcservice.StopDevPackWatch(versionName, id)
+}
+
+func (s *ContentService) SyncDevPack(versionName string, id string) types.DevPackSyncEvent {
+	return mcservice.SyncDevPack(versionName, id)
+}
+
+func (s *ContentService) ListLibraryPacks() []types.LibraryPack {
+	return mcservice.ListLibraryPacks()
+}
+
+func (s *ContentService) AddPackToLibrary(versionName string, packPath string, replace bool) string {
+	return mcservice.AddPackToLibrary(versionName, packPath, replace)
+}
+
+func (s *ContentService) AttachLibraryPack(versionName string, libraryPath string) string {
+	return mcservice.AttachLibraryPack(versionName, libraryPath)
+}
+
+func (s *ContentService) DetachLibraryPack(versionName string, linkPath string) string {
+	return mcservice.DetachLibraryPack(versionName, linkPath)
+}
+
+func (s *ContentService) ListLibraryLinks(versionName string) []types.LibraryLink {
+	return mcservice.ListLibraryLinks(versionName)
+}
+
+func (s *ContentService) RemoveBrokenLibraryLinks(versionName string) int {
+	return mcservice.RemoveBrokenLibraryLinks(versionName)
+}
+
+func (s *ContentService) DeleteLibraryPack(libraryPath string, force bool) string {
+	return mcservice.DeleteLibraryPack(libraryPath, force)
+}
+
 type ModsService struct{}
 
 func NewModsService(_ *Minecraft) *ModsService {
@@ -259,9 +259,38 @@ func (s *VersionService) LaunchVersionByName(name string) string {
 	if errCode := versionlaunch.ValidateLaunchName(name); errCode != "" {
 		return errCode
 	}
+	if errCode := mcservice.WorldCompatErrorCode(mcservice.CheckPinnedWorld(name)); errCode != "" {
+		return errCode
+	}
+	mcservice.LogPendingOptionProfile(mcservice.ApplyPendingOptionProfile(name))
 	return s.launcher.Launch(s.launchContext(), name, true)
 }
 
+// LaunchVersionConfirmed launches a version after the user accepted the compatibility warning for its
+// pinned world, backing that world up first when backup is set. force skips the already-running check
+// like LaunchVersionByNameForce.
+func (s *VersionService) LaunchVersionConfirmed(name string, backup bool, force bool) types.WorldCompatResult {
+	if s.launcher == nil {
+		return types.WorldCompatResult{ErrorCode: "ERR_LAUNCH_GAME"}
+	}
+	if errCode := versionlaunch.ValidateLaunchName(name); errCode != "" {
+		return types.WorldCompatResult{ErrorCode: errCode}
+	}
+	res := mcservice.ConfirmPinnedWorld(name, backup)
+	if res.ErrorCode == "ERR_BACKUP_FAILED" {
+		return res
+	}
+	mcservice.LogPendingOptionProfile(mcservice.ApplyPendingOptionProfile(name))
+	res.ErrorCode = s.launcher.Launch(s.launchContext(), name, !force)
+	return res
+}
+
+// TakeWorldCompatLaunchPrompt returns the pending warning of a shortcut or single-instance launch that
+// its pinned world stopped, so a freshly loaded window can still show it.
+func (s *VersionService) TakeWorldCompatLaunchPrompt() types.WorldCompatLaunchPrompt {
+	return mcservice.TakeWorldCompatLaunchPrompt()
+}
+
 func (s *VersionService) LaunchVersionByNameForce(name string) string {
 	if s.launcher == nil {
 		return "ERR_LAUNCH_GAME"
@@ -269,6 +298,10 @@ func (s *VersionService) LaunchVersionByNameForce(name string) string {
 	if errCode := versionlaunch.ValidateLaunchName(name); errCode != "" {
 		return errCode
 	}
+	if errCode := mcservice.WorldCompatErrorCode(mcservice.CheckPinnedWorld(name)); errCode != "" {
+		return errCode
+	}
+	mcservice.LogPendingOptionProfile(mcservice.ApplyPendingOptionProfile(name))
 	return s.launcher.Launch(s.launchContext(), name, false)
 }
 